@@ -15,13 +15,16 @@ import (
 	"github.com/qdm12/ddns-updater/internal/backup"
 	"github.com/qdm12/ddns-updater/internal/config"
 	"github.com/qdm12/ddns-updater/internal/data"
+	"github.com/qdm12/ddns-updater/internal/docker"
+	"github.com/qdm12/ddns-updater/internal/failover"
 	"github.com/qdm12/ddns-updater/internal/health"
 	"github.com/qdm12/ddns-updater/internal/healthchecksio"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/mqtt"
 	"github.com/qdm12/ddns-updater/internal/noop"
 	jsonparams "github.com/qdm12/ddns-updater/internal/params"
 	persistence "github.com/qdm12/ddns-updater/internal/persistence/json"
-	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
 	recordslib "github.com/qdm12/ddns-updater/internal/records"
 	"github.com/qdm12/ddns-updater/internal/resolver"
 	"github.com/qdm12/ddns-updater/internal/server"
@@ -29,6 +32,7 @@ import (
 	"github.com/qdm12/ddns-updater/internal/system"
 	"github.com/qdm12/ddns-updater/internal/update"
 	"github.com/qdm12/ddns-updater/pkg/publicip"
+	"github.com/qdm12/ddns-updater/pkg/publicip/dns"
 	"github.com/qdm12/goservices"
 	"github.com/qdm12/gosettings/reader"
 	"github.com/qdm12/gosplash"
@@ -139,6 +143,8 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		Addresses:    config.Shoutrrr.Addresses,
 		DefaultTitle: config.Shoutrrr.DefaultTitle,
 		Logger:       logger.New(log.SetComponent("shoutrrr")),
+		QuietWindows: config.Update.MaintenanceWindows,
+		TimeNow:      timeNow,
 	}
 	shoutrrrClient, err := shoutrrr.New(shoutrrrSettings)
 	if err != nil {
@@ -152,7 +158,7 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 	}
 
 	jsonReader := jsonparams.NewReader(logger)
-	providers, warnings, err := jsonReader.JSONProviders(*config.Paths.Config)
+	records, uplinks, warnings, err := jsonReader.JSONRecords(*config.Paths.Config)
 	for _, w := range warnings {
 		logger.Warn(w)
 		shoutrrrClient.Notify(w)
@@ -162,7 +168,7 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		return err
 	}
 
-	logProvidersCount(len(providers), logger)
+	logProvidersCount(len(records), logger)
 
 	client := &http.Client{Timeout: config.Client.Timeout}
 	defer client.CloseIdleConnections()
@@ -172,7 +178,7 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		logger.Warn(err.Error())
 	}
 
-	records, err := readRecords(providers, persistentDB, logger, shoutrrrClient)
+	err = readHistories(records, persistentDB, logger, shoutrrrClient)
 	if err != nil {
 		return fmt.Errorf("reading records: %w", err)
 	}
@@ -194,6 +200,21 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		return err
 	}
 
+	uplinkClients := make(map[string]*http.Client, len(uplinks))
+	uplinkIPGetters := make(map[string]update.PublicIPFetcher, len(uplinks))
+	for _, uplink := range uplinks {
+		uplinkClient := uplink.HTTPClient(config.Client.Timeout)
+		defer uplinkClient.CloseIdleConnections()
+		uplinkClients[uplink.Name] = uplinkClient
+
+		httpSettings.Client = uplinkClient
+		dnsSettings.Options = append(config.PubIP.ToDNSPOptions(), dns.SetDialer(uplink.Dialer()))
+		uplinkIPGetters[uplink.Name], err = publicip.NewFetcher(dnsSettings, httpSettings)
+		if err != nil {
+			return fmt.Errorf("creating public IP fetcher for uplink %s: %w", uplink, err)
+		}
+	}
+
 	resolverSettings := resolver.Settings{
 		Address: config.Resolver.Address,
 		Timeout: config.Resolver.Timeout,
@@ -207,20 +228,31 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 		*config.Health.HealthchecksioUUID)
 
 	debugEnabled := config.Logger.Level == log.LevelDebug.String()
-	updater := update.NewUpdater(db, client, shoutrrrClient, logger, timeNow, debugEnabled)
-	updaterService := update.NewService(db, updater, ipGetter, config.Update.Period,
-		config.Update.Cooldown, logger, resolver, timeNow, hioClient)
+	rateLimiter := ratelimit.NewRegistry(config.Update.RateLimit, timeNow)
+	updater := update.NewUpdater(db, client, uplinkClients, rateLimiter, shoutrrrClient,
+		logger, timeNow, debugEnabled)
+	healthChecker := failover.NewChecker(client)
+	updaterService := update.NewService(db, updater, ipGetter, uplinkIPGetters, healthChecker,
+		config.Update.Period, config.Update.Cooldown, *config.Update.ReconcilePeriod,
+		*config.Update.Adaptive, config.Update.ReconnectWindow, config.Update.MaintenanceWindows,
+		logger, resolver, timeNow, hioClient, shoutrrrClient)
 
 	healthServer, err := createHealthServer(db, resolver, logger, *config.Health.ServerAddress)
 	if err != nil {
 		return fmt.Errorf("creating health server: %w", err)
 	}
 
-	server, err := createServer(ctx, config.Server, logger, db, updaterService)
+	server, err := createServer(ctx, config.Server, logger, db, updaterService, rateLimiter)
 	if err != nil {
 		return fmt.Errorf("creating server: %w", err)
 	}
 
+	summaryService := createSummaryService(config.Shoutrrr, db, shoutrrrClient, timeNow)
+
+	mqttService := createMQTTService(config.MQTT, db, updaterService, logger, buildInfo)
+
+	dockerService := createDockerService(config.Docker, updaterService, persistentDB, logger)
+
 	var backupService goservices.Service
 	backupLogger := logger.New(log.SetComponent("backup"))
 	backupService = backup.New(*config.Backup.Period, *config.Paths.DataDir,
@@ -231,8 +263,14 @@ func _main(ctx context.Context, reader *reader.Reader, args []string, logger log
 	}
 
 	servicesSequence, err := goservices.NewSequence(goservices.SequenceSettings{
-		ServicesStart: []goservices.Service{db, updaterService, healthServer, server, backupService},
-		ServicesStop:  []goservices.Service{server, healthServer, updaterService, backupService, db},
+		ServicesStart: []goservices.Service{
+			db, updaterService, healthServer, server,
+			backupService, summaryService, mqttService, dockerService,
+		},
+		ServicesStop: []goservices.Service{
+			dockerService, mqttService, server, healthServer, updaterService,
+			backupService, summaryService, db,
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("creating services sequence: %w", err)
@@ -321,24 +359,37 @@ func logProvidersCount(providersCount int, logger log.LeveledLogger) {
 	}
 }
 
-func readRecords(providers []provider.Provider, persistentDB *persistence.Database,
-	logger log.LoggerInterface, shoutrrrClient *shoutrrr.Client) (
-	records []recordslib.Record, err error,
-) {
-	records = make([]recordslib.Record, len(providers))
-	for i, provider := range providers {
+func readHistories(records []recordslib.Record, persistentDB *persistence.Database,
+	logger log.LoggerInterface, shoutrrrClient *shoutrrr.Client,
+) (err error) {
+	for i, record := range records {
+		if record.Settings.RecordType != "" {
+			continue // content records history is not persisted
+		}
+		provider := record.Provider
 		logger.Info("Reading history from database: domain " +
 			provider.Domain() + " owner " + provider.Owner() +
 			" " + provider.IPVersion().String())
-		events, err := persistentDB.GetEvents(provider.Domain(),
+		records[i].History, err = persistentDB.GetEvents(provider.Domain(),
 			provider.Owner(), provider.IPVersion())
 		if err != nil {
 			shoutrrrClient.Notify(err.Error())
-			return nil, err
+			return err
+		}
+		records[i].Pin, err = persistentDB.GetPin(provider.Domain(),
+			provider.Owner(), provider.String())
+		if err != nil {
+			shoutrrrClient.Notify(err.Error())
+			return err
+		}
+		records[i].FailedOver, err = persistentDB.GetFailedOver(provider.Domain(),
+			provider.Owner(), provider.String())
+		if err != nil {
+			shoutrrrClient.Notify(err.Error())
+			return err
 		}
-		records[i] = recordslib.New(provider, events)
 	}
-	return records, nil
+	return nil
 }
 
 func exitHealthchecksio(hioClient *healthchecksio.Client,
@@ -369,13 +420,58 @@ func createHealthServer(db health.AllSelecter, resolver health.LookupIPer,
 //nolint:ireturn
 func createServer(ctx context.Context, config config.Server,
 	logger log.LoggerInterface, db server.Database,
-	updaterService server.UpdateForcer) (
+	updaterService *update.Service, rateLimiter server.RateLimitReporter) (
 	service goservices.Service, err error,
 ) {
 	if !*config.Enabled {
 		return noop.New("server"), nil
 	}
 	serverLogger := logger.New(log.SetComponent("http server"))
-	return server.New(ctx, config.ListeningAddress, config.RootURL,
-		db, serverLogger, updaterService)
+	return server.New(ctx, config.ListeningAddress, config.RootURL, config.APIToken,
+		db, serverLogger, updaterService, updaterService, updaterService, rateLimiter)
+}
+
+//nolint:ireturn
+func createSummaryService(config config.Shoutrrr, db shoutrrr.Database,
+	shoutrrrClient *shoutrrr.Client, timeNow func() time.Time,
+) (service goservices.Service) {
+	period := config.SummaryPeriod()
+	if len(config.Addresses) == 0 || period == 0 {
+		return noop.New("notifications summary")
+	}
+	return shoutrrr.NewSummary(period, db, shoutrrrClient, timeNow)
+}
+
+//nolint:ireturn
+func createMQTTService(config config.MQTT, db mqtt.Database,
+	updaterService *update.Service, logger log.LoggerInterface,
+	buildInfo models.BuildInformation,
+) (service goservices.Service) {
+	if *config.Address == "" {
+		return noop.New("mqtt")
+	}
+	settings := mqtt.Settings{
+		Address:         *config.Address,
+		Username:        config.Username,
+		Password:        config.Password,
+		ClientID:        config.ClientID,
+		TopicPrefix:     config.TopicPrefix,
+		DiscoveryPrefix: config.DiscoveryPrefix,
+		Period:          config.Period,
+		Version:         buildInfo.Version,
+	}
+	mqttLogger := logger.New(log.SetComponent("mqtt"))
+	return mqtt.New(settings, db, updaterService, updaterService, mqttLogger)
+}
+
+//nolint:ireturn
+func createDockerService(config config.Docker, updaterService *update.Service,
+	persistentDB *persistence.Database, logger log.LoggerInterface,
+) (service goservices.Service) {
+	if *config.Socket == "" {
+		return noop.New("docker discovery")
+	}
+	dockerLogger := logger.New(log.SetComponent("docker"))
+	return docker.New(*config.Socket, config.LabelPrefix, updaterService,
+		persistentDB, dockerLogger)
 }
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+type Docker struct {
+	// Socket is the Docker Engine API unix socket path,
+	// and defaults to the empty string, meaning records
+	// discovery from container labels is disabled.
+	Socket      *string
+	LabelPrefix string
+}
+
+func (d *Docker) setDefaults() {
+	d.Socket = gosettings.DefaultPointer(d.Socket, "")
+	d.LabelPrefix = gosettings.DefaultComparable(d.LabelPrefix, "ddns")
+}
+
+func (d Docker) Validate() (err error) {
+	return nil
+}
+
+func (d Docker) String() string {
+	return d.toLinesNode().String()
+}
+
+func (d Docker) toLinesNode() *gotree.Node {
+	if *d.Socket == "" {
+		return nil // no socket means Docker discovery is disabled
+	}
+	node := gotree.New("Docker labels discovery")
+	node.Appendf("Socket: %s", *d.Socket)
+	node.Appendf("Label prefix: %s", d.LabelPrefix)
+	return node
+}
+
+func (d *Docker) read(r *reader.Reader) {
+	d.Socket = r.Get("DOCKER_SOCKET", reader.ForceLowercase(false))
+	d.LabelPrefix = r.String("DOCKER_LABEL_PREFIX", reader.ForceLowercase(false))
+}
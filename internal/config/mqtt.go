@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
+	"github.com/qdm12/gotree"
+)
+
+type MQTT struct {
+	// Address is the MQTT broker address, for example
+	// tcp://192.168.1.2:1883. It defaults to the empty
+	// string, meaning the MQTT integration is disabled.
+	Address         *string
+	Username        string
+	Password        string
+	ClientID        string
+	TopicPrefix     string
+	DiscoveryPrefix string
+	Period          time.Duration
+}
+
+func (m *MQTT) setDefaults() {
+	m.Address = gosettings.DefaultPointer(m.Address, "")
+	m.ClientID = gosettings.DefaultComparable(m.ClientID, "ddns-updater")
+	m.TopicPrefix = gosettings.DefaultComparable(m.TopicPrefix, "ddns-updater")
+	m.DiscoveryPrefix = gosettings.DefaultComparable(m.DiscoveryPrefix, "homeassistant")
+	const defaultPeriod = 10 * time.Second
+	m.Period = gosettings.DefaultComparable(m.Period, defaultPeriod)
+}
+
+var ErrMQTTPeriodTooSmall = errors.New("MQTT publish period is too small")
+
+func (m MQTT) Validate() (err error) {
+	if *m.Address == "" {
+		return nil
+	}
+
+	address, err := url.Parse(*m.Address)
+	if err != nil {
+		return fmt.Errorf("address: %w", err)
+	}
+
+	err = validate.IsOneOf(address.Scheme, "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss")
+	if err != nil {
+		return fmt.Errorf("address scheme: %w", err)
+	}
+
+	const minPeriod = time.Second
+	if m.Period < minPeriod {
+		return fmt.Errorf("%w: %s must be at least %s",
+			ErrMQTTPeriodTooSmall, m.Period, minPeriod)
+	}
+
+	return nil
+}
+
+func (m MQTT) String() string {
+	return m.toLinesNode().String()
+}
+
+func (m MQTT) toLinesNode() *gotree.Node {
+	if *m.Address == "" {
+		return nil // no address means MQTT is disabled
+	}
+
+	node := gotree.New("MQTT")
+	node.Appendf("Broker address: %s", *m.Address)
+	if m.Username != "" {
+		node.Appendf("Username: %s", m.Username)
+	}
+	if m.Password != "" {
+		node.Appendf("Password: [set]")
+	}
+	node.Appendf("Client ID: %s", m.ClientID)
+	node.Appendf("Topic prefix: %s", m.TopicPrefix)
+	node.Appendf("Home Assistant discovery prefix: %s", m.DiscoveryPrefix)
+	node.Appendf("Publish period: %s", m.Period)
+	return node
+}
+
+func (m *MQTT) read(r *reader.Reader) (err error) {
+	m.Address = r.Get("MQTT_ADDRESS", reader.ForceLowercase(false))
+	m.Username = r.String("MQTT_USERNAME", reader.ForceLowercase(false))
+	m.Password = r.String("MQTT_PASSWORD", reader.ForceLowercase(false))
+	m.ClientID = r.String("MQTT_CLIENT_ID", reader.ForceLowercase(false))
+	m.TopicPrefix = r.String("MQTT_TOPIC_PREFIX", reader.ForceLowercase(false))
+	m.DiscoveryPrefix = r.String("MQTT_DISCOVERY_PREFIX", reader.ForceLowercase(false))
+	m.Period, err = r.Duration("MQTT_PERIOD")
+	return err
+}
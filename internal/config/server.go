@@ -14,6 +14,9 @@ type Server struct {
 	Enabled          *bool
 	ListeningAddress string
 	RootURL          string
+	// APIToken is the token required by the pin API,
+	// and no token is required if it is empty.
+	APIToken string
 }
 
 func (s *Server) setDefaults() {
@@ -44,19 +47,23 @@ func (s Server) toLinesNode() *gotree.Node {
 	node := gotree.New("Server")
 	node.Appendf("Listening address: %s", s.ListeningAddress)
 	node.Appendf("Root URL: %s", s.RootURL)
+	if s.APIToken != "" {
+		node.Appendf("API token: [set]")
+	}
 	return node
 }
 
-func (s *Server) read(reader *reader.Reader, warner Warner) (err error) {
-	s.Enabled, err = reader.BoolPtr("SERVER_ENABLED")
+func (s *Server) read(r *reader.Reader, warner Warner) (err error) {
+	s.Enabled, err = r.BoolPtr("SERVER_ENABLED")
 	if err != nil {
 		return err
 	}
 
-	s.RootURL = reader.String("ROOT_URL")
+	s.RootURL = r.String("ROOT_URL")
+	s.APIToken = r.String("SERVER_API_TOKEN", reader.ForceLowercase(false))
 
 	// Retro-compatibility
-	port, err := reader.Uint16Ptr("LISTENING_PORT") // TODO change to address
+	port, err := r.Uint16Ptr("LISTENING_PORT") // TODO change to address
 	if err != nil {
 		handleDeprecated(warner, "LISTENING_PORT", "LISTENING_ADDRESS")
 		return err
@@ -64,7 +71,7 @@ func (s *Server) read(reader *reader.Reader, warner Warner) (err error) {
 		s.ListeningAddress = fmt.Sprintf(":%d", *port)
 	}
 
-	s.ListeningAddress = reader.String("LISTENING_ADDRESS")
+	s.ListeningAddress = r.String("LISTENING_ADDRESS")
 
 	return err
 }
@@ -18,6 +18,8 @@ type Config struct {
 	Backup   Backup
 	Logger   Logger
 	Shoutrrr Shoutrrr
+	MQTT     MQTT
+	Docker   Docker
 }
 
 func (c *Config) SetDefaults() {
@@ -31,6 +33,8 @@ func (c *Config) SetDefaults() {
 	c.Backup.setDefaults()
 	c.Logger.setDefaults()
 	c.Shoutrrr.setDefaults()
+	c.MQTT.setDefaults()
+	c.Docker.setDefaults()
 }
 
 func (c Config) Validate() (err error) {
@@ -48,6 +52,8 @@ func (c Config) Validate() (err error) {
 		"backup":    &c.Backup,
 		"logger":    &c.Logger,
 		"shoutrrr":  &c.Shoutrrr,
+		"mqtt":      &c.MQTT,
+		"docker":    &c.Docker,
 	}
 
 	for name, v := range toValidate {
@@ -76,6 +82,8 @@ func (c Config) toLinesNode() *gotree.Node {
 	node.AppendNode(c.Backup.toLinesNode())
 	node.AppendNode(c.Logger.toLinesNode())
 	node.AppendNode(c.Shoutrrr.ToLinesNode())
+	node.AppendNode(c.MQTT.toLinesNode())
+	node.AppendNode(c.Docker.toLinesNode())
 	return node
 }
 
@@ -125,5 +133,12 @@ func (c *Config) Read(reader *reader.Reader,
 		return fmt.Errorf("reading shoutrrr settings: %w", err)
 	}
 
+	err = c.MQTT.read(reader)
+	if err != nil {
+		return fmt.Errorf("reading MQTT settings: %w", err)
+	}
+
+	c.Docker.read(reader)
+
 	return nil
 }
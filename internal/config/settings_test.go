@@ -20,7 +20,10 @@ func Test_Settings_String(t *testing.T) {
 |   └── Timeout: 20s
 ├── Update
 |   ├── Period: 5m0s
-|   └── Cooldown: 5m0s
+|   ├── Cooldown: 5m0s
+|   ├── Reconciliation: disabled
+|   ├── Adaptive scheduling: no
+|   └── Provider account rate limit: none
 ├── Public IP fetching
 |   ├── HTTP enabled: yes
 |   ├── HTTP IP providers
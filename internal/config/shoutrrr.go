@@ -4,21 +4,27 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"time"
 
 	"github.com/containrrr/shoutrrr"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
 	"github.com/qdm12/gotree"
 )
 
 type Shoutrrr struct {
 	Addresses    []string
 	DefaultTitle string
+	// Summary is the frequency of the records summary
+	// notification, and can be "disabled", "daily" or "weekly".
+	Summary string
 }
 
 func (s *Shoutrrr) setDefaults() {
 	s.Addresses = gosettings.DefaultSlice(s.Addresses, []string{})
 	s.DefaultTitle = gosettings.DefaultComparable(s.DefaultTitle, "DDNS Updater")
+	s.Summary = gosettings.DefaultComparable(s.Summary, "disabled")
 }
 
 func (s Shoutrrr) Validate() (err error) {
@@ -26,9 +32,29 @@ func (s Shoutrrr) Validate() (err error) {
 	if err != nil {
 		return fmt.Errorf("shoutrrr addresses: %w", err)
 	}
+
+	err = validate.IsOneOf(s.Summary, "disabled", "daily", "weekly")
+	if err != nil {
+		return fmt.Errorf("summary: %w", err)
+	}
 	return nil
 }
 
+// SummaryPeriod returns the period between summary notifications,
+// or 0 if the summary is disabled.
+func (s Shoutrrr) SummaryPeriod() (period time.Duration) {
+	const day = 24 * time.Hour
+	switch s.Summary {
+	case "daily":
+		return day
+	case "weekly":
+		const daysInWeek = 7
+		return daysInWeek * day
+	default:
+		return 0
+	}
+}
+
 func (s Shoutrrr) String() string {
 	return s.ToLinesNode().String()
 }
@@ -40,6 +66,7 @@ func (s Shoutrrr) ToLinesNode() *gotree.Node {
 
 	node := gotree.New("Shoutrrr")
 	node.Appendf("Default title: %s", s.DefaultTitle)
+	node.Appendf("Summary: %s", s.Summary)
 
 	childNode := node.Appendf("Addresses")
 	for _, address := range s.Addresses {
@@ -74,6 +101,7 @@ func (s *Shoutrrr) read(r *reader.Reader, warner Warner) (err error) {
 	}
 
 	s.DefaultTitle = r.String("SHOUTRRR_DEFAULT_TITLE", reader.ForceLowercase(false))
+	s.Summary = r.String("SHOUTRRR_SUMMARY")
 	return nil
 }
 
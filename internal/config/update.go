@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
+	"github.com/qdm12/ddns-updater/internal/schedule"
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
 	"github.com/qdm12/gotree"
@@ -12,6 +15,26 @@ import (
 type Update struct {
 	Period   time.Duration
 	Cooldown time.Duration
+	// ReconcilePeriod is the period to read records from the provider
+	// APIs supporting it, to repair records modified outside of the program.
+	// It cannot be nil in the internal state, and is disabled if set to 0.
+	ReconcilePeriod *time.Duration
+	// Adaptive is true to check records following the global period
+	// more often after IP address changes or failures, and less often
+	// when their IP address is stable. It cannot be nil in the internal state.
+	Adaptive *bool
+	// ReconnectWindow is the daily window during which the ISP forces
+	// a reconnection, right after which records following the global
+	// period are checked. It is nil if unset.
+	ReconnectWindow *schedule.Window
+	// MaintenanceWindows are the windows during which updates of
+	// non-critical records are deferred and notifications are held.
+	// It is nil if unset.
+	MaintenanceWindows *schedule.CronWindows
+	// RateLimit is the default rate limit of requests sent to each
+	// provider account. It is nil if unset, in which case requests
+	// are only limited by the provider API responses headers.
+	RateLimit *ratelimit.Limit
 }
 
 func (u *Update) setDefaults() {
@@ -19,6 +42,8 @@ func (u *Update) setDefaults() {
 	u.Period = gosettings.DefaultComparable(u.Period, defaultPeriod)
 	const defaultCooldown = 5 * time.Minute
 	u.Cooldown = gosettings.DefaultComparable(u.Cooldown, defaultCooldown)
+	u.ReconcilePeriod = gosettings.DefaultPointer(u.ReconcilePeriod, 0)
+	u.Adaptive = gosettings.DefaultPointer(u.Adaptive, false)
 }
 
 func (u Update) Validate() (err error) {
@@ -33,6 +58,23 @@ func (u Update) toLinesNode() *gotree.Node {
 	node := gotree.New("Update")
 	node.Appendf("Period: %s", u.Period)
 	node.Appendf("Cooldown: %s", u.Cooldown)
+	if *u.ReconcilePeriod == 0 {
+		node.Appendf("Reconciliation: disabled")
+	} else {
+		node.Appendf("Reconciliation period: %s", *u.ReconcilePeriod)
+	}
+	node.Appendf("Adaptive scheduling: %s", gosettings.BoolToYesNo(u.Adaptive))
+	if u.ReconnectWindow != nil {
+		node.Appendf("Reconnect window: %s", u.ReconnectWindow)
+	}
+	if u.MaintenanceWindows != nil {
+		node.Appendf("Maintenance windows: %s", u.MaintenanceWindows)
+	}
+	if u.RateLimit == nil {
+		node.Appendf("Provider account rate limit: none")
+	} else {
+		node.Appendf("Provider account rate limit: %s", u.RateLimit)
+	}
 	return node
 }
 
@@ -43,7 +85,46 @@ func (u *Update) read(reader *reader.Reader, warner Warner) (err error) {
 	}
 
 	u.Cooldown, err = reader.Duration("UPDATE_COOLDOWN_PERIOD")
-	return err
+	if err != nil {
+		return err
+	}
+
+	u.ReconcilePeriod, err = reader.DurationPtr("UPDATE_RECONCILE_PERIOD")
+	if err != nil {
+		return err
+	}
+
+	u.Adaptive, err = reader.BoolPtr("UPDATE_ADAPTIVE")
+	if err != nil {
+		return err
+	}
+
+	reconnectWindow := reader.String("UPDATE_RECONNECT_WINDOW")
+	if reconnectWindow != "" {
+		window, err := schedule.ParseWindow(reconnectWindow)
+		if err != nil {
+			return fmt.Errorf("parsing reconnect window: %w", err)
+		}
+		u.ReconnectWindow = &window
+	}
+
+	maintenanceWindows := reader.String("MAINTENANCE_WINDOWS")
+	if maintenanceWindows != "" {
+		u.MaintenanceWindows, err = schedule.ParseCronWindows(maintenanceWindows)
+		if err != nil {
+			return fmt.Errorf("parsing maintenance windows: %w", err)
+		}
+	}
+
+	rateLimit := reader.String("UPDATE_RATE_LIMIT")
+	if rateLimit != "" {
+		limit, err := ratelimit.ParseLimit(rateLimit)
+		if err != nil {
+			return fmt.Errorf("parsing rate limit: %w", err)
+		}
+		u.RateLimit = &limit
+	}
+	return nil
 }
 
 func readUpdatePeriod(r *reader.Reader, warner Warner) (period time.Duration, err error) {
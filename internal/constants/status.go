@@ -3,9 +3,12 @@ package constants
 import "github.com/qdm12/ddns-updater/internal/models"
 
 const (
-	FAIL     models.Status = "failure"
-	SUCCESS  models.Status = "success"
-	UPTODATE models.Status = "up to date"
-	UPDATING models.Status = "updating"
-	UNSET    models.Status = "unset"
+	FAIL       models.Status = "failure"
+	SUCCESS    models.Status = "success"
+	UPTODATE   models.Status = "up to date"
+	UPDATING   models.Status = "updating"
+	UNSET      models.Status = "unset"
+	DRIFTED    models.Status = "externally modified"
+	FAILEDOVER models.Status = "failed over"
+	PINNED     models.Status = "pinned"
 )
@@ -9,6 +9,9 @@ import (
 
 type Database struct {
 	data []records.Record
+	// staticCount is the number of records from the configuration
+	// file, which are followed by dynamically discovered records.
+	staticCount int
 	sync.RWMutex
 	persistentDB PersistentDatabase
 }
@@ -17,6 +20,7 @@ type Database struct {
 func NewDatabase(data []records.Record, persistentDB PersistentDatabase) *Database {
 	return &Database{
 		data:         data,
+		staticCount:  len(data),
 		persistentDB: persistentDB,
 	}
 }
@@ -0,0 +1,33 @@
+package data
+
+import (
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// SetDynamic replaces the dynamically discovered records with the
+// records given. Records already present, identified by their provider
+// string, keep their current status and history but use the new provider
+// and settings. Note the identifiers of dynamic records may change, so this
+// should not be called concurrently with record updates.
+func (db *Database) SetDynamic(dynamicRecords []records.Record) {
+	db.Lock()
+	defer db.Unlock()
+
+	existing := make(map[string]records.Record, len(db.data)-db.staticCount)
+	for _, record := range db.data[db.staticCount:] {
+		existing[record.Provider.String()] = record
+	}
+
+	data := make([]records.Record, db.staticCount, db.staticCount+len(dynamicRecords))
+	copy(data, db.data[:db.staticCount])
+	for _, record := range dynamicRecords {
+		existingRecord, ok := existing[record.Provider.String()]
+		if ok {
+			existingRecord.Provider = record.Provider
+			existingRecord.Settings = record.Settings
+			record = existingRecord
+		}
+		data = append(data, record)
+	}
+	db.data = data
+}
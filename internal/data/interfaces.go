@@ -3,9 +3,13 @@ package data
 import (
 	"net/netip"
 	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
 )
 
 type PersistentDatabase interface {
 	Close() error
-	StoreNewIP(domain, owner string, ip netip.Addr, t time.Time) (err error)
+	StoreNewIP(domain, owner string, ip netip.Addr, ips []netip.Addr, t time.Time) (err error)
+	SetPin(domain, owner, recordKey string, pin *models.Pin) (err error)
+	SetFailedOver(domain, owner, recordKey string, failedOver bool) (err error)
 }
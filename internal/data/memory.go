@@ -3,6 +3,7 @@ package data
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/qdm12/ddns-updater/internal/records"
 )
@@ -18,8 +19,10 @@ func (db *Database) Select(id uint) (record records.Record, err error) {
 	return db.data[id], nil
 }
 
+// SelectAll returns a copy of all the records, which is not
+// modified by subsequent updates of the database.
 func (db *Database) SelectAll() (records []records.Record) {
 	db.RLock()
 	defer db.RUnlock()
-	return db.data
+	return slices.Clone(db.data)
 }
@@ -13,16 +13,35 @@ func (db *Database) Update(id uint, record records.Record) (err error) {
 		return fmt.Errorf("%w: for id %d", ErrRecordNotFound, id)
 	}
 	currentCount := len(db.data[id].History)
-	newCount := len(record.History)
+	previousPin := db.data[id].Pin
+	previousFailedOver := db.data[id].FailedOver
 	db.data[id] = record
-	// new IP address added
-	if newCount > currentCount {
-		if err := db.persistentDB.StoreNewIP(
-			record.Provider.Domain(),
-			record.Provider.Owner(),
-			record.History.GetCurrentIP(),
-			record.History.GetSuccessTime(),
-		); err != nil {
+	if record.Settings.RecordType != "" {
+		// the history of content records is not persisted, since it
+		// would mix with the history of A and AAAA records of the
+		// same domain and owner.
+		return nil
+	}
+	// new IP addresses added, which can be more than one for dual-stack records
+	for i := currentCount; i < len(record.History); i++ {
+		event := record.History[i]
+		err = db.persistentDB.StoreNewIP(record.Provider.Domain(),
+			record.Provider.Owner(), event.IP, event.IPs, event.Time)
+		if err != nil {
+			return err
+		}
+	}
+	if previousPin != record.Pin {
+		err = db.persistentDB.SetPin(record.Provider.Domain(),
+			record.Provider.Owner(), record.Provider.String(), record.Pin)
+		if err != nil {
+			return err
+		}
+	}
+	if previousFailedOver != record.FailedOver {
+		err = db.persistentDB.SetFailedOver(record.Provider.Domain(),
+			record.Provider.Owner(), record.Provider.String(), record.FailedOver)
+		if err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,117 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// client is a minimal Docker Engine API client over a unix socket.
+type client struct {
+	httpClient *http.Client
+}
+
+func newClient(socketPath string) *client {
+	dialer := &net.Dialer{}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
+		},
+	}
+	return &client{
+		// No timeout is set since the events endpoint is streaming,
+		// contexts are used instead.
+		httpClient: &http.Client{Transport: transport},
+	}
+}
+
+// The host is ignored since the connection is over the unix socket.
+const baseURL = "http://docker"
+
+type container struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Labels map[string]string `json:"Labels"`
+}
+
+var ErrHTTPStatusNotValid = errors.New("HTTP status is not valid")
+
+func (c *client) listContainers(ctx context.Context) (containers []container, err error) {
+	response, err := c.get(ctx, "/containers/json", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	err = json.NewDecoder(response.Body).Decode(&containers)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+	return containers, nil
+}
+
+type event struct {
+	Type   string `json:"Type"`
+	Action string `json:"Action"`
+	Actor  struct {
+		ID string `json:"ID"`
+	} `json:"Actor"`
+}
+
+// watchEvents calls onSubscribed once the events stream is opened, and
+// then onEvent for each container start and stop event, until the
+// context is canceled or the events stream fails.
+func (c *client) watchEvents(ctx context.Context, onSubscribed func(),
+	onEvent func(event event),
+) (err error) {
+	filters := `{"type":["container"],"event":["start","die","destroy"]}`
+	query := url.Values{"filters": []string{filters}}
+	response, err := c.get(ctx, "/events", query)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	onSubscribed()
+
+	decoder := json.NewDecoder(response.Body)
+	for {
+		var e event
+		err = decoder.Decode(&e)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("events stream closed: %w", err)
+			}
+			return fmt.Errorf("json decoding event: %w", err)
+		}
+		onEvent(e)
+	}
+}
+
+func (c *client) get(ctx context.Context, path string, query url.Values) (
+	response *http.Response, err error,
+) {
+	u := baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+
+	response, err = c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrHTTPStatusNotValid, response.Status)
+	}
+	return response, nil
+}
@@ -0,0 +1,27 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type RecordsSetter interface {
+	SetDynamicRecords(ctx context.Context, records []records.Record) (err error)
+}
+
+type EventsGetter interface {
+	GetEvents(domain, owner string, ipVersion ipversion.IPVersion) (
+		events []models.HistoryEvent, err error)
+	GetPin(domain, owner, recordKey string) (pin *models.Pin, err error)
+	GetFailedOver(domain, owner, recordKey string) (failedOver bool, err error)
+}
+
+type Logger interface {
+	Debug(s string)
+	Info(s string)
+	Warn(s string)
+	Error(s string)
+}
@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// labelsToSettings converts the container labels starting with the
+// prefix given to a settings JSON object, as found in config.json.
+// Label values `true`, `false` and numbers are converted to JSON
+// booleans and numbers, unless they are enclosed in double quotes.
+// The ok return value is false if the container does not have
+// both the domain and provider labels, or if its enable label is false.
+func labelsToSettings(labels map[string]string, prefix string) (
+	settings json.RawMessage, ok bool, err error,
+) {
+	prefix += "."
+	fields := make(map[string]json.RawMessage)
+	for label, value := range labels {
+		key, found := strings.CutPrefix(label, prefix)
+		if !found || key == "" {
+			continue
+		}
+		if key == "enable" {
+			if value == "false" {
+				return nil, false, nil
+			}
+			continue
+		}
+		fields[key], err = labelValueToJSON(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("label %s: %w", label, err)
+		}
+	}
+
+	_, hasDomain := fields["domain"]
+	_, hasProvider := fields["provider"]
+	if !hasDomain || !hasProvider {
+		return nil, false, nil
+	}
+
+	settings, err = json.Marshal(fields)
+	if err != nil {
+		return nil, false, fmt.Errorf("encoding settings: %w", err)
+	}
+	return settings, true, nil
+}
+
+func labelValueToJSON(value string) (raw json.RawMessage, err error) {
+	switch {
+	case value == "true", value == "false":
+		return json.RawMessage(value), nil
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		var s string
+		err = json.Unmarshal([]byte(value), &s)
+		if err != nil {
+			return nil, fmt.Errorf("decoding quoted value: %w", err)
+		}
+		return json.Marshal(s)
+	}
+
+	_, err = strconv.ParseFloat(value, 64)
+	if err == nil {
+		return json.RawMessage(value), nil
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,72 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_labelsToSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		labels     map[string]string
+		settings   string
+		ok         bool
+		errMessage string
+	}{
+		"no_labels": {},
+		"missing_provider": {
+			labels: map[string]string{"ddns.domain": "app.example.com"},
+		},
+		"disabled": {
+			labels: map[string]string{
+				"ddns.enable":   "false",
+				"ddns.domain":   "app.example.com",
+				"ddns.provider": "cloudflare",
+			},
+		},
+		"typed_values": {
+			labels: map[string]string{
+				"ddns.enable":          "true",
+				"ddns.domain":          "app.example.com",
+				"ddns.provider":        "cloudflare",
+				"ddns.proxied":         "true",
+				"ddns.ttl":             "600",
+				"ddns.zone_identifier": `"1234"`,
+				"traefik.enable":       "true",
+			},
+			settings: `{"domain":"app.example.com","provider":"cloudflare",` +
+				`"proxied":true,"ttl":600,"zone_identifier":"1234"}`,
+			ok: true,
+		},
+		"malformed_quoted_value": {
+			labels: map[string]string{
+				"ddns.domain":   "app.example.com",
+				"ddns.provider": "cloudflare",
+				"ddns.token":    `"a"b"`,
+			},
+			errMessage: "label ddns.token: decoding quoted value: " +
+				"invalid character 'b' after top-level value",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings, ok, err := labelsToSettings(testCase.labels, "ddns")
+
+			if testCase.errMessage != "" {
+				require.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.ok, ok)
+			if testCase.settings != "" {
+				assert.JSONEq(t, testCase.settings, string(settings))
+			}
+		})
+	}
+}
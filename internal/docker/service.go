@@ -0,0 +1,189 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/params"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// Service discovers records from the labels of running Docker
+// containers, and watches container events to keep them in sync.
+type Service struct {
+	// Injected fields
+	labelPrefix string
+	setter      RecordsSetter
+	events      EventsGetter
+	logger      Logger
+
+	// Internal fields
+	client      *client
+	lastApplied string
+	runCancel   context.CancelFunc
+	done        <-chan struct{}
+}
+
+func New(socketPath, labelPrefix string, setter RecordsSetter,
+	events EventsGetter, logger Logger,
+) *Service {
+	return &Service{
+		labelPrefix: labelPrefix,
+		setter:      setter,
+		events:      events,
+		logger:      logger,
+		client:      newClient(socketPath),
+	}
+}
+
+func (s *Service) String() string {
+	return "docker discovery"
+}
+
+func (s *Service) Start(ctx context.Context) (runError <-chan error, startErr error) {
+	ready := make(chan struct{})
+	runCtx, runCancel := context.WithCancel(context.Background())
+	s.runCancel = runCancel
+	done := make(chan struct{})
+	s.done = done
+	go s.run(runCtx, ready, done) //nolint:contextcheck
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return nil, s.Stop()
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (s *Service) run(ctx context.Context, ready chan<- struct{},
+	done chan<- struct{},
+) {
+	defer close(done)
+	close(ready)
+
+	const retryPeriod = 10 * time.Second
+	for {
+		// Containers are listed once (re)subscribed to events, so
+		// that no container change is missed in between.
+		onSubscribed := func() { s.synchronize(ctx) }
+		err := s.client.watchEvents(ctx, onSubscribed, func(e event) {
+			s.logger.Debug("container " + e.Actor.ID + " event " + e.Action)
+			s.synchronize(ctx)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Warn("watching events: " + err.Error() +
+			"; retrying in " + retryPeriod.String())
+
+		timer := time.NewTimer(retryPeriod)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (s *Service) synchronize(ctx context.Context) {
+	containers, err := s.client.listContainers(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("listing containers: " + err.Error())
+		}
+		return
+	}
+
+	var dynamicRecords []records.Record
+	var applied []string
+	for _, container := range containers {
+		containerRecords, settings, err := s.containerToRecords(container)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("container %s: %s",
+				containerName(container), err))
+			continue
+		} else if len(containerRecords) > 0 {
+			applied = append(applied, string(settings))
+		}
+		dynamicRecords = append(dynamicRecords, containerRecords...)
+	}
+
+	// Container events unrelated to labeled containers should not
+	// trigger a records update. The settings of the labels are compared
+	// so that changing any label, such as the ttl, updates the records.
+	appliedString := strings.Join(applied, "\n")
+	if appliedString == s.lastApplied {
+		return
+	}
+
+	s.logger.Info(fmt.Sprintf("found %d record(s) from container labels", len(dynamicRecords)))
+	err = s.setter.SetDynamicRecords(ctx, dynamicRecords)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("setting records: " + err.Error())
+		}
+		return
+	}
+	s.lastApplied = appliedString
+}
+
+// containerToRecords returns the records of the container labels, and
+// the settings read from the labels.
+func (s *Service) containerToRecords(container container) (
+	containerRecords []records.Record, settings json.RawMessage, err error,
+) {
+	settings, ok, err := labelsToSettings(container.Labels, s.labelPrefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading labels: %w", err)
+	} else if !ok {
+		return nil, nil, nil
+	}
+
+	containerRecords, warnings, err := params.RecordsFromSettings(settings)
+	for _, warning := range warnings {
+		s.logger.Warn(fmt.Sprintf("container %s: %s", containerName(container), warning))
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating records: %w", err)
+	}
+
+	for i, record := range containerRecords {
+		if record.Settings.RecordType != "" {
+			continue // content records history is not persisted
+		}
+		provider := record.Provider
+		containerRecords[i].History, err = s.events.GetEvents(provider.Domain(),
+			provider.Owner(), provider.IPVersion())
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting history: %w", err)
+		}
+		containerRecords[i].Pin, err = s.events.GetPin(provider.Domain(),
+			provider.Owner(), provider.String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting pin: %w", err)
+		}
+		containerRecords[i].FailedOver, err = s.events.GetFailedOver(provider.Domain(),
+			provider.Owner(), provider.String())
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting failover state: %w", err)
+		}
+	}
+	return containerRecords, settings, nil
+}
+
+func containerName(container container) string {
+	if len(container.Names) > 0 {
+		return strings.TrimPrefix(container.Names[0], "/")
+	}
+	return container.ID
+}
+
+func (s *Service) Stop() (err error) {
+	s.runCancel()
+	<-s.done
+	return nil
+}
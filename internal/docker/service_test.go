@@ -0,0 +1,178 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDockerAPI serves a minimal Docker Engine API over a unix socket.
+type fakeDockerAPI struct {
+	mutex      sync.Mutex
+	containers []container
+	events     chan event
+	// subscribed is true once the events stream is opened.
+	subscribed bool
+	// listedUnsubscribed is true if containers were listed
+	// before the events stream was opened.
+	listedUnsubscribed bool
+}
+
+func (f *fakeDockerAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/containers/json":
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		f.listedUnsubscribed = f.listedUnsubscribed || !f.subscribed
+		_ = json.NewEncoder(w).Encode(f.containers)
+	case "/events":
+		f.mutex.Lock()
+		f.subscribed = true
+		f.mutex.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		encoder := json.NewEncoder(w)
+		for {
+			select {
+			case e := <-f.events:
+				_ = encoder.Encode(e)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeDockerAPI) setContainers(containers []container) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.containers = containers
+}
+
+type testSetter struct {
+	records chan []records.Record
+}
+
+func (s *testSetter) SetDynamicRecords(ctx context.Context, records []records.Record) error {
+	select {
+	case s.records <- records:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type testEvents struct{}
+
+func (testEvents) GetEvents(string, string, ipversion.IPVersion) ([]models.HistoryEvent, error) {
+	return nil, nil
+}
+
+func (testEvents) GetFailedOver(string, string, string) (bool, error) {
+	return false, nil
+}
+
+func (testEvents) GetPin(string, string, string) (*models.Pin, error) {
+	return nil, nil //nolint:nilnil
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_Service(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	api := &fakeDockerAPI{events: make(chan event)}
+	server := &http.Server{Handler: api, ReadHeaderTimeout: time.Second} //nolint:gosec
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	api.setContainers([]container{
+		{ID: "1", Names: []string{"/app"}, Labels: map[string]string{
+			"ddns.domain":   "app.example.com,www.example.com",
+			"ddns.provider": "example",
+			"ddns.username": "user",
+			"ddns.password": `"1234"`,
+		}},
+		{ID: "2", Names: []string{"/unrelated"}},
+	})
+
+	setter := &testSetter{records: make(chan []records.Record)}
+	service := New(socketPath, "ddns", setter, testEvents{}, noopLogger{})
+	_, err = service.Start(context.Background())
+	require.NoError(t, err)
+
+	const timeout = 5 * time.Second
+	select {
+	case records := <-setter.records:
+		require.Len(t, records, 2)
+		assert.Equal(t, "app.example.com", records[0].Provider.BuildDomainName())
+		assert.Equal(t, "www.example.com", records[1].Provider.BuildDomainName())
+	case <-time.After(timeout):
+		t.Fatal("records were not set")
+	}
+
+	// Changing a label of the container updates its records.
+	api.setContainers([]container{
+		{ID: "1", Names: []string{"/app"}, Labels: map[string]string{
+			"ddns.domain":   "app.example.com,www.example.com",
+			"ddns.provider": "example",
+			"ddns.username": "user",
+			"ddns.password": `"5678"`,
+		}},
+		{ID: "2", Names: []string{"/unrelated"}},
+	})
+	select {
+	case api.events <- event{Type: "container", Action: "update"}:
+	case <-time.After(timeout):
+		t.Fatal("event was not consumed")
+	}
+	select {
+	case records := <-setter.records:
+		assert.Len(t, records, 2)
+	case <-time.After(timeout):
+		t.Fatal("records were not set after a label change")
+	}
+
+	// Stopping the labeled container removes its records.
+	api.setContainers([]container{{ID: "2", Names: []string{"/unrelated"}}})
+	select {
+	case api.events <- event{Type: "container", Action: "die"}:
+	case <-time.After(timeout):
+		t.Fatal("event was not consumed")
+	}
+	select {
+	case records := <-setter.records:
+		assert.Empty(t, records)
+	case <-time.After(timeout):
+		t.Fatal("records were not set")
+	}
+
+	err = service.Stop()
+	require.NoError(t, err)
+
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+	assert.False(t, api.listedUnsubscribed, "containers listed before subscribing to events")
+}
@@ -0,0 +1,67 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Checker runs health checks of records primary targets.
+type Checker struct {
+	client *http.Client
+	dialer *net.Dialer
+}
+
+func NewChecker(client *http.Client) *Checker {
+	return &Checker{
+		client: client,
+		dialer: &net.Dialer{},
+	}
+}
+
+var ErrHTTPStatusNotHealthy = errors.New("HTTP status code is not healthy")
+
+// Check runs the health check of the settings given,
+// and returns an error if the target is unhealthy.
+func (c *Checker) Check(ctx context.Context, settings Settings) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, settings.Timeout)
+	defer cancel()
+
+	switch settings.Check {
+	case CheckHTTP:
+		return c.checkHTTP(ctx, settings.Target)
+	case CheckTCP:
+		return c.checkTCP(ctx, settings.Target)
+	default:
+		return fmt.Errorf("%w: %s", ErrCheckNotValid, settings.Check)
+	}
+}
+
+func (c *Checker) checkHTTP(ctx context.Context, url string) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	_ = response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: %d", ErrHTTPStatusNotHealthy, response.StatusCode)
+	}
+	return nil
+}
+
+func (c *Checker) checkTCP(ctx context.Context, address string) (err error) {
+	connection, err := c.dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("connecting: %w", err)
+	}
+	_ = connection.Close()
+	return nil
+}
@@ -0,0 +1,72 @@
+package failover
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Checker_Check(t *testing.T) {
+	t.Parallel()
+
+	healthyServer := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	t.Cleanup(healthyServer.Close)
+	unhealthyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(unhealthyServer.Close)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := listener.Addr().String()
+	err = listener.Close()
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		settings   Settings
+		healthy    bool
+		errWrapped error
+	}{
+		"http_healthy": {
+			settings: Settings{Check: CheckHTTP, Target: healthyServer.URL},
+			healthy:  true,
+		},
+		"http_unhealthy": {
+			settings:   Settings{Check: CheckHTTP, Target: unhealthyServer.URL},
+			errWrapped: ErrHTTPStatusNotHealthy,
+		},
+		"tcp_healthy": {
+			settings: Settings{Check: CheckTCP, Target: healthyServer.Listener.Addr().String()},
+			healthy:  true,
+		},
+		"tcp_unhealthy": {
+			settings: Settings{Check: CheckTCP, Target: closedAddress},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := NewChecker(&http.Client{})
+			testCase.settings.Timeout = time.Second
+
+			err := checker.Check(context.Background(), testCase.settings)
+
+			if testCase.healthy {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+			}
+		})
+	}
+}
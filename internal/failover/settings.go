@@ -0,0 +1,111 @@
+// Package failover defines the health check settings of records
+// pointed to a backup IP address when their primary target is unhealthy.
+package failover
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+// CheckType is the type of health check of the primary target.
+type CheckType string
+
+const (
+	// CheckHTTP sends an HTTP GET request to the target URL, and
+	// considers it healthy if the response status code is below 400.
+	CheckHTTP CheckType = "http"
+	// CheckTCP connects to the target host:port address over TCP,
+	// and considers it healthy if the connection is established.
+	CheckTCP CheckType = "tcp"
+)
+
+// Settings are the failover settings of a record.
+type Settings struct {
+	Check  CheckType
+	Target string
+	// Interval is the duration between two health checks.
+	Interval time.Duration
+	// Timeout is the maximum duration of a health check.
+	Timeout time.Duration
+	// Threshold is the number of consecutive failed health checks
+	// to switch to the backup, and the number of consecutive successful
+	// health checks to switch back once failed over.
+	Threshold uint
+	// BackupIP is the IP address to point the record at when failed over,
+	// and is mutually exclusive with BackupRecord.
+	BackupIP netip.Addr
+	// BackupRecord is the domain name of another record whose current
+	// IP addresses are used when failed over.
+	BackupRecord string
+}
+
+var (
+	ErrCheckNotValid          = errors.New("check type is not valid")
+	ErrTargetNotSet           = errors.New("target is not set")
+	ErrTargetNotValid         = errors.New("target is not valid")
+	ErrIntervalTooSmall       = errors.New("interval is too small")
+	ErrTimeoutNotValid        = errors.New("timeout is not valid")
+	ErrThresholdNotSet        = errors.New("threshold is not set")
+	ErrBackupNotSet           = errors.New("backup IP address or backup record must be set")
+	ErrBackupBothSet          = errors.New("backup IP address and backup record are mutually exclusive")
+	ErrTargetURLSchemeNotHTTP = errors.New("target URL scheme is not http or https")
+)
+
+func (s Settings) Validate() (err error) {
+	switch s.Check {
+	case CheckHTTP:
+		err = validateURL(s.Target)
+	case CheckTCP:
+		err = validateAddress(s.Target)
+	default:
+		return fmt.Errorf("%w: %q must be one of %q or %q",
+			ErrCheckNotValid, s.Check, CheckHTTP, CheckTCP)
+	}
+	if err != nil {
+		return err
+	}
+
+	const minInterval = time.Second
+	switch {
+	case s.Interval < minInterval:
+		return fmt.Errorf("%w: %s must be at least %s", ErrIntervalTooSmall, s.Interval, minInterval)
+	case s.Timeout <= 0 || s.Timeout > s.Interval:
+		return fmt.Errorf("%w: %s must be positive and at most the interval %s",
+			ErrTimeoutNotValid, s.Timeout, s.Interval)
+	case s.Threshold == 0:
+		return fmt.Errorf("%w", ErrThresholdNotSet)
+	case !s.BackupIP.IsValid() && s.BackupRecord == "":
+		return fmt.Errorf("%w", ErrBackupNotSet)
+	case s.BackupIP.IsValid() && s.BackupRecord != "":
+		return fmt.Errorf("%w", ErrBackupBothSet)
+	}
+	return nil
+}
+
+func validateURL(target string) (err error) {
+	if target == "" {
+		return fmt.Errorf("%w", ErrTargetNotSet)
+	}
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTargetNotValid, err)
+	} else if targetURL.Scheme != "http" && targetURL.Scheme != "https" {
+		return fmt.Errorf("%w: %s", ErrTargetURLSchemeNotHTTP, target)
+	}
+	return nil
+}
+
+func validateAddress(target string) (err error) {
+	if target == "" {
+		return fmt.Errorf("%w", ErrTargetNotSet)
+	}
+	_, _, err = net.SplitHostPort(target)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTargetNotValid, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package ipexpr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+var ErrMACAddressLength = errors.New("MAC address is not 48 or 64 bits long")
+
+// macToEUI64 returns the IPv6 address with the modified EUI-64
+// interface identifier of the MAC address given, as described
+// in RFC 4291 appendix A.
+func macToEUI64(s string) (host netip.Addr, err error) {
+	mac, err := net.ParseMAC(strings.TrimSpace(s))
+	if err != nil {
+		return host, err
+	}
+
+	const (
+		mac48Length       = 6
+		eui64Length       = 8
+		ipv6Length        = 16
+		universalLocalBit = 0x02
+	)
+	var identifier []byte
+	switch len(mac) {
+	case mac48Length:
+		const ouiLength = 3
+		identifier = make([]byte, 0, eui64Length)
+		identifier = append(identifier, mac[:ouiLength]...)
+		identifier = append(identifier, 0xff, 0xfe) //nolint:mnd
+		identifier = append(identifier, mac[ouiLength:]...)
+	case eui64Length:
+		identifier = mac
+	default:
+		return host, fmt.Errorf("%w: %s", ErrMACAddressLength, mac)
+	}
+
+	var ipv6Bytes [ipv6Length]byte
+	copy(ipv6Bytes[ipv6Length-eui64Length:], identifier)
+	ipv6Bytes[ipv6Length-eui64Length] ^= universalLocalBit
+	return netip.AddrFrom16(ipv6Bytes), nil
+}
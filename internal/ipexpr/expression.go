@@ -0,0 +1,164 @@
+// Package ipexpr computes the IP address of a record from the public
+// IP address, with expressions such as "suffix(::10/64)".
+package ipexpr
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+type kind string
+
+const (
+	kindStatic kind = "static"
+	kindSuffix kind = "suffix"
+	kindMap    kind = "map"
+	kindHost   kind = "host"
+	kindEUI64  kind = "eui64"
+)
+
+// Expression computes an IP address from a public IP address
+// of the same IP family.
+type Expression struct {
+	kind   kind
+	static netip.Addr
+	suffix netip.Prefix
+	table  map[netip.Addr]netip.Addr
+	// host is the IPv6 host identifier for the host and eui64 kinds.
+	host netip.Addr
+	is4  bool
+	raw  string
+}
+
+var (
+	ErrExpressionFormat    = errors.New("expression is not in the format <function>(<arguments>)")
+	ErrFunctionUnknown     = errors.New("expression function is unknown")
+	ErrArgumentNotValid    = errors.New("expression argument is not valid")
+	ErrIPFamiliesDifferent = errors.New("IP addresses are of different IP families")
+	ErrHostNotIPv6         = errors.New("host identifier is not an IPv6 address")
+)
+
+// Parse parses an expression, which is one of:
+//   - static(<ip>) to always use the IP address given.
+//   - suffix(<ip>/<bits>) to use the last bits of the IP address given
+//     and the first bits of the public IP address.
+//   - map(<public ip>=<ip>, ...) to use the IP address mapped to the
+//     public IP address, such as with a 1:1 NAT table.
+//   - host(<ipv6>) to use the bits of the IPv6 host identifier given
+//     after the delegated prefix of the public IPv6 address.
+//   - eui64(<mac>) to use the EUI-64 interface identifier of the MAC
+//     address given after the /64 prefix of the public IPv6 address.
+func Parse(s string) (expression Expression, err error) {
+	s = strings.TrimSpace(s)
+	function, arguments, ok := strings.Cut(s, "(")
+	if !ok || !strings.HasSuffix(arguments, ")") {
+		return expression, fmt.Errorf("%w: %q", ErrExpressionFormat, s)
+	}
+	arguments = strings.TrimSpace(strings.TrimSuffix(arguments, ")"))
+	expression.kind = kind(strings.TrimSpace(function))
+	expression.raw = s
+
+	switch expression.kind {
+	case kindStatic:
+		expression.static, err = netip.ParseAddr(arguments)
+		expression.is4 = expression.static.Is4()
+	case kindSuffix:
+		expression.suffix, err = netip.ParsePrefix(arguments)
+		expression.is4 = expression.suffix.Addr().Is4()
+	case kindMap:
+		expression.table, expression.is4, err = parseTable(arguments)
+	case kindHost:
+		expression.host, err = netip.ParseAddr(arguments)
+		if err == nil && !expression.host.Is6() {
+			err = fmt.Errorf("%w: %s", ErrHostNotIPv6, expression.host)
+		}
+	case kindEUI64:
+		expression.host, err = macToEUI64(arguments)
+	default:
+		return expression, fmt.Errorf("%w: %q must be one of %q, %q, %q, %q or %q",
+			ErrFunctionUnknown, function, kindStatic, kindSuffix, kindMap, kindHost, kindEUI64)
+	}
+	if err != nil {
+		return expression, fmt.Errorf("%w: %s: %w", ErrArgumentNotValid, s, err)
+	}
+	return expression, nil
+}
+
+var ErrMappingFormat = errors.New("mapping is not in the format <public ip>=<ip>")
+
+func parseTable(arguments string) (table map[netip.Addr]netip.Addr, is4 bool, err error) {
+	mappings := strings.Split(arguments, ",")
+	table = make(map[netip.Addr]netip.Addr, len(mappings))
+	for i, mapping := range mappings {
+		publicString, ipString, ok := strings.Cut(mapping, "=")
+		if !ok {
+			return nil, false, fmt.Errorf("%w: %q", ErrMappingFormat, mapping)
+		}
+		publicIP, err := netip.ParseAddr(strings.TrimSpace(publicString))
+		if err != nil {
+			return nil, false, err
+		}
+		ip, err := netip.ParseAddr(strings.TrimSpace(ipString))
+		if err != nil {
+			return nil, false, err
+		}
+		if i == 0 {
+			is4 = publicIP.Is4()
+		}
+		if publicIP.Is4() != is4 || ip.Is4() != is4 {
+			return nil, false, fmt.Errorf("%w: %s", ErrIPFamiliesDifferent, strings.TrimSpace(mapping))
+		}
+		table[publicIP] = ip
+	}
+	return table, is4, nil
+}
+
+// Is4 returns true if the expression computes IPv4 addresses.
+func (e Expression) Is4() bool {
+	return e.is4
+}
+
+// IsStatic returns true if the expression does not
+// depend on the public IP address.
+func (e Expression) IsStatic() bool {
+	return e.kind == kindStatic
+}
+
+// UsesPrefix returns true if the expression depends on the
+// length of the delegated IPv6 prefix.
+func (e Expression) UsesPrefix() bool {
+	return e.kind == kindHost
+}
+
+// Compute returns the IP address computed from the public IP address
+// given, which must be of the IP family of the expression, or invalid
+// if not found. The prefix bits are the length of the delegated IPv6
+// prefix, which is only used by host expressions.
+// The address returned is invalid if it cannot be computed.
+func (e Expression) Compute(publicIP netip.Addr, prefixBits int) (ip netip.Addr) {
+	switch e.kind {
+	case kindStatic:
+		return e.static
+	case kindMap:
+		return e.table[publicIP.Unmap()]
+	}
+
+	if !publicIP.IsValid() {
+		return netip.Addr{}
+	}
+	switch e.kind {
+	case kindSuffix:
+		return MergeSuffix(publicIP, e.suffix)
+	case kindHost:
+		return MergeSuffix(publicIP, netip.PrefixFrom(e.host, publicIP.BitLen()-prefixBits))
+	default: // EUI-64 interface identifiers are always 64 bits long
+		const interfaceIdentifierBits = 64
+		return MergeSuffix(publicIP, netip.PrefixFrom(e.host, interfaceIdentifierBits))
+	}
+}
+
+func (e Expression) String() string {
+	return e.raw
+}
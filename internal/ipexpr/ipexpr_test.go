@@ -0,0 +1,319 @@
+package ipexpr
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		expression Expression
+		errWrapped error
+		errMessage string
+	}{
+		"static": {
+			s: " static(203.0.113.10) ",
+			expression: Expression{
+				kind:   kindStatic,
+				static: netip.MustParseAddr("203.0.113.10"),
+				is4:    true,
+				raw:    "static(203.0.113.10)",
+			},
+		},
+		"suffix": {
+			s: "suffix(::10/64)",
+			expression: Expression{
+				kind:   kindSuffix,
+				suffix: netip.MustParsePrefix("::10/64"),
+				raw:    "suffix(::10/64)",
+			},
+		},
+		"map": {
+			s: "map(198.51.100.1=203.0.113.5, 198.51.100.2 = 203.0.113.6)",
+			expression: Expression{
+				kind: kindMap,
+				table: map[netip.Addr]netip.Addr{
+					netip.MustParseAddr("198.51.100.1"): netip.MustParseAddr("203.0.113.5"),
+					netip.MustParseAddr("198.51.100.2"): netip.MustParseAddr("203.0.113.6"),
+				},
+				is4: true,
+				raw: "map(198.51.100.1=203.0.113.5, 198.51.100.2 = 203.0.113.6)",
+			},
+		},
+		"host": {
+			s: "host(::10)",
+			expression: Expression{
+				kind: kindHost,
+				host: netip.MustParseAddr("::10"),
+				raw:  "host(::10)",
+			},
+		},
+		"eui64": {
+			s: "eui64(02:11:22:33:44:55)",
+			expression: Expression{
+				kind: kindEUI64,
+				host: netip.MustParseAddr("::11:22ff:fe33:4455"),
+				raw:  "eui64(02:11:22:33:44:55)",
+			},
+		},
+		"host_not_ipv6": {
+			s:          "host(0.0.0.5)",
+			errWrapped: ErrHostNotIPv6,
+			errMessage: "expression argument is not valid: host(0.0.0.5): " +
+				"host identifier is not an IPv6 address: 0.0.0.5",
+		},
+		"bad_format": {
+			s:          "static 1.2.3.4",
+			errWrapped: ErrExpressionFormat,
+			errMessage: `expression is not in the format <function>(<arguments>): "static 1.2.3.4"`,
+		},
+		"unknown_function": {
+			s:          "offset(1)",
+			errWrapped: ErrFunctionUnknown,
+			errMessage: `expression function is unknown: "offset" must be one of ` +
+				`"static", "suffix", "map", "host" or "eui64"`,
+		},
+		"bad_static": {
+			s:          "static(x)",
+			errWrapped: ErrArgumentNotValid,
+			errMessage: `expression argument is not valid: static(x): ParseAddr("x"): unable to parse IP`,
+		},
+		"map_bad_mapping": {
+			s:          "map(1.2.3.4)",
+			errWrapped: ErrMappingFormat,
+			errMessage: `expression argument is not valid: map(1.2.3.4): ` +
+				`mapping is not in the format <public ip>=<ip>: "1.2.3.4"`,
+		},
+		"map_families_different": {
+			s:          "map(1.2.3.4=::1)",
+			errWrapped: ErrIPFamiliesDifferent,
+			errMessage: `expression argument is not valid: map(1.2.3.4=::1): ` +
+				`IP addresses are of different IP families: 1.2.3.4=::1`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := Parse(testCase.s)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.expression, expression)
+		})
+	}
+}
+
+func Test_Expression_Compute(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		publicIP   netip.Addr
+		prefixBits int
+		ip         netip.Addr
+	}{
+		"static": {
+			expression: "static(203.0.113.10)",
+			publicIP:   netip.MustParseAddr("198.51.100.1"),
+			ip:         netip.MustParseAddr("203.0.113.10"),
+		},
+		"static_without_public_ip": {
+			expression: "static(203.0.113.10)",
+			ip:         netip.MustParseAddr("203.0.113.10"),
+		},
+		"suffix_ipv6": {
+			expression: "suffix(::1234:5678:9abc:def0/64)",
+			publicIP:   netip.MustParseAddr("2001:db8:1:2:aaaa:bbbb:cccc:dddd"),
+			ip:         netip.MustParseAddr("2001:db8:1:2:1234:5678:9abc:def0"),
+		},
+		"suffix_ipv4": {
+			expression: "suffix(0.0.0.5/3)",
+			publicIP:   netip.MustParseAddr("198.51.100.9"),
+			ip:         netip.MustParseAddr("198.51.100.13"),
+		},
+		"suffix_without_public_ip": {
+			expression: "suffix(::1/64)",
+		},
+		"map": {
+			expression: "map(198.51.100.1=203.0.113.5)",
+			publicIP:   netip.MustParseAddr("198.51.100.1"),
+			ip:         netip.MustParseAddr("203.0.113.5"),
+		},
+		"map_not_found": {
+			expression: "map(198.51.100.1=203.0.113.5)",
+			publicIP:   netip.MustParseAddr("198.51.100.2"),
+		},
+		"host_prefix_56": {
+			expression: "host(0:0:0:1::10)",
+			publicIP:   netip.MustParseAddr("2001:db8:0:aa00:aaaa:bbbb:cccc:dddd"),
+			prefixBits: 56,
+			ip:         netip.MustParseAddr("2001:db8:0:aa01::10"),
+		},
+		"host_prefix_64": {
+			expression: "host(::10)",
+			publicIP:   netip.MustParseAddr("2001:db8:0:aa00:aaaa:bbbb:cccc:dddd"),
+			prefixBits: 64,
+			ip:         netip.MustParseAddr("2001:db8:0:aa00::10"),
+		},
+		"eui64": {
+			expression: "eui64(00:11:22:33:44:55)",
+			publicIP:   netip.MustParseAddr("2001:db8:0:aa00:aaaa:bbbb:cccc:dddd"),
+			prefixBits: 56,
+			ip:         netip.MustParseAddr("2001:db8:0:aa00:211:22ff:fe33:4455"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := Parse(testCase.expression)
+			require.NoError(t, err)
+
+			ip := expression.Compute(testCase.publicIP, testCase.prefixBits)
+
+			assert.Equal(t, testCase.ip, ip)
+		})
+	}
+}
+
+func Test_Prefix_Bits(t *testing.T) {
+	t.Parallel()
+
+	publicIP := netip.MustParseAddr("2001:db8:0:aa00::1")
+	interfaces := map[string][]netip.Prefix{
+		"eth0": {netip.MustParsePrefix("192.168.1.2/24"), netip.MustParsePrefix("2001:db8:0:aa00::1/56")},
+		"eth1": {netip.MustParsePrefix("2001:db8:ffff::1/64")},
+		"eth2": {netip.MustParsePrefix("2001:db8:0:aa00::1/128")},
+	}
+	addresses := func(iface string) (prefixes []netip.Prefix, err error) {
+		if iface != "" {
+			return interfaces[iface], nil
+		}
+		return append(interfaces["eth2"], interfaces["eth0"]...), nil
+	}
+
+	testCases := map[string]struct {
+		s          string
+		bits       int
+		errWrapped error
+		errMessage string
+	}{
+		"configured": {
+			s:    "/48",
+			bits: 48,
+		},
+		"any_interface": {
+			s:    "interface",
+			bits: 56,
+		},
+		"named_interface": {
+			s:    "interface:eth0",
+			bits: 56,
+		},
+		"not_on_interface": {
+			s:          "interface:eth1",
+			errWrapped: ErrPrefixNotLearned,
+			errMessage: "prefix length not found on network interfaces: for 2001:db8:0:aa00::1",
+		},
+		"not_on_interface_fallback": {
+			s:    "interface:eth1/48",
+			bits: 48,
+		},
+		"learned_over_fallback": {
+			s:    "interface:eth0/48",
+			bits: 56,
+		},
+		"single_address": {
+			s:          "interface:eth2",
+			errWrapped: ErrPrefixLearnedTooLong,
+			errMessage: "prefix length learned is too long: /128 for 2001:db8:0:aa00::1 is longer than /64",
+		},
+		"single_address_fallback": {
+			s:    "interface:eth2/56",
+			bits: 56,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prefix, err := ParsePrefix(testCase.s)
+			require.NoError(t, err)
+			prefix.addresses = addresses
+
+			bits, err := prefix.Bits(publicIP)
+
+			assert.Equal(t, testCase.bits, bits)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_ParsePrefix(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		errWrapped error
+		errMessage string
+	}{
+		"bits": {
+			s: "/56",
+		},
+		"interface": {
+			s: "interface:eth0",
+		},
+		"interface_fallback": {
+			s: "interface/56",
+		},
+		"named_interface_fallback": {
+			s: "interface:eth0/56",
+		},
+		"fallback_not_valid": {
+			s:          "interface:eth0/x",
+			errWrapped: ErrPrefixFormat,
+			errMessage: `prefix is not in the format /<bits> or interface[:<name>][/<bits>]: "interface:eth0/x"`,
+		},
+		"empty_interface_name": {
+			s:          "interface:",
+			errWrapped: ErrPrefixFormat,
+			errMessage: `prefix is not in the format /<bits> or interface[:<name>][/<bits>]: "interface:"`,
+		},
+		"bits_out_of_range": {
+			s:          "/128",
+			errWrapped: ErrPrefixBitsRange,
+			errMessage: "prefix bits are out of range: 128 must be between 1 and 127",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prefix, err := ParsePrefix(testCase.s)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Equal(t, testCase.s, prefix.String())
+		})
+	}
+}
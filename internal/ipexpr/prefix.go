@@ -0,0 +1,146 @@
+package ipexpr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// DefaultPrefixBits is the length of the delegated IPv6
+// prefix used if no prefix is configured.
+const DefaultPrefixBits = 64
+
+// Prefix defines how the length of the delegated IPv6 prefix is obtained,
+// which is either configured, or learned from the prefix length of the
+// network interface address containing the public IPv6 address.
+type Prefix struct {
+	// bits is the configured prefix length, which is also the
+	// fallback length when learning it. It is 0 if unset.
+	bits      int
+	learn     bool
+	iface     string
+	raw       string
+	addresses func(iface string) (prefixes []netip.Prefix, err error)
+}
+
+var (
+	ErrPrefixFormat         = errors.New("prefix is not in the format /<bits> or interface[:<name>][/<bits>]")
+	ErrPrefixBitsRange      = errors.New("prefix bits are out of range")
+	ErrPrefixNotLearned     = errors.New("prefix length not found on network interfaces")
+	ErrPrefixLearnedTooLong = errors.New("prefix length learned is too long")
+)
+
+// ParsePrefix parses the delegated IPv6 prefix definition given, which is
+// one of "/<bits>" such as "/56", "interface" to learn the prefix length
+// from any network interface, or "interface:<name>" to learn it from the
+// network interface with the name given. The interface forms can be
+// followed by "/<bits>", such as "interface:eth0/56", to fall back to
+// this prefix length if it cannot be learned.
+func ParsePrefix(s string) (prefix Prefix, err error) {
+	s = strings.TrimSpace(s)
+	prefix.raw = s
+	prefix.addresses = interfacePrefixes
+	learnDefinition, bitsString, hasBits := strings.Cut(s, "/")
+	if hasBits {
+		const maxBits = 127
+		bits, err := strconv.Atoi(bitsString)
+		if err != nil {
+			return prefix, fmt.Errorf("%w: %q", ErrPrefixFormat, s)
+		} else if bits < 1 || bits > maxBits {
+			return prefix, fmt.Errorf("%w: %d must be between 1 and %d", ErrPrefixBitsRange, bits, maxBits)
+		}
+		prefix.bits = bits
+	}
+
+	switch {
+	case learnDefinition == "" && hasBits:
+	case learnDefinition == "interface":
+		prefix.learn = true
+	case strings.HasPrefix(learnDefinition, "interface:") && len(learnDefinition) > len("interface:"):
+		prefix.learn = true
+		prefix.iface = strings.TrimPrefix(learnDefinition, "interface:")
+	default:
+		return prefix, fmt.Errorf("%w: %q", ErrPrefixFormat, s)
+	}
+	return prefix, nil
+}
+
+// Bits returns the length of the delegated prefix of the public IPv6
+// address given. A learned length falls back to the configured length
+// if it cannot be learned.
+func (p Prefix) Bits(publicIP netip.Addr) (bits int, err error) {
+	if !p.learn {
+		return p.bits, nil
+	}
+	bits, err = p.learnBits(publicIP)
+	if err != nil && p.bits != 0 {
+		return p.bits, nil
+	}
+	return bits, err
+}
+
+// learnBits returns the length of the network interface prefix containing
+// the public IPv6 address given. Lengths longer than /64 are rejected,
+// since they are not the delegated prefix but for example a single
+// address assigned with DHCPv6.
+func (p Prefix) learnBits(publicIP netip.Addr) (bits int, err error) {
+	prefixes, err := p.addresses(p.iface)
+	if err != nil {
+		return 0, fmt.Errorf("getting network interface addresses: %w", err)
+	}
+	const maxLearnedBits = 64
+	err = fmt.Errorf("%w: for %s", ErrPrefixNotLearned, publicIP)
+	for _, prefix := range prefixes {
+		switch {
+		case !prefix.Contains(publicIP):
+			continue
+		case prefix.Bits() > maxLearnedBits:
+			err = fmt.Errorf("%w: /%d for %s is longer than /%d",
+				ErrPrefixLearnedTooLong, prefix.Bits(), publicIP, maxLearnedBits)
+			continue
+		}
+		return prefix.Bits(), nil
+	}
+	return 0, err
+}
+
+func (p Prefix) String() string {
+	return p.raw
+}
+
+// interfacePrefixes returns the address prefixes of the network interface
+// with the name given, or of all network interfaces if the name is empty.
+func interfacePrefixes(iface string) (prefixes []netip.Prefix, err error) {
+	var addresses []net.Addr
+	if iface == "" {
+		addresses, err = net.InterfaceAddrs()
+	} else {
+		var netInterface *net.Interface
+		netInterface, err = net.InterfaceByName(iface)
+		if err != nil {
+			return nil, err
+		}
+		addresses, err = netInterface.Addrs()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes = make([]netip.Prefix, 0, len(addresses))
+	for _, address := range addresses {
+		ipNet, ok := address.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip, ok := netip.AddrFromSlice(ipNet.IP)
+		if !ok {
+			continue
+		}
+		bits, _ := ipNet.Mask.Size()
+		prefixes = append(prefixes, netip.PrefixFrom(ip.Unmap(), bits))
+	}
+	return prefixes, nil
+}
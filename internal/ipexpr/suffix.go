@@ -0,0 +1,30 @@
+package ipexpr
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// MergeSuffix returns the IP address made of the first bits of the
+// IP address given and the last bits of the suffix address, where the
+// number of last bits is the bits count of the suffix prefix.
+// The IP address and suffix must be of the same IP family.
+func MergeSuffix(ip netip.Addr, suffix netip.Prefix) (merged netip.Addr) {
+	const bitsInByte = 8
+	const byteMask = 0xFF
+	keptBits := ip.BitLen() - suffix.Bits()
+	keptBytes := keptBits / bitsInByte
+	remainderBits := keptBits % bitsInByte
+	ipBytes := ip.AsSlice()
+	mergedBytes := suffix.Addr().AsSlice()
+	copy(mergedBytes[:keptBytes], ipBytes[:keptBytes])
+	if remainderBits > 0 {
+		mask := byte(byteMask << (bitsInByte - remainderBits))
+		mergedBytes[keptBytes] = (ipBytes[keptBytes] & mask) | (mergedBytes[keptBytes] & ^mask)
+	}
+	merged, ok := netip.AddrFromSlice(mergedBytes)
+	if !ok {
+		panic(fmt.Sprintf("failed to create IP address from merged bytes %v", mergedBytes))
+	}
+	return merged
+}
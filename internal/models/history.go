@@ -12,8 +12,11 @@ import (
 type History []HistoryEvent // current and previous ips
 
 type HistoryEvent struct { // current and previous ips
-	IP   netip.Addr `json:"ip"`
-	Time time.Time  `json:"time"`
+	IP netip.Addr `json:"ip"`
+	// IPs are all the IP addresses of a multi-value record,
+	// starting with IP, and are empty for other records.
+	IPs  []netip.Addr `json:"ips,omitempty"`
+	Time time.Time    `json:"time"`
 }
 
 // GetPreviousIPs returns an antichronological list of previous
@@ -22,10 +25,13 @@ func (h History) GetPreviousIPs() (previousIPs []netip.Addr) {
 	if len(h) <= 1 {
 		return nil
 	}
-	previousIPs = make([]netip.Addr, len(h)-1)
+	previousIPs = make([]netip.Addr, 0, len(h)-1)
 	mostRecentPreviousIPIndex := len(h) - 2 //nolint:mnd
-	for i := range previousIPs {
-		previousIPs[i] = h[mostRecentPreviousIPIndex-i].IP
+	for i := mostRecentPreviousIPIndex; i >= 0; i-- {
+		if !h[i].IP.IsValid() { // multi-value record without IP address
+			continue
+		}
+		previousIPs = append(previousIPs, h[i].IP)
 	}
 	return previousIPs
 }
@@ -38,6 +44,44 @@ func (h History) GetCurrentIP() netip.Addr {
 	return h[len(h)-1].IP
 }
 
+// GetCurrentIPs returns the current IP addresses of a multi-value
+// record, or the current IP address for other records. It returns
+// nil if the latest event has no IP address, which is the case for
+// a multi-value record set to no IP address.
+func (h History) GetCurrentIPs() []netip.Addr {
+	if len(h) < 1 {
+		return nil
+	}
+	event := h[len(h)-1]
+	switch {
+	case len(event.IPs) > 0:
+		return event.IPs
+	case !event.IP.IsValid():
+		return nil
+	}
+	return []netip.Addr{event.IP}
+}
+
+// GetCurrentIP4 returns the latest IPv4 address in history.
+func (h History) GetCurrentIP4() netip.Addr {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].IP.Is4() {
+			return h[i].IP
+		}
+	}
+	return netip.Addr{}
+}
+
+// GetCurrentIP6 returns the latest IPv6 address in history.
+func (h History) GetCurrentIP6() netip.Addr {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].IP.Is6() {
+			return h[i].IP
+		}
+	}
+	return netip.Addr{}
+}
+
 // GetSuccessTime returns the latest success update time.
 func (h History) GetSuccessTime() time.Time {
 	if len(h) < 1 {
@@ -46,6 +90,48 @@ func (h History) GetSuccessTime() time.Time {
 	return h[len(h)-1].Time
 }
 
+// GetTypicalChangeTime returns the time of day, as a duration since
+// midnight in the location given, at which the IP address usually
+// changes, typically due to a daily reconnection forced by the ISP.
+// It is learned from the recent IP address changes, and ok is false
+// if most of them did not happen within the same hour of the day.
+func (h History) GetTypicalChangeTime(location *time.Location) (
+	timeOfDay time.Duration, ok bool,
+) {
+	const maxChanges = 14
+	changes := h
+	if len(changes) > 0 {
+		changes = changes[1:] // first event is not a change
+	}
+	if len(changes) > maxChanges {
+		changes = changes[len(changes)-maxChanges:]
+	}
+
+	const hoursInDay = 24
+	var hourCounts [hoursInDay]int
+	var hourLatest [hoursInDay]time.Duration
+	for _, event := range changes {
+		t := event.Time.In(location)
+		hour := t.Hour()
+		hourCounts[hour]++
+		eventTimeOfDay := time.Duration(hour)*time.Hour + time.Duration(t.Minute())*time.Minute
+		hourLatest[hour] = max(hourLatest[hour], eventTimeOfDay)
+	}
+
+	typicalHour := 0
+	for hour, count := range hourCounts {
+		if count > hourCounts[typicalHour] {
+			typicalHour = hour
+		}
+	}
+	const minChanges = 3
+	count := hourCounts[typicalHour]
+	if count < minChanges || count*2 <= len(changes) {
+		return 0, false
+	}
+	return hourLatest[typicalHour], true
+}
+
 func (h History) GetDurationSinceSuccess(now time.Time) string {
 	if len(h) < 1 {
 		return "N/A"
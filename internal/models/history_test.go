@@ -42,6 +42,16 @@ func Test_GetPreviousIPs(t *testing.T) {
 				netip.MustParseAddr("1.2.3.4"),
 			},
 		},
+		"event_without_ip": {
+			h: History{
+				{IP: netip.MustParseAddr("1.2.3.4")},
+				{},                                   // multi-value record set to no IP address
+				{IP: netip.MustParseAddr("9.6.7.8")}, // last one
+			},
+			previousIPs: []netip.Addr{
+				netip.MustParseAddr("1.2.3.4"),
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -80,3 +90,89 @@ func Test_GetDurationSinceSuccess(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetCurrentIP4and6(t *testing.T) {
+	t.Parallel()
+	testCases := map[string]struct {
+		h   History
+		ip4 netip.Addr
+		ip6 netip.Addr
+	}{
+		"empty_history": {
+			h: History{},
+		},
+		"ipv4_only": {
+			h: History{
+				{IP: netip.MustParseAddr("1.2.3.4")},
+				{IP: netip.MustParseAddr("5.6.7.8")},
+			},
+			ip4: netip.MustParseAddr("5.6.7.8"),
+		},
+		"mixed": {
+			h: History{
+				{IP: netip.MustParseAddr("1.2.3.4")},
+				{IP: netip.MustParseAddr("::1")},
+				{IP: netip.MustParseAddr("5.6.7.8")},
+				{IP: netip.MustParseAddr("::2")},
+			},
+			ip4: netip.MustParseAddr("5.6.7.8"),
+			ip6: netip.MustParseAddr("::2"),
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.ip4, testCase.h.GetCurrentIP4())
+			assert.Equal(t, testCase.ip6, testCase.h.GetCurrentIP6())
+		})
+	}
+}
+
+func Test_GetTypicalChangeTime(t *testing.T) {
+	t.Parallel()
+	at := func(day, hour, minute int) HistoryEvent {
+		return HistoryEvent{Time: time.Date(2025, time.January, day, hour, minute, 0, 0, time.UTC)}
+	}
+	testCases := map[string]struct {
+		h         History
+		timeOfDay time.Duration
+		ok        bool
+	}{
+		"empty_history": {
+			h: History{},
+		},
+		"too_few_changes": {
+			h: History{at(1, 12, 0), at(2, 3, 5), at(3, 3, 10)},
+		},
+		"daily_reconnect": {
+			h: History{
+				at(1, 12, 0), // initial event
+				at(2, 3, 5),
+				at(3, 3, 12),
+				at(4, 17, 40),
+				at(5, 3, 8),
+			},
+			timeOfDay: 3*time.Hour + 12*time.Minute,
+			ok:        true,
+		},
+		"scattered_changes": {
+			h: History{
+				at(1, 12, 0),
+				at(2, 3, 5),
+				at(3, 3, 12),
+				at(4, 3, 8),
+				at(5, 10, 0),
+				at(6, 14, 0),
+				at(7, 20, 0),
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			timeOfDay, ok := testCase.h.GetTypicalChangeTime(time.UTC)
+			assert.Equal(t, testCase.timeOfDay, timeOfDay)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
@@ -4,11 +4,17 @@ package models
 // It is exported so that the HTML template engine can render it.
 type HTMLData struct {
 	Rows []HTMLRow
+	// APITokenRequired is true if the pin forms
+	// must be submitted with the API token.
+	APITokenRequired bool
 }
 
 // HTMLRow contains HTML fields to be rendered
 // It is exported so that the HTML template engine can render it.
 type HTMLRow struct {
+	ID uint
+	// Key identifies the record in pin and unpin requests.
+	Key         string
 	Domain      string
 	Owner       string
 	Provider    string
@@ -16,4 +22,12 @@ type HTMLRow struct {
 	Status      string
 	CurrentIP   string
 	PreviousIPs string
+	// PinnedUntil is empty if the record is not pinned.
+	PinnedUntil string
+	// HistoryIPs are the unique IP addresses of the record history,
+	// suggested to pin the record to.
+	HistoryIPs []string
+	// RateLimit is the remaining request budget of the provider
+	// account of the record, and is empty if unknown.
+	RateLimit string
 }
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Pin is a manual override of the IP address of a record,
+// during which the record is not updated.
+type Pin struct {
+	IP    netip.Addr `json:"ip"`
+	Until time.Time  `json:"until"`
+}
+
+// Expired returns true if the pin expired at the time given.
+func (p *Pin) Expired(now time.Time) bool {
+	return !now.Before(p.Until)
+}
@@ -0,0 +1,116 @@
+package mqtt
+
+import (
+	"encoding/json"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// discoveryConfig is a Home Assistant MQTT discovery configuration,
+// see https://www.home-assistant.io/integrations/mqtt/#mqtt-discovery
+type discoveryConfig struct {
+	Name              string          `json:"name"`
+	UniqueID          string          `json:"unique_id"`
+	ObjectID          string          `json:"object_id"`
+	StateTopic        string          `json:"state_topic,omitempty"`
+	CommandTopic      string          `json:"command_topic,omitempty"`
+	PayloadPress      string          `json:"payload_press,omitempty"`
+	DeviceClass       string          `json:"device_class,omitempty"`
+	Icon              string          `json:"icon,omitempty"`
+	AvailabilityTopic string          `json:"availability_topic"`
+	Device            discoveryDevice `json:"device"`
+}
+
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+	SWVersion    string   `json:"sw_version,omitempty"`
+}
+
+// makeDiscoveryConfigs returns the Home Assistant discovery
+// configuration payloads to publish, keyed by topic.
+func makeDiscoveryConfigs(settings Settings, records []records.Record) (
+	configs map[string][]byte, err error,
+) {
+	nodeID := slugify(settings.ClientID)
+	device := discoveryDevice{
+		Identifiers:  []string{nodeID},
+		Name:         "DDNS Updater",
+		Manufacturer: "qdm12",
+		Model:        "ddns-updater",
+		SWVersion:    settings.Version,
+	}
+	availability := availabilityTopic(settings.TopicPrefix)
+
+	type entity struct {
+		component string
+		config    discoveryConfig
+	}
+	entities := []entity{
+		{component: "button", config: discoveryConfig{
+			Name:         "Force update",
+			ObjectID:     "force_update",
+			CommandTopic: commandTopic(settings.TopicPrefix),
+			PayloadPress: payloadPress,
+			Icon:         "mdi:refresh",
+		}},
+		{component: "sensor", config: discoveryConfig{
+			Name:       "Public IPv4",
+			ObjectID:   "public_ipv4",
+			StateTopic: settings.TopicPrefix + "/public_ipv4",
+			Icon:       "mdi:ip-network",
+		}},
+		{component: "sensor", config: discoveryConfig{
+			Name:       "Public IPv6",
+			ObjectID:   "public_ipv6",
+			StateTopic: settings.TopicPrefix + "/public_ipv6",
+			Icon:       "mdi:ip-network",
+		}},
+	}
+
+	for _, record := range records {
+		objectID := recordObjectID(record)
+		recordPrefix := settings.TopicPrefix + "/" + objectID
+		name := record.Provider.BuildDomainName() + " (" +
+			record.IPVersionString() + ")"
+		entities = append(entities,
+			entity{component: "sensor", config: discoveryConfig{
+				Name:       name + " status",
+				ObjectID:   objectID + "_status",
+				StateTopic: recordPrefix + "/status",
+				Icon:       "mdi:dns",
+			}},
+			entity{component: "sensor", config: discoveryConfig{
+				Name:       name + " IP",
+				ObjectID:   objectID + "_ip",
+				StateTopic: recordPrefix + "/ip",
+				Icon:       "mdi:ip-network",
+			}},
+			entity{component: "sensor", config: discoveryConfig{
+				Name:        name + " last update",
+				ObjectID:    objectID + "_last_update",
+				StateTopic:  recordPrefix + "/last_update",
+				DeviceClass: "timestamp",
+			}},
+		)
+	}
+
+	configs = make(map[string][]byte, len(entities))
+	for _, entity := range entities {
+		config := entity.config
+		config.ObjectID = nodeID + "_" + config.ObjectID
+		config.UniqueID = config.ObjectID
+		config.AvailabilityTopic = availability
+		config.Device = device
+		payload, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		topic := settings.DiscoveryPrefix + "/" + entity.component + "/" +
+			nodeID + "/" + config.ObjectID + "/config"
+		configs[topic] = payload
+	}
+	return configs, nil
+}
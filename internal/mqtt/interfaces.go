@@ -0,0 +1,27 @@
+package mqtt
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+type Database interface {
+	SelectAll() (records []records.Record)
+}
+
+type UpdateForcer interface {
+	ForceUpdate(ctx context.Context) (errors []error)
+}
+
+type PublicIPsGetter interface {
+	PublicIPs() (ipv4, ipv6 netip.Addr)
+}
+
+type Logger interface {
+	Debug(s string)
+	Info(s string)
+	Warn(s string)
+	Error(s string)
+}
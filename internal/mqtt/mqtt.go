@@ -0,0 +1,222 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Service publishes the records state and public IP addresses
+// as retained MQTT messages, together with Home Assistant discovery
+// configurations, and triggers updates on command messages.
+type Service struct {
+	// Injected fields
+	settings Settings
+	db       Database
+	forcer   UpdateForcer
+	ips      PublicIPsGetter
+	logger   Logger
+
+	// Internal fields
+	client    paho.Client
+	connected chan struct{}
+	force     chan struct{}
+	// published maps topics to their last published payload,
+	// and is only accessed by the run goroutine.
+	published map[string]string
+	runCancel context.CancelFunc
+	done      <-chan struct{}
+}
+
+func New(settings Settings, db Database, forcer UpdateForcer,
+	ips PublicIPsGetter, logger Logger,
+) *Service {
+	return &Service{
+		settings: settings,
+		db:       db,
+		forcer:   forcer,
+		ips:      ips,
+		logger:   logger,
+	}
+}
+
+func (s *Service) String() string {
+	return "mqtt"
+}
+
+const (
+	qos            = 1
+	publishTimeout = 5 * time.Second
+)
+
+func (s *Service) Start(ctx context.Context) (runError <-chan error, startErr error) {
+	s.connected = make(chan struct{}, 1)
+	s.force = make(chan struct{}, 1)
+	s.published = make(map[string]string)
+
+	options := paho.NewClientOptions().
+		AddBroker(s.settings.Address).
+		SetClientID(s.settings.ClientID).
+		SetUsername(s.settings.Username).
+		SetPassword(s.settings.Password).
+		SetWill(availabilityTopic(s.settings.TopicPrefix), payloadOffline, qos, true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(func(paho.Client) {
+			select {
+			case s.connected <- struct{}{}:
+			default:
+			}
+		}).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			s.logger.Warn("connection lost: " + err.Error())
+		})
+	s.client = paho.NewClient(options)
+	// The connection is retried in the background if the broker is
+	// unreachable, so the token is not waited for.
+	_ = s.client.Connect()
+
+	ready := make(chan struct{})
+	runCtx, runCancel := context.WithCancel(context.Background())
+	s.runCancel = runCancel
+	done := make(chan struct{})
+	s.done = done
+	go s.run(runCtx, ready, done) //nolint:contextcheck
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return nil, s.Stop()
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (s *Service) run(ctx context.Context, ready chan<- struct{},
+	done chan<- struct{},
+) {
+	defer close(done)
+	ticker := time.NewTicker(s.settings.Period)
+	close(ready)
+
+	for {
+		select {
+		case <-s.connected:
+			s.logger.Info("connected to " + s.settings.Address)
+			s.onConnect()
+		case <-ticker.C:
+			s.publishAll()
+		case <-s.force:
+			s.logger.Info("update forced by command message")
+			errs := s.forcer.ForceUpdate(ctx)
+			for _, err := range errs {
+				s.logger.Error(err.Error())
+			}
+			s.publishAll()
+		case <-ctx.Done():
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func (s *Service) onConnect() {
+	// Retained messages may have been lost by the broker,
+	// so everything is published again.
+	clear(s.published)
+
+	s.publish(availabilityTopic(s.settings.TopicPrefix), payloadOnline)
+
+	token := s.client.Subscribe(commandTopic(s.settings.TopicPrefix), qos,
+		func(_ paho.Client, message paho.Message) {
+			if string(message.Payload()) != payloadPress {
+				return
+			}
+			select {
+			case s.force <- struct{}{}:
+			default: // an update is already pending
+			}
+		})
+	err := waitToken(token)
+	if err != nil {
+		s.logger.Error("subscribing to command topic: " + err.Error())
+	}
+
+	s.publishAll()
+}
+
+// publishAll publishes the discovery configurations and states
+// which changed since they were last published, and clears
+// retained messages for topics which are no longer used.
+func (s *Service) publishAll() {
+	records := s.db.SelectAll()
+
+	payloads, err := makeDiscoveryConfigs(s.settings, records)
+	if err != nil {
+		s.logger.Error("making discovery configurations: " + err.Error())
+		return
+	}
+	topicToPayload := make(map[string]string, len(payloads))
+	for topic, payload := range payloads {
+		topicToPayload[topic] = string(payload)
+	}
+
+	publicIPv4, publicIPv6 := s.ips.PublicIPs()
+	states := makeStates(s.settings.TopicPrefix, records, publicIPv4, publicIPv6)
+	for topic, payload := range states {
+		topicToPayload[topic] = payload
+	}
+
+	for topic := range s.published {
+		_, stillUsed := topicToPayload[topic]
+		if stillUsed || topic == availabilityTopic(s.settings.TopicPrefix) {
+			continue
+		}
+		// Empty retained payload removes the retained message
+		// and the Home Assistant entity for discovery topics.
+		s.publish(topic, "")
+		delete(s.published, topic)
+	}
+
+	for topic, payload := range topicToPayload {
+		if s.published[topic] == payload {
+			continue
+		}
+		s.publish(topic, payload)
+	}
+}
+
+func (s *Service) publish(topic, payload string) {
+	if !s.client.IsConnectionOpen() {
+		return
+	}
+	token := s.client.Publish(topic, qos, true, payload)
+	err := waitToken(token)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("publishing to topic %s: %s", topic, err))
+		return
+	}
+	s.logger.Debug("published " + payload + " to " + topic)
+	s.published[topic] = payload
+}
+
+var ErrTimeout = errors.New("timed out")
+
+func waitToken(token paho.Token) (err error) {
+	if !token.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("%w: after %s", ErrTimeout, publishTimeout)
+	}
+	return token.Error()
+}
+
+func (s *Service) Stop() (err error) {
+	s.runCancel()
+	<-s.done
+	if s.client.IsConnectionOpen() {
+		s.publish(availabilityTopic(s.settings.TopicPrefix), payloadOffline)
+	}
+	const quiesceMilliseconds = 250
+	s.client.Disconnect(quiesceMilliseconds)
+	return nil
+}
@@ -0,0 +1,140 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	providerconstants "github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDatabase struct {
+	records []records.Record
+}
+
+func (d *testDatabase) SelectAll() []records.Record { return d.records }
+
+type testForcer struct {
+	forced chan struct{}
+}
+
+func (f *testForcer) ForceUpdate(context.Context) []error {
+	f.forced <- struct{}{}
+	return nil
+}
+
+type testIPs struct{}
+
+func (testIPs) PublicIPs() (ipv4, ipv6 netip.Addr) {
+	return netip.MustParseAddr("1.2.3.4"), netip.Addr{}
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_Service(t *testing.T) {
+	t.Parallel()
+
+	broker := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	err := broker.AddHook(new(auth.AllowHook), nil)
+	require.NoError(t, err)
+	listener := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	err = broker.AddListener(listener)
+	require.NoError(t, err)
+	go func() { _ = broker.Serve() }()
+	t.Cleanup(func() { _ = broker.Close() })
+
+	var mutex sync.Mutex
+	received := make(map[string]string)
+	err = broker.Subscribe("#", 1, func(_ *mochi.Client, _ packets.Subscription, packet packets.Packet) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		received[packet.TopicName] = string(packet.Payload)
+	})
+	require.NoError(t, err)
+
+	recordProvider, err := provider.New(providerconstants.Example,
+		json.RawMessage(`{"username":"user","password":"pass"}`),
+		"example.com", "sub", ipversion.IP4, netip.Prefix{}, nil, nil)
+	require.NoError(t, err)
+	record := records.New(recordProvider, records.Settings{}, []models.HistoryEvent{{
+		IP:   netip.MustParseAddr("1.2.3.4"),
+		Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}})
+	record.Status = constants.SUCCESS
+	db := &testDatabase{records: []records.Record{record}}
+	forcer := &testForcer{forced: make(chan struct{})}
+
+	settings := Settings{
+		Address:         "tcp://" + listener.Address(),
+		ClientID:        "ddns-updater",
+		TopicPrefix:     "ddns-updater",
+		DiscoveryPrefix: "homeassistant",
+		Period:          time.Hour,
+		Version:         "test",
+	}
+	service := New(settings, db, forcer, testIPs{}, noopLogger{})
+
+	_, err = service.Start(context.Background())
+	require.NoError(t, err)
+
+	expectedStates := map[string]string{
+		"ddns-updater/availability":                                          "online",
+		"ddns-updater/public_ipv4":                                           "1.2.3.4",
+		"ddns-updater/public_ipv6":                                           "None",
+		"ddns-updater/sub_example_com_ipv4/status":                           "success",
+		"ddns-updater/sub_example_com_ipv4/ip":                               "1.2.3.4",
+		"ddns-updater/sub_example_com_ipv4/last_update":                      "2024-01-02T03:04:05Z",
+		"homeassistant/button/ddns_updater/ddns_updater_force_update/config": "",
+	}
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for topic, payload := range expectedStates {
+			receivedPayload, ok := received[topic]
+			if !ok || (payload != "" && receivedPayload != payload) {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = broker.Publish("ddns-updater/force_update", []byte("PRESS"), false, 1)
+	require.NoError(t, err)
+	select {
+	case <-forcer.forced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("update was not forced")
+	}
+
+	err = service.Stop()
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return received["ddns-updater/availability"] == "offline"
+	}, 5*time.Second, 10*time.Millisecond)
+}
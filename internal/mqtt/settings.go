@@ -0,0 +1,19 @@
+package mqtt
+
+import "time"
+
+type Settings struct {
+	// Address is the broker address, for example tcp://192.168.1.2:1883.
+	Address  string
+	Username string
+	Password string
+	ClientID string
+	// TopicPrefix is the prefix of all the state and command topics.
+	TopicPrefix string
+	// DiscoveryPrefix is the Home Assistant MQTT discovery prefix.
+	DiscoveryPrefix string
+	// Period is the period to check for state changes to publish.
+	Period time.Duration
+	// Version is the program version advertised to Home Assistant.
+	Version string
+}
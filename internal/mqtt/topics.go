@@ -0,0 +1,86 @@
+package mqtt
+
+import (
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+const (
+	// payloadNone is interpreted as an unknown state by Home Assistant.
+	payloadNone    = "None"
+	payloadOnline  = "online"
+	payloadOffline = "offline"
+	payloadPress   = "PRESS"
+)
+
+func availabilityTopic(prefix string) string {
+	return prefix + "/availability"
+}
+
+func commandTopic(prefix string) string {
+	return prefix + "/force_update"
+}
+
+// recordObjectID returns an identifier for the record usable in
+// MQTT topics and as Home Assistant object id.
+func recordObjectID(record records.Record) string {
+	return slugify(record.Provider.BuildDomainName() + "_" +
+		record.IPVersionString())
+}
+
+func slugify(s string) string {
+	s = strings.ToLower(s)
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// makeStates returns the state payloads to publish, keyed by topic.
+func makeStates(prefix string, records []records.Record,
+	publicIPv4, publicIPv6 netip.Addr,
+) (states map[string]string) {
+	const statesPerRecord = 3
+	const globalStates = 2
+	states = make(map[string]string, globalStates+statesPerRecord*len(records))
+	states[prefix+"/public_ipv4"] = ipToPayload(publicIPv4)
+	states[prefix+"/public_ipv6"] = ipToPayload(publicIPv6)
+
+	for _, record := range records {
+		recordPrefix := prefix + "/" + recordObjectID(record)
+		states[recordPrefix+"/status"] = string(record.Status)
+		states[recordPrefix+"/ip"] = ipsToPayload(record.CurrentIPs())
+		states[recordPrefix+"/last_update"] = timeToPayload(record.History.GetSuccessTime())
+	}
+	return states
+}
+
+func ipsToPayload(ips []netip.Addr) string {
+	if len(ips) == 0 {
+		return payloadNone
+	}
+	ipStrings := make([]string, len(ips))
+	for i, ip := range ips {
+		ipStrings[i] = ip.String()
+	}
+	return strings.Join(ipStrings, ",")
+}
+
+func ipToPayload(ip netip.Addr) string {
+	if !ip.IsValid() {
+		return payloadNone
+	}
+	return ip.String()
+}
+
+func timeToPayload(t time.Time) string {
+	if t.IsZero() {
+		return payloadNone
+	}
+	return t.Format(time.RFC3339)
+}
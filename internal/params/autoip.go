@@ -0,0 +1,58 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+var (
+	ErrIPSourceNotValid   = errors.New("IP source is not valid")
+	ErrAutoIPStalePolicy  = errors.New("stale policy is not supported with the auto IP source")
+	ErrAutoIPNotSupported = errors.New("provider does not support the auto IP source")
+)
+
+const (
+	ipSourcePublic  = "public"
+	ipSourceAuto    = "auto"
+	ipSourceUplinks = "uplinks"
+	ipSourceReports = "reports"
+)
+
+// parseIPSource returns true if the IP source given is "auto", in which
+// case the IP address is detected by the provider from the update request.
+// The multi-value IP sources "uplinks" and "reports" are handled by
+// makeMultiIPSettings.
+func parseIPSource(ipSource string, stalePolicy records.StalePolicy) (
+	autoIP bool, err error,
+) {
+	switch ipSource {
+	case "", ipSourcePublic, ipSourceUplinks, ipSourceReports:
+		return false, nil
+	case ipSourceAuto:
+	default:
+		return false, fmt.Errorf("%w: %q must be one of %q, %q, %q or %q",
+			ErrIPSourceNotValid, ipSource, ipSourcePublic, ipSourceAuto,
+			ipSourceUplinks, ipSourceReports)
+	}
+
+	// The public IP addresses are not fetched for auto records,
+	// so their records cannot be detected as stale.
+	if stalePolicy != records.StalePolicyKeep {
+		return false, fmt.Errorf("%w: %q", ErrAutoIPStalePolicy, stalePolicy)
+	}
+	return true, nil
+}
+
+func checkAutoIPSupport(recordProvider provider.Provider, autoIP bool) (err error) {
+	if !autoIP {
+		return nil
+	}
+	_, ok := recordProvider.(provider.AutoUpdater)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrAutoIPNotSupported, recordProvider.String())
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseIPSource(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ipSource    string
+		stalePolicy records.StalePolicy
+		autoIP      bool
+		errWrapped  error
+		errMessage  string
+	}{
+		"empty": {
+			stalePolicy: records.StalePolicyKeep,
+		},
+		"public": {
+			ipSource:    "public",
+			stalePolicy: records.StalePolicyDelete,
+		},
+		"auto": {
+			ipSource:    "auto",
+			stalePolicy: records.StalePolicyKeep,
+			autoIP:      true,
+		},
+		"invalid": {
+			ipSource:   "provider",
+			errWrapped: ErrIPSourceNotValid,
+			errMessage: `IP source is not valid: "provider" must be one of "public", "auto", "uplinks" or "reports"`,
+		},
+		"auto_with_stale_policy": {
+			ipSource:    "auto",
+			stalePolicy: records.StalePolicyDelete,
+			errWrapped:  ErrAutoIPStalePolicy,
+			errMessage:  `stale policy is not supported with the auto IP source: "delete"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			autoIP, err := parseIPSource(testCase.ipSource, testCase.stalePolicy)
+
+			assert.Equal(t, testCase.autoIP, autoIP)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"text/template"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+var (
+	ErrContentFieldUnexpected    = errors.New("content is set without a record type")
+	ErrContentRecordTypeNotValid = errors.New("record type is not valid")
+	ErrContentNotSet             = errors.New("content is not set")
+	ErrContentTemplateNotValid   = errors.New("content template is not valid")
+	ErrContentFirstFieldNotFixed = errors.New("content first field depends on the IP addresses")
+	ErrContentSettingUnsupported = errors.New("setting is not supported for content records")
+	ErrContentNotSupported       = errors.New("provider does not support content records")
+)
+
+// makeContentSettings returns the record type and the parsed content
+// template of the record, which are empty and nil for A and AAAA records.
+func makeContentSettings(common commonSettings, settings records.Settings) (
+	recordType string, content *template.Template, err error,
+) {
+	if common.RecordType == "" {
+		if common.Content != "" {
+			return "", nil, fmt.Errorf("%w", ErrContentFieldUnexpected)
+		}
+		return "", nil, nil
+	}
+
+	recordType = strings.ToUpper(common.RecordType)
+	switch recordType {
+	case constants.TXT, constants.SPF, constants.SVCB, constants.HTTPS:
+	default:
+		return "", nil, fmt.Errorf("%w: %q must be one of %s, %s, %s or %s",
+			ErrContentRecordTypeNotValid, common.RecordType,
+			constants.TXT, constants.SPF, constants.SVCB, constants.HTTPS)
+	}
+
+	if common.Content == "" {
+		return "", nil, fmt.Errorf("%w", ErrContentNotSet)
+	}
+	content, err = template.New("content").Option("missingkey=error").Parse(common.Content)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrContentTemplateNotValid, err)
+	}
+
+	// The content is derived from the public IP addresses,
+	// which cannot be combined with other IP address sources.
+	switch {
+	case settings.AutoIP:
+		return "", nil, fmt.Errorf("%w: ip source %q", ErrContentSettingUnsupported, ipSourceAuto)
+	case settings.MultiIP != nil:
+		return "", nil, fmt.Errorf("%w: ip source %q", ErrContentSettingUnsupported, common.IPSource)
+	case settings.StalePolicy != records.StalePolicyKeep:
+		return "", nil, fmt.Errorf("%w: stale policy %q", ErrContentSettingUnsupported,
+			settings.StalePolicy)
+	case settings.Failover != nil:
+		return "", nil, fmt.Errorf("%w: failover", ErrContentSettingUnsupported)
+	}
+	return recordType, content, nil
+}
+
+// checkContentSupport checks the provider given supports setting the
+// content of records, and that the first field of the content does not
+// depend on the IP addresses, since providers use it to find the record
+// to replace amongst the records of the same type.
+func checkContentSupport(recordProvider provider.Provider, settings records.Settings) (err error) {
+	if settings.RecordType == "" {
+		return nil
+	}
+	_, ok := recordProvider.(provider.ContentUpdater)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrContentNotSupported, recordProvider.String())
+	}
+
+	// IP addresses reserved for documentation, see RFC 5737 and RFC 3849.
+	ipSets := [][]netip.Addr{
+		{netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("2001:db8::1")},
+		{netip.MustParseAddr("198.51.100.2"), netip.MustParseAddr("2001:db8::2")},
+	}
+	firstFields := make([]string, len(ipSets))
+	for i, ips := range ipSets {
+		content, err := records.RenderContent(settings.Content, recordProvider, ips)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrContentTemplateNotValid, err)
+		}
+		fields := strings.Fields(content)
+		if len(fields) == 0 {
+			return fmt.Errorf("%w: rendered content is empty", ErrContentTemplateNotValid)
+		}
+		firstFields[i] = fields[0]
+	}
+	if firstFields[0] != firstFields[1] {
+		return fmt.Errorf("%w: rendered as %q and %q", ErrContentFirstFieldNotFixed,
+			firstFields[0], firstFields[1])
+	}
+	return nil
+}
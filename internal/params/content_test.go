@@ -0,0 +1,169 @@
+package params
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"text/template"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_makeContentSettings(t *testing.T) {
+	t.Parallel()
+
+	keepSettings := records.Settings{StalePolicy: records.StalePolicyKeep}
+
+	testCases := map[string]struct {
+		common     commonSettings
+		settings   records.Settings
+		recordType string
+		errWrapped error
+		errMessage string
+	}{
+		"address_record": {
+			settings: keepSettings,
+		},
+		"txt_lowercase": {
+			common:     commonSettings{RecordType: "txt", Content: "v=spf1 ip4:{{.IPv4}} -all"},
+			settings:   keepSettings,
+			recordType: "TXT",
+		},
+		"content_without_record_type": {
+			common:     commonSettings{Content: "v=spf1 -all"},
+			settings:   keepSettings,
+			errWrapped: ErrContentFieldUnexpected,
+			errMessage: "content is set without a record type",
+		},
+		"record_type_not_valid": {
+			common:     commonSettings{RecordType: "MX", Content: "10 mail"},
+			settings:   keepSettings,
+			errWrapped: ErrContentRecordTypeNotValid,
+			errMessage: `record type is not valid: "MX" must be one of TXT, SPF, SVCB or HTTPS`,
+		},
+		"content_not_set": {
+			common:     commonSettings{RecordType: "TXT"},
+			settings:   keepSettings,
+			errWrapped: ErrContentNotSet,
+			errMessage: "content is not set",
+		},
+		"template_not_valid": {
+			common:     commonSettings{RecordType: "TXT", Content: "{{.IPv4"},
+			settings:   keepSettings,
+			errWrapped: ErrContentTemplateNotValid,
+			errMessage: "content template is not valid: template: content:1: unclosed action",
+		},
+		"auto_ip": {
+			common: commonSettings{RecordType: "TXT", Content: "ip={{.IP}}"},
+			settings: records.Settings{
+				StalePolicy: records.StalePolicyKeep,
+				AutoIP:      true,
+			},
+			errWrapped: ErrContentSettingUnsupported,
+			errMessage: `setting is not supported for content records: ip source "auto"`,
+		},
+		"failover": {
+			common: commonSettings{RecordType: "TXT", Content: "ip={{.IP}}"},
+			settings: records.Settings{
+				StalePolicy: records.StalePolicyKeep,
+				Failover:    &failover.Settings{},
+			},
+			errWrapped: ErrContentSettingUnsupported,
+			errMessage: "setting is not supported for content records: failover",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recordType, content, err := makeContentSettings(testCase.common, testCase.settings)
+
+			assert.Equal(t, testCase.recordType, recordType)
+			assert.Equal(t, testCase.recordType != "", content != nil)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_checkContentSupport(t *testing.T) {
+	t.Parallel()
+
+	makeProvider := func(providerName models.Provider, ttl *uint32) provider.Provider {
+		data := json.RawMessage(`{"zone_identifier":"zone","token":"token",` +
+			`"username":"user","password":"pass"}`)
+		recordProvider, err := provider.New(providerName, data, "example.com",
+			"@", ipversion.IP4, netip.Prefix{}, nil, ttl)
+		require.NoError(t, err)
+		return recordProvider
+	}
+	ttl := uint32(1)
+
+	testCases := map[string]struct {
+		provider   provider.Provider
+		recordType string
+		content    string
+		errWrapped error
+		errMessage string
+	}{
+		"address_record": {
+			provider: makeProvider(constants.NoIP, nil),
+		},
+		"fixed_first_field": {
+			provider:   makeProvider(constants.Cloudflare, &ttl),
+			recordType: "SVCB",
+			content:    "1 . ipv4hint={{.IPv4}} ipv6hint={{.IPv6}}",
+		},
+		"provider_not_supported": {
+			provider:   makeProvider(constants.NoIP, nil),
+			recordType: "TXT",
+			content:    "ip={{.IP}}",
+			errWrapped: ErrContentNotSupported,
+			errMessage: "provider does not support content records: " +
+				"[domain: example.com | owner: @ | provider: noip | ip: ipv4]",
+		},
+		"first_field_not_fixed": {
+			provider:   makeProvider(constants.Cloudflare, &ttl),
+			recordType: "TXT",
+			content:    "ip={{.IPv4}}",
+			errWrapped: ErrContentFirstFieldNotFixed,
+			errMessage: "content first field depends on the IP addresses: " +
+				`rendered as "ip=192.0.2.1" and "ip=198.51.100.2"`,
+		},
+		"empty_content": {
+			provider:   makeProvider(constants.Cloudflare, &ttl),
+			recordType: "TXT",
+			content:    "{{if false}}x{{end}}",
+			errWrapped: ErrContentTemplateNotValid,
+			errMessage: "content template is not valid: rendered content is empty",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := records.Settings{RecordType: testCase.recordType}
+			if testCase.content != "" {
+				settings.Content = template.Must(template.New("content").Parse(testCase.content))
+			}
+
+			err := checkContentSupport(testCase.provider, settings)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
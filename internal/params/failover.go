@@ -0,0 +1,102 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type failoverSettings struct {
+	Check  string `json:"check"`
+	Target string `json:"target"`
+	// Interval defaults to 30s if unset.
+	Interval string `json:"interval,omitempty"`
+	// Timeout defaults to 5s if unset.
+	Timeout string `json:"timeout,omitempty"`
+	// Threshold defaults to 3 if unset.
+	Threshold    uint       `json:"threshold,omitempty"`
+	BackupIP     netip.Addr `json:"backup_ip,omitempty"`
+	BackupRecord string     `json:"backup_record,omitempty"`
+}
+
+var (
+	ErrFailoverIntervalNotValid        = errors.New("failover interval is not valid")
+	ErrFailoverTimeoutNotValid         = errors.New("failover timeout is not valid")
+	ErrFailoverBackupIPVersionMismatch = errors.New("failover backup IP address has the wrong IP version")
+	ErrFailoverBackupRecordNotDefined  = errors.New("failover backup record is not defined")
+)
+
+func makeFailoverSettings(jsonSettings *failoverSettings, ipVersion ipversion.IPVersion) (
+	settings *failover.Settings, err error,
+) {
+	if jsonSettings == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	const (
+		defaultInterval  = 30 * time.Second
+		defaultTimeout   = 5 * time.Second
+		defaultThreshold = 3
+	)
+	settings = &failover.Settings{
+		Check:        failover.CheckType(jsonSettings.Check),
+		Target:       jsonSettings.Target,
+		Interval:     defaultInterval,
+		Timeout:      defaultTimeout,
+		Threshold:    jsonSettings.Threshold,
+		BackupIP:     jsonSettings.BackupIP,
+		BackupRecord: jsonSettings.BackupRecord,
+	}
+	if jsonSettings.Interval != "" {
+		settings.Interval, err = time.ParseDuration(jsonSettings.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailoverIntervalNotValid, err)
+		}
+	}
+	if jsonSettings.Timeout != "" {
+		settings.Timeout, err = time.ParseDuration(jsonSettings.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailoverTimeoutNotValid, err)
+		}
+	}
+	if settings.Threshold == 0 {
+		settings.Threshold = defaultThreshold
+	}
+
+	err = settings.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating failover settings: %w", err)
+	}
+
+	backupIP := settings.BackupIP
+	if (ipVersion == ipversion.IP4 && backupIP.Is6()) ||
+		(ipVersion == ipversion.IP6 && backupIP.Is4()) {
+		return nil, fmt.Errorf("%w: %s for IP version %s",
+			ErrFailoverBackupIPVersionMismatch, backupIP, ipVersion)
+	}
+	return settings, nil
+}
+
+func checkFailoverBackupRecords(recs []records.Record) (err error) {
+	domains := make(map[string]struct{}, len(recs))
+	for _, record := range recs {
+		domains[record.Provider.BuildDomainName()] = struct{}{}
+	}
+	for _, record := range recs {
+		settings := record.Settings.Failover
+		if settings == nil || settings.BackupRecord == "" {
+			continue
+		}
+		_, exists := domains[settings.BackupRecord]
+		if !exists {
+			return fmt.Errorf("%w: %s for record %s",
+				ErrFailoverBackupRecordNotDefined, settings.BackupRecord, record.Provider)
+		}
+	}
+	return nil
+}
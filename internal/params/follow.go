@@ -0,0 +1,51 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+var (
+	ErrFollowNotValid           = errors.New("follow domain name is not valid")
+	ErrFollowSettingUnsupported = errors.New("setting is not supported for follow records")
+	ErrFollowSelf               = errors.New("record follows itself")
+)
+
+// parseFollow returns the lowercase fully qualified domain name the
+// record follows, which is empty if the record does not follow any.
+func parseFollow(follow string, settings records.Settings) (followed string, err error) {
+	if follow == "" {
+		return "", nil
+	}
+	followed = strings.ToLower(strings.TrimSuffix(follow, "."))
+	err = utils.CheckDomain(followed)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrFollowNotValid, err)
+	}
+
+	// The IP addresses are the ones of the followed domain name,
+	// which cannot be combined with other IP address sources.
+	switch {
+	case settings.AutoIP:
+		return "", fmt.Errorf("%w: ip source %q", ErrFollowSettingUnsupported, ipSourceAuto)
+	case settings.MultiIP != nil:
+		return "", fmt.Errorf("%w: multi-value ip source", ErrFollowSettingUnsupported)
+	case settings.Uplink != "":
+		return "", fmt.Errorf("%w: uplink", ErrFollowSettingUnsupported)
+	case settings.Failover != nil:
+		return "", fmt.Errorf("%w: failover", ErrFollowSettingUnsupported)
+	}
+	return followed, nil
+}
+
+func checkFollowSelf(recordProvider provider.Provider, followed string) (err error) {
+	if followed != "" && followed == strings.ToLower(recordProvider.BuildDomainName()) {
+		return fmt.Errorf("%w: %s", ErrFollowSelf, recordProvider.String())
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseFollow(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		follow     string
+		settings   records.Settings
+		followed   string
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {},
+		"normalized": {
+			follow:   "Home.Example.com.",
+			followed: "home.example.com",
+		},
+		"not_valid": {
+			follow:     "home..example.com",
+			errWrapped: ErrFollowNotValid,
+			errMessage: `follow domain name is not valid: domain name has invalid character: ` +
+				`label starts with '.' for domain "home..example.com"`,
+		},
+		"uplink": {
+			follow:     "home.example.com",
+			settings:   records.Settings{Uplink: "wan1"},
+			errWrapped: ErrFollowSettingUnsupported,
+			errMessage: "setting is not supported for follow records: uplink",
+		},
+		"failover": {
+			follow:     "home.example.com",
+			settings:   records.Settings{Failover: &failover.Settings{}},
+			errWrapped: ErrFollowSettingUnsupported,
+			errMessage: "setting is not supported for follow records: failover",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			followed, err := parseFollow(testCase.follow, testCase.settings)
+
+			assert.Equal(t, testCase.followed, followed)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/ipexpr"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+var (
+	ErrIPExpressionFamilyDuplicate = errors.New("several IP expressions are set for the same IP family")
+	ErrIPExpressionFamilyMismatch  = errors.New("IP expression does not match the IP version")
+	ErrIPExpressionSuffixConflict  = errors.New("IPv6 expression cannot be combined with the IPv6 suffix")
+	ErrIPExpressionUnsupported     = errors.New("setting is not supported with IP expressions")
+)
+
+// parseIPExpressions parses the IP expressions of the record,
+// allowing at most one expression per IP family.
+func parseIPExpressions(expressionStrings []string, ipVersion ipversion.IPVersion,
+	ipv6Suffix netip.Prefix, settings records.Settings,
+) (expressions []ipexpr.Expression, err error) {
+	if len(expressionStrings) == 0 {
+		return nil, nil
+	}
+
+	switch {
+	case settings.AutoIP:
+		return nil, fmt.Errorf("%w: ip source %q", ErrIPExpressionUnsupported, ipSourceAuto)
+	case settings.MultiIP != nil && settings.MultiIP.Reported:
+		return nil, fmt.Errorf("%w: ip source %q", ErrIPExpressionUnsupported, ipSourceReports)
+	}
+
+	expressions = make([]ipexpr.Expression, len(expressionStrings))
+	var hasIPv4, hasIPv6 bool
+	for i, expressionString := range expressionStrings {
+		expression, err := ipexpr.Parse(expressionString)
+		if err != nil {
+			return nil, fmt.Errorf("parsing IP expression: %w", err)
+		}
+
+		familyUsed := &hasIPv6
+		if expression.Is4() {
+			familyUsed = &hasIPv4
+		}
+		switch {
+		case *familyUsed:
+			return nil, fmt.Errorf("%w: %s", ErrIPExpressionFamilyDuplicate, expression)
+		case !settings.DualStack && ipVersion == ipversion.IP4 && !expression.Is4(),
+			!settings.DualStack && ipVersion == ipversion.IP6 && expression.Is4():
+			return nil, fmt.Errorf("%w: %s for IP version %s",
+				ErrIPExpressionFamilyMismatch, expression, ipVersion)
+		case !expression.Is4() && ipv6Suffix.IsValid():
+			return nil, fmt.Errorf("%w: %s and %s", ErrIPExpressionSuffixConflict,
+				expression, ipv6Suffix)
+		case expression.IsStatic() && settings.StalePolicy != records.StalePolicyKeep:
+			// the public IP address availability is not relevant
+			// for the record of a static IP address.
+			return nil, fmt.Errorf("%w: stale policy %q for %s", ErrIPExpressionUnsupported,
+				settings.StalePolicy, expression)
+		}
+		*familyUsed = true
+		expressions[i] = expression
+	}
+	return expressions, nil
+}
+
+var (
+	ErrIPv6PrefixIPVersion    = errors.New("IPv6 prefix is set for an IPv4 record")
+	ErrIPv6PrefixHostIDNotSet = errors.New("IPv6 prefix is set without host identifier")
+)
+
+// parseIPv6Prefix parses the definition of the delegated IPv6 prefix of
+// the record, which is nil if unset. The host identifier following the
+// prefix is either the host expression of the record or its IPv6 suffix.
+func parseIPv6Prefix(s string, ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix,
+	settings records.Settings,
+) (prefix *ipexpr.Prefix, err error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	hasHostExpression := false
+	for _, expression := range settings.IPExpressions {
+		hasHostExpression = hasHostExpression || expression.UsesPrefix()
+	}
+	switch {
+	case ipVersion == ipversion.IP4 && !settings.DualStack:
+		return nil, fmt.Errorf("%w", ErrIPv6PrefixIPVersion)
+	case !hasHostExpression && !ipv6Suffix.IsValid():
+		return nil, fmt.Errorf("%w", ErrIPv6PrefixHostIDNotSet)
+	}
+
+	parsed, err := ipexpr.ParsePrefix(s)
+	if err != nil {
+		return nil, fmt.Errorf("parsing IPv6 prefix: %w", err)
+	}
+	return &parsed, nil
+}
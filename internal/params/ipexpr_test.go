@@ -0,0 +1,175 @@
+package params
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/ipexpr"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseIPExpressions(t *testing.T) {
+	t.Parallel()
+
+	keepSettings := records.Settings{StalePolicy: records.StalePolicyKeep}
+
+	testCases := map[string]struct {
+		expressions []string
+		ipVersion   ipversion.IPVersion
+		ipv6Suffix  netip.Prefix
+		settings    records.Settings
+		count       int
+		errWrapped  error
+		errMessage  string
+	}{
+		"none": {
+			ipVersion: ipversion.IP4or6,
+			settings:  keepSettings,
+		},
+		"one_per_family": {
+			expressions: []string{"static(203.0.113.10)", "suffix(::10/64)"},
+			ipVersion:   ipversion.IP4or6,
+			settings:    keepSettings,
+			count:       2,
+		},
+		"parse_error": {
+			expressions: []string{"static()"},
+			ipVersion:   ipversion.IP4,
+			settings:    keepSettings,
+			errWrapped:  ipexpr.ErrArgumentNotValid,
+			errMessage: `parsing IP expression: expression argument is not valid: ` +
+				`static(): ParseAddr(""): unable to parse IP`,
+		},
+		"family_duplicate": {
+			expressions: []string{"static(203.0.113.10)", "map(198.51.100.1=203.0.113.5)"},
+			ipVersion:   ipversion.IP4,
+			settings:    keepSettings,
+			errWrapped:  ErrIPExpressionFamilyDuplicate,
+			errMessage: "several IP expressions are set for the same IP family: " +
+				"map(198.51.100.1=203.0.113.5)",
+		},
+		"family_mismatch": {
+			expressions: []string{"suffix(::10/64)"},
+			ipVersion:   ipversion.IP4,
+			settings:    keepSettings,
+			errWrapped:  ErrIPExpressionFamilyMismatch,
+			errMessage:  "IP expression does not match the IP version: suffix(::10/64) for IP version ipv4",
+		},
+		"dual_stack": {
+			expressions: []string{"suffix(::10/64)"},
+			ipVersion:   ipversion.IP4,
+			settings:    records.Settings{StalePolicy: records.StalePolicyKeep, DualStack: true},
+			count:       1,
+		},
+		"ipv6_suffix_conflict": {
+			expressions: []string{"suffix(::10/64)"},
+			ipVersion:   ipversion.IP6,
+			ipv6Suffix:  netip.MustParsePrefix("::20/64"),
+			settings:    keepSettings,
+			errWrapped:  ErrIPExpressionSuffixConflict,
+			errMessage:  "IPv6 expression cannot be combined with the IPv6 suffix: suffix(::10/64) and ::20/64",
+		},
+		"auto_ip": {
+			expressions: []string{"static(203.0.113.10)"},
+			ipVersion:   ipversion.IP4,
+			settings:    records.Settings{StalePolicy: records.StalePolicyKeep, AutoIP: true},
+			errWrapped:  ErrIPExpressionUnsupported,
+			errMessage:  `setting is not supported with IP expressions: ip source "auto"`,
+		},
+		"static_stale_policy": {
+			expressions: []string{"static(203.0.113.10)"},
+			ipVersion:   ipversion.IP4,
+			settings:    records.Settings{StalePolicy: records.StalePolicyDelete},
+			errWrapped:  ErrIPExpressionUnsupported,
+			errMessage: `setting is not supported with IP expressions: ` +
+				`stale policy "delete" for static(203.0.113.10)`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expressions, err := parseIPExpressions(testCase.expressions, testCase.ipVersion,
+				testCase.ipv6Suffix, testCase.settings)
+
+			assert.Len(t, expressions, testCase.count)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_parseIPv6Prefix(t *testing.T) {
+	t.Parallel()
+
+	hostExpression, err := ipexpr.Parse("host(::10)")
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		s          string
+		ipVersion  ipversion.IPVersion
+		ipv6Suffix netip.Prefix
+		settings   records.Settings
+		set        bool
+		errWrapped error
+		errMessage string
+	}{
+		"unset": {
+			ipVersion: ipversion.IP6,
+		},
+		"host_expression": {
+			s:         "/56",
+			ipVersion: ipversion.IP6,
+			settings:  records.Settings{IPExpressions: []ipexpr.Expression{hostExpression}},
+			set:       true,
+		},
+		"ipv6_suffix": {
+			s:          "interface",
+			ipVersion:  ipversion.IP4or6,
+			ipv6Suffix: netip.MustParsePrefix("::10/64"),
+			set:        true,
+		},
+		"ipv4_record": {
+			s:          "/56",
+			ipVersion:  ipversion.IP4,
+			ipv6Suffix: netip.MustParsePrefix("::10/64"),
+			errWrapped: ErrIPv6PrefixIPVersion,
+			errMessage: "IPv6 prefix is set for an IPv4 record",
+		},
+		"no_host_identifier": {
+			s:          "/56",
+			ipVersion:  ipversion.IP6,
+			errWrapped: ErrIPv6PrefixHostIDNotSet,
+			errMessage: "IPv6 prefix is set without host identifier",
+		},
+		"not_valid": {
+			s:          "56",
+			ipVersion:  ipversion.IP6,
+			ipv6Suffix: netip.MustParsePrefix("::10/64"),
+			errWrapped: ipexpr.ErrPrefixFormat,
+			errMessage: `parsing IPv6 prefix: prefix is not in the format ` +
+				`/<bits> or interface[:<name>][/<bits>]: "56"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prefix, err := parseIPv6Prefix(testCase.s, testCase.ipVersion,
+				testCase.ipv6Suffix, testCase.settings)
+
+			assert.Equal(t, testCase.set, prefix != nil)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
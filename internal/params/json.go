@@ -14,6 +14,8 @@ import (
 	"github.com/qdm12/ddns-updater/internal/provider"
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
 	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/uplink"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 	"golang.org/x/net/publicsuffix"
 )
@@ -28,34 +30,88 @@ type commonSettings struct {
 	Owner      string       `json:"owner,omitempty"`
 	IPVersion  string       `json:"ip_version"`
 	IPv6Suffix netip.Prefix `json:"ipv6_suffix"`
+	// CreateIfMissing is nil if unset, in which case
+	// the provider default behavior is used.
+	CreateIfMissing *bool `json:"create_if_missing,omitempty"`
+	// TTL is nil if unset, in which case the provider default TTL is used.
+	TTL *uint32 `json:"ttl,omitempty"`
+	// StalePolicy is the policy for the record of an IP family which
+	// is no longer available, and defaults to "keep" if unset.
+	StalePolicy  string     `json:"stale_policy,omitempty"`
+	FallbackIPv4 netip.Addr `json:"fallback_ipv4,omitempty"`
+	FallbackIPv6 netip.Addr `json:"fallback_ipv6,omitempty"`
+	// Uplink is the name of the uplink to use for the record,
+	// and is empty to use the default network path.
+	Uplink string `json:"uplink,omitempty"`
+	// IPSource is "auto" to let the provider detect the IP address
+	// from the update request, "uplinks" to set the record to the public
+	// IP addresses of the uplinks listed in Uplinks, "reports" to set the
+	// record to the IP addresses reported by hosts, and defaults to
+	// "public" if unset.
+	IPSource string `json:"ip_source,omitempty"`
+	// Uplinks are the names of the uplinks for the "uplinks" IP source.
+	Uplinks []string `json:"uplinks,omitempty"`
+	// ReportExpiry is the duration after which a reported IP address
+	// is removed for the "reports" IP source, and defaults to 1h.
+	ReportExpiry string `json:"report_expiry,omitempty"`
+	// ReportToken is the token hosts must send to report
+	// their IP address for the "reports" IP source.
+	ReportToken string `json:"report_token,omitempty"`
+	// Failover is nil if the record has no failover.
+	Failover *failoverSettings `json:"failover,omitempty"`
+	// Period and Cron override the global update period for the record,
+	// and are mutually exclusive.
+	Period string `json:"period,omitempty"`
+	Cron   string `json:"cron,omitempty"`
+	// Cooldown overrides the global cooldown period for the record.
+	Cooldown string `json:"cooldown,omitempty"`
+	// Critical is true to update the record during maintenance windows.
+	Critical bool `json:"critical,omitempty"`
+	// RateLimit overrides the default rate limit for the provider
+	// account of the record, for example "10/1h".
+	RateLimit string `json:"rate_limit,omitempty"`
+	// IPExpressions compute the IP addresses of the record from the
+	// public IP addresses, for example "suffix(::10/64)".
+	IPExpressions []string `json:"ip_expressions,omitempty"`
+	// IPv6Prefix defines the delegated IPv6 prefix length used with host
+	// identifiers, for example "/56", "interface" or "interface:eth0".
+	IPv6Prefix string `json:"ipv6_prefix,omitempty"`
+	// Follow is the domain name whose IP addresses the record is set to,
+	// instead of the public IP addresses, and is empty if unset.
+	Follow string `json:"follow,omitempty"`
+	// RecordType is the type of the record to set with the Content
+	// template, such as "TXT", and is empty for A and AAAA records.
+	RecordType string `json:"record_type,omitempty"`
+	Content    string `json:"content,omitempty"`
 	// Retro values for warnings
 	ProviderIP *bool `json:"provider_ip,omitempty"`
 }
 
-// JSONProviders obtain the update settings from the JSON content,
-// first trying from the environment variable CONFIG and then from
-// the file config.json.
-func (r *Reader) JSONProviders(filePath string) (
-	providers []provider.Provider, warnings []string, err error,
+// JSONRecords obtain the records to update and the uplinks they use
+// from the JSON content, first trying from the environment variable
+// CONFIG and then from the file config.json.
+// The records returned have no history set.
+func (r *Reader) JSONRecords(filePath string) (
+	recs []records.Record, uplinks []uplink.Uplink, warnings []string, err error,
 ) {
-	providers, warnings, err = r.getProvidersFromEnv(filePath)
-	if providers != nil || warnings != nil || err != nil {
-		return providers, warnings, err
+	recs, uplinks, warnings, err = r.getRecordsFromEnv(filePath)
+	if recs != nil || uplinks != nil || warnings != nil || err != nil {
+		return recs, uplinks, warnings, err
 	}
-	return r.getProvidersFromFile(filePath)
+	return r.getRecordsFromFile(filePath)
 }
 
 var errWriteConfigToFile = errors.New("cannot write configuration to file")
 
-// getProvidersFromFile obtain the update settings from config.json.
-func (r *Reader) getProvidersFromFile(filePath string) (
-	providers []provider.Provider, warnings []string, err error,
+// getRecordsFromFile obtain the update settings from config.json.
+func (r *Reader) getRecordsFromFile(filePath string) (
+	recs []records.Record, uplinks []uplink.Uplink, warnings []string, err error,
 ) {
 	r.logger.Info("reading JSON config from file " + filePath)
 	bytes, err := r.readFile(filePath)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		r.logger.Info("file not found, creating an empty settings file")
@@ -65,44 +121,44 @@ func (r *Reader) getProvidersFromFile(filePath string) (
 		if err != nil {
 			err = fmt.Errorf("%w: %w", errWriteConfigToFile, err)
 		}
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	r.logger.Debug("config read: " + string(bytes))
 
 	return extractAllSettings(bytes)
 }
 
-// getProvidersFromEnv obtain the update settings from the environment variable CONFIG.
+// getRecordsFromEnv obtain the update settings from the environment variable CONFIG.
 // If the settings are valid, they are written to the filePath.
-func (r *Reader) getProvidersFromEnv(filePath string) (
-	providers []provider.Provider, warnings []string, err error,
+func (r *Reader) getRecordsFromEnv(filePath string) (
+	recs []records.Record, uplinks []uplink.Uplink, warnings []string, err error,
 ) {
 	s := os.Getenv("CONFIG")
 	if s == "" {
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 	r.logger.Info("reading JSON config from environment variable CONFIG")
 	r.logger.Debug("config read: " + s)
 
 	b := []byte(s)
 
-	providers, warnings, err = extractAllSettings(b)
+	recs, uplinks, warnings, err = extractAllSettings(b)
 	if err != nil {
-		return providers, warnings, fmt.Errorf("configuration given: %w", err)
+		return recs, uplinks, warnings, fmt.Errorf("configuration given: %w", err)
 	}
 
 	buffer := bytes.NewBuffer(nil)
 	err = json.Indent(buffer, b, "", "  ")
 	if err != nil {
-		return providers, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
+		return recs, uplinks, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
 	}
 	const filePerm = fs.FileMode(0o666)
 	err = r.writeFile(filePath, buffer.Bytes(), filePerm)
 	if err != nil {
-		return providers, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
+		return recs, uplinks, warnings, fmt.Errorf("%w: %w", errWriteConfigToFile, err)
 	}
 
-	return providers, warnings, nil
+	return recs, uplinks, warnings, nil
 }
 
 var (
@@ -111,9 +167,10 @@ var (
 )
 
 func extractAllSettings(jsonBytes []byte) (
-	allProviders []provider.Provider, warnings []string, err error,
+	allRecords []records.Record, uplinks []uplink.Uplink, warnings []string, err error,
 ) {
 	config := struct {
+		Uplinks        []uplink.Uplink  `json:"uplinks"`
 		CommonSettings []commonSettings `json:"settings"`
 	}{}
 	rawConfig := struct {
@@ -121,29 +178,68 @@ func extractAllSettings(jsonBytes []byte) (
 	}{}
 	err = json.Unmarshal(jsonBytes, &config)
 	if err != nil {
-		return nil, nil, fmt.Errorf("%w: %w", errUnmarshalCommon, err)
+		return nil, nil, nil, fmt.Errorf("%w: %w", errUnmarshalCommon, err)
 	}
 	err = json.Unmarshal(jsonBytes, &rawConfig)
 	if err != nil {
-		return nil, nil, fmt.Errorf("%w: %w", errUnmarshalRaw, err)
+		return nil, nil, nil, fmt.Errorf("%w: %w", errUnmarshalRaw, err)
 	}
 	// TODO(v3): remove retro compatibility with IPV6_PREFIX
 	retroIPv6Suffix, err := getRetroIPv6Suffix()
 	if err != nil {
-		return nil, nil, fmt.Errorf("getting retro-compatible global IPV6 suffix: %w", err)
+		return nil, nil, nil, fmt.Errorf("getting retro-compatible global IPV6 suffix: %w", err)
+	}
+
+	err = validateUplinks(config.Uplinks)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("validating uplinks: %w", err)
 	}
 
 	for i, common := range config.CommonSettings {
-		newProvider, newWarnings, err := makeSettingsFromObject(common, rawConfig.Settings[i],
+		newRecords, newWarnings, err := makeSettingsFromObject(common, rawConfig.Settings[i],
 			retroIPv6Suffix)
 		warnings = append(warnings, newWarnings...)
 		if err != nil {
-			return nil, warnings, err
+			return nil, nil, warnings, err
 		}
-		allProviders = append(allProviders, newProvider...)
+		allRecords = append(allRecords, newRecords...)
 	}
 
-	return allProviders, warnings, nil
+	err = checkRecordUplinks(allRecords, config.Uplinks)
+	if err != nil {
+		return nil, nil, warnings, err
+	}
+
+	err = checkRateLimits(allRecords)
+	if err != nil {
+		return nil, nil, warnings, err
+	}
+
+	err = checkFailoverBackupRecords(allRecords)
+	if err != nil {
+		return nil, nil, warnings, err
+	}
+
+	return allRecords, config.Uplinks, warnings, nil
+}
+
+// RecordsFromSettings returns the records defined by a single
+// settings JSON object, as found in the "settings" array of config.json.
+// The records returned have no history set.
+func RecordsFromSettings(rawSettings json.RawMessage) (
+	recs []records.Record, warnings []string, err error,
+) {
+	var common commonSettings
+	err = json.Unmarshal(rawSettings, &common)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", errUnmarshalCommon, err)
+	}
+	// TODO(v3): remove retro compatibility with IPV6_PREFIX
+	retroIPv6Suffix, err := getRetroIPv6Suffix()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting retro-compatible global IPV6 suffix: %w", err)
+	}
+	return makeSettingsFromObject(common, rawSettings, retroIPv6Suffix)
 }
 
 var (
@@ -153,7 +249,7 @@ var (
 
 func makeSettingsFromObject(common commonSettings, rawSettings json.RawMessage,
 	retroGlobalIPv6Suffix netip.Prefix) (
-	providers []provider.Provider, warnings []string, err error,
+	recs []records.Record, warnings []string, err error,
 ) {
 	if common.Provider == "google" {
 		return nil, nil, fmt.Errorf("%w: %s", ErrProviderNoLongerSupported, common.Provider)
@@ -190,6 +286,12 @@ func makeSettingsFromObject(common commonSettings, rawSettings json.RawMessage,
 	if common.IPVersion == "" {
 		common.IPVersion = ipversion.IP4or6.String()
 	}
+	// Dual-stack records use a provider with the IP version ipv4 or ipv6,
+	// which is then given the IPv4 and IPv6 addresses in turn.
+	dualStack := strings.EqualFold(common.IPVersion, records.DualStackIPVersion)
+	if dualStack {
+		common.IPVersion = ipversion.IP4or6.String()
+	}
 	ipVersion, err := ipversion.Parse(common.IPVersion)
 	if err != nil {
 		return nil, nil, err
@@ -219,16 +321,91 @@ func makeSettingsFromObject(common commonSettings, rawSettings json.RawMessage,
 			"You should use the hetznercloud with the new Hetzner Cloud console instead, "+
 				"given this legacy Hetzner API is going to be shutdown soon.")
 	}
-	providers = make([]provider.Provider, len(owners))
+	recordSettings, newWarnings, err := makeRecordSettings(common, ipVersion)
+	warnings = append(warnings, newWarnings...)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.DualStack = dualStack
+	recordSettings.Uplink = common.Uplink
+	recordSettings.AutoIP, err = parseIPSource(common.IPSource, recordSettings.StalePolicy)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.Failover, err = makeFailoverSettings(common.Failover, ipVersion)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.MultiIP, err = makeMultiIPSettings(common, recordSettings)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.IPExpressions, err = parseIPExpressions(common.IPExpressions,
+		ipVersion, common.IPv6Suffix, recordSettings)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.IPv6Prefix, err = parseIPv6Prefix(common.IPv6Prefix,
+		ipVersion, ipv6Suffix, recordSettings)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.Follow, err = parseFollow(common.Follow, recordSettings)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.RecordType, recordSettings.Content, err = makeContentSettings(
+		common, recordSettings)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.Schedule, recordSettings.Cooldown, err = parseSchedule(
+		common.Period, common.Cron, common.Cooldown)
+	if err != nil {
+		return nil, warnings, err
+	}
+	recordSettings.Critical = common.Critical
+	recordSettings.RateLimit, err = parseRateLimit(common.RateLimit)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	recs = make([]records.Record, len(owners))
 	for i, owner := range owners {
 		owner = strings.TrimSpace(owner)
-		providers[i], err = provider.New(providerName, rawSettings, domain,
-			owner, ipVersion, ipv6Suffix)
+		recordProvider, err := provider.New(providerName, rawSettings, domain,
+			owner, ipVersion, ipv6Suffix, common.CreateIfMissing, common.TTL)
+		if err != nil {
+			return nil, warnings, err
+		}
+		err = checkStalePolicySupport(recordProvider, recordSettings.StalePolicy)
+		if err != nil {
+			return nil, warnings, err
+		}
+		err = checkAutoIPSupport(recordProvider, recordSettings.AutoIP)
+		if err != nil {
+			return nil, warnings, err
+		}
+		err = checkMultiIPSupport(recordProvider, recordSettings.MultiIP)
+		if err != nil {
+			return nil, warnings, err
+		}
+		err = checkContentSupport(recordProvider, recordSettings)
+		if err != nil {
+			return nil, warnings, err
+		}
+		err = checkFollowSelf(recordProvider, recordSettings.Follow)
+		if err != nil {
+			return nil, warnings, err
+		}
+		recordSettings.Account, err = makeAccount(recordProvider, providerName,
+			domain, rawSettings)
 		if err != nil {
 			return nil, warnings, err
 		}
+		recs[i] = records.New(recordProvider, recordSettings, nil)
 	}
-	return providers, warnings, nil
+	return recs, warnings, nil
 }
 
 var ErrMultipleDomainsSpecified = errors.New("multiple domains specified")
@@ -0,0 +1,118 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+var (
+	ErrMultiIPUplinksNotSet       = errors.New("uplinks are not set for the uplinks IP source")
+	ErrMultiIPUplinkNameEmpty     = errors.New("uplink name is empty")
+	ErrMultiIPUplinkDuplicate     = errors.New("uplink is duplicated")
+	ErrMultiIPFieldUnexpected     = errors.New("field is not valid for the IP source")
+	ErrMultiIPReportExpiryInvalid = errors.New("report expiry is not valid")
+	ErrMultiIPReportTokenNotSet   = errors.New("report token is not set for the reports IP source")
+	ErrMultiIPSettingUnsupported  = errors.New("setting is not supported for multi-value records")
+	ErrMultiIPNotSupported        = errors.New("provider does not support multi-value records")
+)
+
+// makeMultiIPSettings returns the multi-value settings of the record
+// for the IP sources "uplinks" and "reports", and nil otherwise.
+func makeMultiIPSettings(common commonSettings, settings records.Settings) (
+	multiIP *records.MultiIP, err error,
+) {
+	switch {
+	case len(common.Uplinks) > 0 && common.IPSource != ipSourceUplinks:
+		return nil, fmt.Errorf("%w: uplinks for IP source %q",
+			ErrMultiIPFieldUnexpected, common.IPSource)
+	case common.ReportExpiry != "" && common.IPSource != ipSourceReports:
+		return nil, fmt.Errorf("%w: report_expiry for IP source %q",
+			ErrMultiIPFieldUnexpected, common.IPSource)
+	case common.ReportToken != "" && common.IPSource != ipSourceReports:
+		return nil, fmt.Errorf("%w: report_token for IP source %q",
+			ErrMultiIPFieldUnexpected, common.IPSource)
+	}
+
+	switch common.IPSource {
+	case ipSourceUplinks:
+		multiIP, err = makeUplinksMultiIP(common.Uplinks)
+	case ipSourceReports:
+		multiIP, err = makeReportsMultiIP(common.ReportExpiry, common.ReportToken)
+	default:
+		return nil, nil //nolint:nilnil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The record value is a set of IP addresses which
+	// cannot be combined with settings of a single IP address.
+	switch {
+	case settings.DualStack:
+		return nil, fmt.Errorf("%w: ip version %q", ErrMultiIPSettingUnsupported,
+			records.DualStackIPVersion)
+	case settings.Uplink != "":
+		return nil, fmt.Errorf("%w: uplink", ErrMultiIPSettingUnsupported)
+	case settings.StalePolicy != records.StalePolicyKeep:
+		return nil, fmt.Errorf("%w: stale policy %q", ErrMultiIPSettingUnsupported,
+			settings.StalePolicy)
+	case settings.Failover != nil:
+		return nil, fmt.Errorf("%w: failover", ErrMultiIPSettingUnsupported)
+	}
+	return multiIP, nil
+}
+
+func makeUplinksMultiIP(uplinks []string) (multiIP *records.MultiIP, err error) {
+	if len(uplinks) == 0 {
+		return nil, fmt.Errorf("%w", ErrMultiIPUplinksNotSet)
+	}
+	names := make(map[string]struct{}, len(uplinks))
+	for _, uplink := range uplinks {
+		if uplink == "" {
+			return nil, fmt.Errorf("%w", ErrMultiIPUplinkNameEmpty)
+		}
+		_, exists := names[uplink]
+		if exists {
+			return nil, fmt.Errorf("%w: %s", ErrMultiIPUplinkDuplicate, uplink)
+		}
+		names[uplink] = struct{}{}
+	}
+	return &records.MultiIP{Uplinks: uplinks}, nil
+}
+
+func makeReportsMultiIP(reportExpiry, reportToken string) (multiIP *records.MultiIP, err error) {
+	if reportToken == "" {
+		return nil, fmt.Errorf("%w", ErrMultiIPReportTokenNotSet)
+	}
+	const defaultReportExpiry = time.Hour
+	multiIP = &records.MultiIP{
+		Reported:     true,
+		ReportExpiry: defaultReportExpiry,
+		ReportToken:  reportToken,
+	}
+	if reportExpiry != "" {
+		multiIP.ReportExpiry, err = time.ParseDuration(reportExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMultiIPReportExpiryInvalid, err)
+		} else if multiIP.ReportExpiry <= 0 {
+			return nil, fmt.Errorf("%w: %s must be positive",
+				ErrMultiIPReportExpiryInvalid, multiIP.ReportExpiry)
+		}
+	}
+	return multiIP, nil
+}
+
+func checkMultiIPSupport(recordProvider provider.Provider, multiIP *records.MultiIP) (err error) {
+	if multiIP == nil {
+		return nil
+	}
+	_, ok := recordProvider.(provider.MultiUpdater)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMultiIPNotSupported, recordProvider.String())
+	}
+	return nil
+}
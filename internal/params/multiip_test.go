@@ -0,0 +1,128 @@
+package params
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeMultiIPSettings(t *testing.T) {
+	t.Parallel()
+
+	keepSettings := records.Settings{StalePolicy: records.StalePolicyKeep}
+
+	testCases := map[string]struct {
+		common     commonSettings
+		settings   records.Settings
+		multiIP    *records.MultiIP
+		errWrapped error
+		errMessage string
+	}{
+		"public": {
+			settings: keepSettings,
+		},
+		"uplinks": {
+			common:   commonSettings{IPSource: "uplinks", Uplinks: []string{"wan1", "wan2"}},
+			settings: keepSettings,
+			multiIP:  &records.MultiIP{Uplinks: []string{"wan1", "wan2"}},
+		},
+		"reports_default_expiry": {
+			common:   commonSettings{IPSource: "reports", ReportToken: "token"},
+			settings: keepSettings,
+			multiIP: &records.MultiIP{Reported: true, ReportExpiry: time.Hour,
+				ReportToken: "token"},
+		},
+		"reports_expiry": {
+			common: commonSettings{IPSource: "reports", ReportExpiry: "10m",
+				ReportToken: "token"},
+			settings: keepSettings,
+			multiIP: &records.MultiIP{Reported: true, ReportExpiry: 10 * time.Minute,
+				ReportToken: "token"},
+		},
+		"uplinks_for_public": {
+			common:     commonSettings{Uplinks: []string{"wan1"}},
+			settings:   keepSettings,
+			errWrapped: ErrMultiIPFieldUnexpected,
+			errMessage: `field is not valid for the IP source: uplinks for IP source ""`,
+		},
+		"report_expiry_for_uplinks": {
+			common: commonSettings{IPSource: "uplinks", Uplinks: []string{"wan1"},
+				ReportExpiry: "1h"},
+			settings:   keepSettings,
+			errWrapped: ErrMultiIPFieldUnexpected,
+			errMessage: `field is not valid for the IP source: report_expiry for IP source "uplinks"`,
+		},
+		"report_token_for_public": {
+			common:     commonSettings{ReportToken: "token"},
+			settings:   keepSettings,
+			errWrapped: ErrMultiIPFieldUnexpected,
+			errMessage: `field is not valid for the IP source: report_token for IP source ""`,
+		},
+		"report_token_not_set": {
+			common:     commonSettings{IPSource: "reports"},
+			settings:   keepSettings,
+			errWrapped: ErrMultiIPReportTokenNotSet,
+			errMessage: "report token is not set for the reports IP source",
+		},
+		"uplinks_not_set": {
+			common:     commonSettings{IPSource: "uplinks"},
+			settings:   keepSettings,
+			errWrapped: ErrMultiIPUplinksNotSet,
+			errMessage: "uplinks are not set for the uplinks IP source",
+		},
+		"uplink_duplicate": {
+			common:     commonSettings{IPSource: "uplinks", Uplinks: []string{"wan1", "wan1"}},
+			settings:   keepSettings,
+			errWrapped: ErrMultiIPUplinkDuplicate,
+			errMessage: "uplink is duplicated: wan1",
+		},
+		"report_expiry_not_positive": {
+			common: commonSettings{IPSource: "reports", ReportExpiry: "0s",
+				ReportToken: "token"},
+			settings:   keepSettings,
+			errWrapped: ErrMultiIPReportExpiryInvalid,
+			errMessage: "report expiry is not valid: 0s must be positive",
+		},
+		"dual_stack": {
+			common: commonSettings{IPSource: "reports", ReportToken: "token"},
+			settings: records.Settings{
+				StalePolicy: records.StalePolicyKeep,
+				DualStack:   true,
+			},
+			errWrapped: ErrMultiIPSettingUnsupported,
+			errMessage: `setting is not supported for multi-value records: ip version "ipv4 and ipv6"`,
+		},
+		"stale_policy": {
+			common:     commonSettings{IPSource: "reports", ReportToken: "token"},
+			settings:   records.Settings{StalePolicy: records.StalePolicyDelete},
+			errWrapped: ErrMultiIPSettingUnsupported,
+			errMessage: `setting is not supported for multi-value records: stale policy "delete"`,
+		},
+		"failover": {
+			common: commonSettings{IPSource: "uplinks", Uplinks: []string{"wan1"}},
+			settings: records.Settings{
+				StalePolicy: records.StalePolicyKeep,
+				Failover:    &failover.Settings{},
+			},
+			errWrapped: ErrMultiIPSettingUnsupported,
+			errMessage: "setting is not supported for multi-value records: failover",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			multiIP, err := makeMultiIPSettings(testCase.common, testCase.settings)
+
+			assert.Equal(t, testCase.multiIP, multiIP)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
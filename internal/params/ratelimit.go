@@ -0,0 +1,100 @@
+package params
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+var ErrRateLimitConflict = errors.New("records of the same provider account have different rate limits")
+
+func parseRateLimit(s string) (limit *ratelimit.Limit, err error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil
+	}
+	parsed, err := ratelimit.ParseLimit(s)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// makeAccount returns an identifier of the provider account of a record,
+// made of the provider name and of a fingerprint of the account identity
+// of the provider if it implements it, or of the credentials found in the
+// raw provider settings given otherwise. The credentials cannot be
+// recovered from the fingerprint.
+func makeAccount(recordProvider provider.Provider, providerName models.Provider,
+	domain string, rawSettings json.RawMessage,
+) (account string, err error) {
+	const fingerprintLength = 8
+	if identifier, ok := recordProvider.(provider.AccountIdentifier); ok {
+		hash := sha256.Sum256([]byte(identifier.AccountIdentity()))
+		return string(providerName) + ":" + hex.EncodeToString(hash[:fingerprintLength]), nil
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(rawSettings, &fields)
+	if err != nil {
+		return "", fmt.Errorf("decoding provider settings: %w", err)
+	}
+
+	credentialKeyParts := []string{
+		"token", "key", "secret", "password", "user",
+		"email", "login", "client", "account", "credentials",
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		lowerKey := strings.ToLower(key)
+		isCredential := slices.ContainsFunc(credentialKeyParts, func(part string) bool {
+			return strings.Contains(lowerKey, part)
+		})
+		if isCredential {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	hash := sha256.New()
+	if len(keys) == 0 {
+		// no credentials found, so consider each domain
+		// to be its own account.
+		_, _ = hash.Write([]byte(domain))
+	}
+	for _, key := range keys {
+		_, _ = hash.Write([]byte(key + "=" + string(fields[key]) + "\n"))
+	}
+	fingerprint := hex.EncodeToString(hash.Sum(nil)[:fingerprintLength])
+	return string(providerName) + ":" + fingerprint, nil
+}
+
+// checkRateLimits checks records of the same provider account do not
+// have different rate limits set, since they share the same rate limit.
+func checkRateLimits(recs []records.Record) (err error) {
+	accountRecords := make(map[string]records.Record, len(recs))
+	for _, record := range recs {
+		if record.Settings.RateLimit == nil {
+			continue
+		}
+		other, ok := accountRecords[record.Settings.Account]
+		if !ok {
+			accountRecords[record.Settings.Account] = record
+			continue
+		}
+		if *other.Settings.RateLimit != *record.Settings.RateLimit {
+			return fmt.Errorf("%w: %s for record %s and %s for record %s",
+				ErrRateLimitConflict, other.Settings.RateLimit, other.Provider,
+				record.Settings.RateLimit, record.Provider)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package params
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAccountIdentifier struct {
+	provider.Provider
+	identity string
+}
+
+func (p fakeAccountIdentifier) AccountIdentity() string {
+	return p.identity
+}
+
+func Test_makeAccount(t *testing.T) {
+	t.Parallel()
+
+	makeAccountOf := func(domain, rawSettings string) string {
+		account, err := makeAccount(nil, constants.Cloudflare, domain, json.RawMessage(rawSettings))
+		require.NoError(t, err)
+		return account
+	}
+
+	sameToken := makeAccountOf("a.com", `{"token":"abc","zone_identifier":"1","proxied":true}`)
+	assert.Regexp(t, `^cloudflare:[0-9a-f]{16}$`, sameToken)
+	assert.NotContains(t, sameToken, "abc")
+	assert.Equal(t, sameToken,
+		makeAccountOf("b.com", `{"zone_identifier":"2","token":"abc"}`))
+	assert.NotEqual(t, sameToken,
+		makeAccountOf("a.com", `{"token":"def","zone_identifier":"1"}`))
+
+	// without credentials, each domain is its own account
+	assert.NotEqual(t, makeAccountOf("a.com", `{}`), makeAccountOf("b.com", `{}`))
+
+	assert.Equal(t,
+		makeAccountOf("a.com", `{"zone":"a","credentials":{"type":"service_account"}}`),
+		makeAccountOf("b.com", `{"zone":"b","credentials":{"type":"service_account"}}`))
+
+	identifier := fakeAccountIdentifier{identity: "identity"}
+	fromIdentity, err := makeAccount(identifier, constants.GCP, "a.com",
+		json.RawMessage(`{"zone":"a"}`))
+	require.NoError(t, err)
+	assert.Regexp(t, `^gcp:[0-9a-f]{16}$`, fromIdentity)
+	otherDomain, err := makeAccount(identifier, constants.GCP, "b.com",
+		json.RawMessage(`{"zone":"b"}`))
+	require.NoError(t, err)
+	assert.Equal(t, fromIdentity, otherDomain)
+
+	_, err = makeAccount(nil, constants.Cloudflare, "a.com", json.RawMessage(`[]`))
+	assert.ErrorContains(t, err, "decoding provider settings: ")
+}
@@ -0,0 +1,54 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/schedule"
+)
+
+var (
+	ErrPeriodAndCronSet = errors.New("period and cron cannot be both set")
+	ErrPeriodNotValid   = errors.New("period is not valid")
+	ErrCooldownNotValid = errors.New("cooldown is not valid")
+	ErrCooldownNegative = errors.New("cooldown cannot be negative")
+)
+
+// parseSchedule returns the record update schedule and cooldown period,
+// which are both nil if unset, in which case the global update period
+// and cooldown period are used.
+func parseSchedule(period, cron, cooldown string) (
+	recordSchedule *schedule.Schedule, recordCooldown *time.Duration, err error,
+) {
+	switch {
+	case period != "" && cron != "":
+		return nil, nil, fmt.Errorf("%w: period %q and cron %q",
+			ErrPeriodAndCronSet, period, cron)
+	case period != "":
+		duration, err := time.ParseDuration(period)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrPeriodNotValid, err)
+		}
+		recordSchedule, err = schedule.NewPeriod(duration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrPeriodNotValid, err)
+		}
+	case cron != "":
+		recordSchedule, err = schedule.NewCron(cron)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if cooldown != "" {
+		duration, err := time.ParseDuration(cooldown)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrCooldownNotValid, err)
+		} else if duration < 0 {
+			return nil, nil, fmt.Errorf("%w: %s", ErrCooldownNegative, duration)
+		}
+		recordCooldown = &duration
+	}
+	return recordSchedule, recordCooldown, nil
+}
@@ -0,0 +1,83 @@
+package params
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/schedule"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseSchedule(t *testing.T) {
+	t.Parallel()
+
+	thirtySeconds := 30 * time.Second
+
+	testCases := map[string]struct {
+		period         string
+		cron           string
+		cooldown       string
+		scheduleString string
+		cooldownValue  *time.Duration
+		errWrapped     error
+		errMessage     string
+	}{
+		"unset": {},
+		"period": {
+			period:         "30s",
+			scheduleString: "every 30s",
+		},
+		"cron_and_cooldown": {
+			cron:           "*/10 * * * *",
+			cooldown:       "30s",
+			scheduleString: "cron */10 * * * *",
+			cooldownValue:  &thirtySeconds,
+		},
+		"period_and_cron": {
+			period:     "1m",
+			cron:       "* * * * *",
+			errWrapped: ErrPeriodAndCronSet,
+			errMessage: `period and cron cannot be both set: period "1m" and cron "* * * * *"`,
+		},
+		"invalid_period": {
+			period:     "1x",
+			errWrapped: ErrPeriodNotValid,
+			errMessage: `period is not valid: time: unknown unit "x" in duration "1x"`,
+		},
+		"zero_period": {
+			period:     "0s",
+			errWrapped: schedule.ErrPeriodNotPositive,
+			errMessage: "period is not valid: period is not positive: 0s",
+		},
+		"invalid_cron": {
+			cron:       "* * *",
+			errWrapped: schedule.ErrCronFieldsCount,
+			errMessage: `parsing cron expression: cron expression must have 5 fields: "* * *" has 3 fields`,
+		},
+		"negative_cooldown": {
+			cooldown:   "-1s",
+			errWrapped: ErrCooldownNegative,
+			errMessage: "cooldown cannot be negative: -1s",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recordSchedule, cooldown, err := parseSchedule(testCase.period,
+				testCase.cron, testCase.cooldown)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			if testCase.scheduleString == "" {
+				assert.Nil(t, recordSchedule)
+			} else {
+				assert.Equal(t, testCase.scheduleString, recordSchedule.String())
+			}
+			assert.Equal(t, testCase.cooldownValue, cooldown)
+		})
+	}
+}
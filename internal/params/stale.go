@@ -0,0 +1,81 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+var (
+	ErrStalePolicyNotValid       = errors.New("stale policy is not valid")
+	ErrFallbackIPNotSet          = errors.New("fallback IP address is not set")
+	ErrFallbackIPVersionMismatch = errors.New("fallback IP address has the wrong IP version")
+)
+
+func makeRecordSettings(common commonSettings, ipVersion ipversion.IPVersion) (
+	settings records.Settings, warnings []string, err error,
+) {
+	settings.StalePolicy = records.StalePolicy(common.StalePolicy)
+	if settings.StalePolicy == "" {
+		settings.StalePolicy = records.StalePolicyKeep
+	}
+
+	switch settings.StalePolicy {
+	case records.StalePolicyKeep, records.StalePolicyDelete:
+		if common.FallbackIPv4.IsValid() || common.FallbackIPv6.IsValid() {
+			warnings = append(warnings, fmt.Sprintf(
+				"fallback IP addresses are ignored for stale policy %q", settings.StalePolicy))
+		}
+		return settings, warnings, nil
+	case records.StalePolicyFallback:
+	default:
+		return settings, nil, fmt.Errorf("%w: %q must be one of %q, %q or %q",
+			ErrStalePolicyNotValid, settings.StalePolicy, records.StalePolicyKeep,
+			records.StalePolicyDelete, records.StalePolicyFallback)
+	}
+
+	if ipVersion != ipversion.IP6 {
+		settings.FallbackIPv4, err = checkFallbackIP(common.FallbackIPv4, "fallback_ipv4", false)
+		if err != nil {
+			return settings, nil, err
+		}
+	}
+	if ipVersion != ipversion.IP4 {
+		settings.FallbackIPv6, err = checkFallbackIP(common.FallbackIPv6, "fallback_ipv6", true)
+		if err != nil {
+			return settings, nil, err
+		}
+	}
+	return settings, nil, nil
+}
+
+func checkFallbackIP(ip netip.Addr, fieldName string, ipv6 bool) (
+	checkedIP netip.Addr, err error,
+) {
+	switch {
+	case !ip.IsValid():
+		return netip.Addr{}, fmt.Errorf("%w: %s", ErrFallbackIPNotSet, fieldName)
+	case ip.Is6() != ipv6:
+		return netip.Addr{}, fmt.Errorf("%w: %s is %s",
+			ErrFallbackIPVersionMismatch, fieldName, ip)
+	}
+	return ip, nil
+}
+
+func checkStalePolicySupport(recordProvider provider.Provider,
+	stalePolicy records.StalePolicy,
+) (err error) {
+	if stalePolicy != records.StalePolicyDelete {
+		return nil
+	}
+	_, ok := recordProvider.(provider.RecordDeleter)
+	if !ok {
+		return fmt.Errorf("%w: %s for stale policy %q",
+			provider.ErrRecordDeletionUnsupported, recordProvider.String(), stalePolicy)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package params
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeRecordSettings(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		common     commonSettings
+		ipVersion  ipversion.IPVersion
+		settings   records.Settings
+		warnings   []string
+		errWrapped error
+		errMessage string
+	}{
+		"default": {
+			ipVersion: ipversion.IP4or6,
+			settings:  records.Settings{StalePolicy: records.StalePolicyKeep},
+		},
+		"invalid_policy": {
+			common:     commonSettings{StalePolicy: "remove"},
+			ipVersion:  ipversion.IP4or6,
+			settings:   records.Settings{StalePolicy: "remove"},
+			errWrapped: ErrStalePolicyNotValid,
+			errMessage: `stale policy is not valid: "remove" must be one of "keep", "delete" or "fallback"`,
+		},
+		"delete_with_fallback": {
+			common: commonSettings{
+				StalePolicy:  "delete",
+				FallbackIPv4: netip.MustParseAddr("1.2.3.4"),
+			},
+			ipVersion: ipversion.IP4,
+			settings:  records.Settings{StalePolicy: records.StalePolicyDelete},
+			warnings:  []string{`fallback IP addresses are ignored for stale policy "delete"`},
+		},
+		"fallback_ipv6_missing": {
+			common: commonSettings{
+				StalePolicy:  "fallback",
+				FallbackIPv4: netip.MustParseAddr("1.2.3.4"),
+			},
+			ipVersion: ipversion.IP4or6,
+			settings: records.Settings{
+				StalePolicy:  records.StalePolicyFallback,
+				FallbackIPv4: netip.MustParseAddr("1.2.3.4"),
+			},
+			errWrapped: ErrFallbackIPNotSet,
+			errMessage: "fallback IP address is not set: fallback_ipv6",
+		},
+		"fallback_ipv4_version_mismatch": {
+			common: commonSettings{
+				StalePolicy:  "fallback",
+				FallbackIPv4: netip.MustParseAddr("::1"),
+			},
+			ipVersion:  ipversion.IP4,
+			settings:   records.Settings{StalePolicy: records.StalePolicyFallback},
+			errWrapped: ErrFallbackIPVersionMismatch,
+			errMessage: "fallback IP address has the wrong IP version: fallback_ipv4 is ::1",
+		},
+		"fallback_ipv6_only": {
+			common: commonSettings{
+				StalePolicy:  "fallback",
+				FallbackIPv6: netip.MustParseAddr("::1"),
+			},
+			ipVersion: ipversion.IP6,
+			settings: records.Settings{
+				StalePolicy:  records.StalePolicyFallback,
+				FallbackIPv6: netip.MustParseAddr("::1"),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings, warnings, err := makeRecordSettings(testCase.common, testCase.ipVersion)
+
+			assert.Equal(t, testCase.settings, settings)
+			assert.Equal(t, testCase.warnings, warnings)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
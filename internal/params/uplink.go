@@ -0,0 +1,54 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/uplink"
+)
+
+var (
+	ErrUplinkNameDuplicate = errors.New("uplink name is duplicated")
+	ErrUplinkNotDefined    = errors.New("uplink is not defined")
+)
+
+func validateUplinks(uplinks []uplink.Uplink) (err error) {
+	names := make(map[string]struct{}, len(uplinks))
+	for _, uplink := range uplinks {
+		err = uplink.Validate()
+		if err != nil {
+			return err
+		}
+		_, exists := names[uplink.Name]
+		if exists {
+			return fmt.Errorf("%w: %s", ErrUplinkNameDuplicate, uplink.Name)
+		}
+		names[uplink.Name] = struct{}{}
+	}
+	return nil
+}
+
+func checkRecordUplinks(recs []records.Record, uplinks []uplink.Uplink) (err error) {
+	names := make(map[string]struct{}, len(uplinks))
+	for _, uplink := range uplinks {
+		names[uplink.Name] = struct{}{}
+	}
+	for _, record := range recs {
+		recordUplinks := []string{record.Settings.Uplink}
+		if record.Settings.MultiIP != nil {
+			recordUplinks = record.Settings.MultiIP.Uplinks
+		}
+		for _, recordUplink := range recordUplinks {
+			if recordUplink == "" {
+				continue
+			}
+			_, exists := names[recordUplink]
+			if !exists {
+				return fmt.Errorf("%w: %s for record %s",
+					ErrUplinkNotDefined, recordUplink, record.Provider)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package params
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/uplink"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateUplinks(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		uplinks    []uplink.Uplink
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {},
+		"valid": {
+			uplinks: []uplink.Uplink{
+				{Name: "wan1", Address: netip.MustParseAddr("192.168.1.2")},
+				{Name: "wan2", Address: netip.MustParseAddr("192.168.2.2")},
+			},
+		},
+		"name_not_set": {
+			uplinks: []uplink.Uplink{
+				{Address: netip.MustParseAddr("192.168.1.2")},
+			},
+			errWrapped: uplink.ErrNameNotSet,
+			errMessage: "name is not set",
+		},
+		"interface_or_address_not_set": {
+			uplinks:    []uplink.Uplink{{Name: "wan1"}},
+			errWrapped: uplink.ErrInterfaceOrAddressNotSet,
+			errMessage: "interface or address must be set: for uplink wan1",
+		},
+		"duplicate_name": {
+			uplinks: []uplink.Uplink{
+				{Name: "wan1", Address: netip.MustParseAddr("192.168.1.2")},
+				{Name: "wan1", Address: netip.MustParseAddr("192.168.2.2")},
+			},
+			errWrapped: ErrUplinkNameDuplicate,
+			errMessage: "uplink name is duplicated: wan1",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateUplinks(testCase.uplinks)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
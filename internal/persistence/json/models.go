@@ -16,6 +16,21 @@ type record struct {
 	Host   string                `json:"host,omitempty"`
 	Owner  string                `json:"owner"`
 	Events []models.HistoryEvent `json:"ips"`
+	// Pins are the pins of the records with this domain and owner,
+	// with at most one pin per record.
+	Pins []pin `json:"pins,omitempty"`
+	// FailedOver are the keys of the records with this domain and owner
+	// pointing to their failover backup IP addresses.
+	FailedOver []string `json:"failed_over,omitempty"`
+}
+
+type pin struct {
+	models.Pin
+	// Record is the key identifying the record of the pin amongst
+	// the records of the same domain and owner, such as records of
+	// different providers or IP versions. It is empty for pins stored
+	// by older versions, which are ignored.
+	Record string `json:"record,omitempty"`
 }
 
 func (r record) String() string {
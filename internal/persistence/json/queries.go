@@ -3,14 +3,19 @@ package json
 import (
 	"fmt"
 	"net/netip"
+	"slices"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
-// StoreNewIP stores a new IP address for a certain domain and owner.
-func (db *Database) StoreNewIP(domain, owner string, ip netip.Addr, t time.Time) (err error) {
+// StoreNewIP stores a new IP address for a certain domain and owner,
+// where ips are the IP addresses of a multi-value record and are
+// empty for other records.
+func (db *Database) StoreNewIP(domain, owner string, ip netip.Addr, ips []netip.Addr,
+	t time.Time,
+) (err error) {
 	db.mutex.Lock()
 	defer db.mutex.Unlock()
 
@@ -33,6 +38,7 @@ func (db *Database) StoreNewIP(domain, owner string, ip netip.Addr, t time.Time)
 
 	event := models.HistoryEvent{
 		IP:   ip,
+		IPs:  ips,
 		Time: t,
 	}
 	db.data.Records[targetIndex].Events = append(db.data.Records[targetIndex].Events, event)
@@ -54,6 +60,121 @@ func (db *Database) GetEvents(domain, owner string,
 	return nil, nil
 }
 
+// SetPin sets the pin of the record with the given domain, owner and
+// record key, replacing any existing pin for this record.
+// The pin is removed if pin is nil.
+func (db *Database) SetPin(domain, owner, recordKey string,
+	recordPin *models.Pin,
+) (err error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	targetIndex := -1
+	for i, record := range db.data.Records {
+		if record.Domain == domain && record.Owner == owner {
+			targetIndex = i
+			break
+		}
+	}
+
+	if targetIndex == -1 {
+		if recordPin == nil {
+			return nil
+		}
+		db.data.Records = append(db.data.Records, record{
+			Domain: domain,
+			Owner:  owner,
+		})
+		targetIndex = len(db.data.Records) - 1
+	}
+
+	pins := db.data.Records[targetIndex].Pins
+	pins = slices.DeleteFunc(pins, func(pin pin) bool {
+		// Pins without record key cannot be matched to
+		// a record, so they are removed as well.
+		return pin.Record == recordKey || pin.Record == ""
+	})
+	if recordPin != nil {
+		pins = append(pins, pin{Pin: *recordPin, Record: recordKey})
+	}
+	db.data.Records[targetIndex].Pins = pins
+	return db.write()
+}
+
+// GetPin returns the pin of the record with the given domain, owner
+// and record key, and nil if the record is not pinned.
+func (db *Database) GetPin(domain, owner, recordKey string) (
+	recordPin *models.Pin, err error,
+) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	for _, record := range db.data.Records {
+		if record.Domain != domain || record.Owner != owner {
+			continue
+		}
+		for _, pin := range record.Pins {
+			if pin.Record == recordKey {
+				return &pin.Pin, nil
+			}
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+// SetFailedOver sets whether the record with the given domain, owner
+// and record key points to its failover backup IP addresses.
+func (db *Database) SetFailedOver(domain, owner, recordKey string,
+	failedOver bool,
+) (err error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	targetIndex := -1
+	for i, record := range db.data.Records {
+		if record.Domain == domain && record.Owner == owner {
+			targetIndex = i
+			break
+		}
+	}
+
+	if targetIndex == -1 {
+		if !failedOver {
+			return nil
+		}
+		db.data.Records = append(db.data.Records, record{
+			Domain: domain,
+			Owner:  owner,
+		})
+		targetIndex = len(db.data.Records) - 1
+	}
+
+	keys := db.data.Records[targetIndex].FailedOver
+	keys = slices.DeleteFunc(keys, func(key string) bool {
+		return key == recordKey
+	})
+	if failedOver {
+		keys = append(keys, recordKey)
+	}
+	db.data.Records[targetIndex].FailedOver = keys
+	return db.write()
+}
+
+// GetFailedOver returns true if the record with the given domain, owner
+// and record key points to its failover backup IP addresses.
+func (db *Database) GetFailedOver(domain, owner, recordKey string) (
+	failedOver bool, err error,
+) {
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	for _, record := range db.data.Records {
+		if record.Domain == domain && record.Owner == owner &&
+			slices.Contains(record.FailedOver, recordKey) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func filterEvents(events []models.HistoryEvent, ipVersion ipversion.IPVersion) (filteredEvents []models.HistoryEvent) {
 	filteredEvents = make([]models.HistoryEvent, 0, len(events))
 	for _, event := range events {
@@ -0,0 +1,93 @@
+package json
+
+import (
+	"net/netip"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Database_pins(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabase(t.TempDir())
+	require.NoError(t, err)
+
+	const (
+		cloudflareKey = "[domain: example.com | owner: @ | provider: cloudflare | ip: ipv4 or ipv6]"
+		noIPKey       = "[domain: example.com | owner: @ | provider: noip | ip: ipv4]"
+	)
+	cloudflarePin := &models.Pin{
+		IP:    netip.MustParseAddr("1.2.3.4"),
+		Until: time.Unix(1000, 0).UTC(),
+	}
+
+	err = db.SetPin("example.com", "@", cloudflareKey, cloudflarePin)
+	require.NoError(t, err)
+
+	pin, err := db.GetPin("example.com", "@", cloudflareKey)
+	require.NoError(t, err)
+	assert.Equal(t, cloudflarePin, pin)
+
+	pin, err = db.GetPin("example.com", "@", noIPKey)
+	require.NoError(t, err)
+	assert.Nil(t, pin)
+
+	noIPPin := &models.Pin{
+		IP:    netip.MustParseAddr("5.6.7.8"),
+		Until: time.Unix(2000, 0).UTC(),
+	}
+	err = db.SetPin("example.com", "@", noIPKey, noIPPin)
+	require.NoError(t, err)
+
+	err = db.SetPin("example.com", "@", cloudflareKey, nil)
+	require.NoError(t, err)
+
+	pin, err = db.GetPin("example.com", "@", cloudflareKey)
+	require.NoError(t, err)
+	assert.Nil(t, pin)
+
+	pin, err = db.GetPin("example.com", "@", noIPKey)
+	require.NoError(t, err)
+	assert.Equal(t, noIPPin, pin)
+}
+
+func Test_Database_failedOver(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewDatabase(t.TempDir())
+	require.NoError(t, err)
+
+	const (
+		ipv4Key = "[domain: example.com | owner: @ | provider: cloudflare | ip: ipv4]"
+		ipv6Key = "[domain: example.com | owner: @ | provider: cloudflare | ip: ipv6]"
+	)
+
+	err = db.SetFailedOver("example.com", "@", ipv4Key, true)
+	require.NoError(t, err)
+
+	failedOver, err := db.GetFailedOver("example.com", "@", ipv4Key)
+	require.NoError(t, err)
+	assert.True(t, failedOver)
+
+	failedOver, err = db.GetFailedOver("example.com", "@", ipv6Key)
+	require.NoError(t, err)
+	assert.False(t, failedOver)
+
+	reopened, err := NewDatabase(filepath.Dir(db.filepath))
+	require.NoError(t, err)
+	failedOver, err = reopened.GetFailedOver("example.com", "@", ipv4Key)
+	require.NoError(t, err)
+	assert.True(t, failedOver)
+
+	err = db.SetFailedOver("example.com", "@", ipv4Key, false)
+	require.NoError(t, err)
+
+	failedOver, err = db.GetFailedOver("example.com", "@", ipv4Key)
+	require.NoError(t, err)
+	assert.False(t, failedOver)
+}
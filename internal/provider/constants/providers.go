@@ -25,6 +25,7 @@ const (
 	DynV6        models.Provider = "dynv6"
 	EasyDNS      models.Provider = "easydns"
 	Example      models.Provider = "example"
+	Exec         models.Provider = "exec"
 	FreeDNS      models.Provider = "freedns"
 	Gandi        models.Provider = "gandi"
 	GCP          models.Provider = "gcp"
@@ -63,6 +64,7 @@ const (
 	Variomedia   models.Provider = "variomedia"
 	Vercel       models.Provider = "vercel"
 	Vultr        models.Provider = "vultr"
+	Webhook      models.Provider = "webhook"
 	Zoneedit     models.Provider = "zoneedit"
 )
 
@@ -88,6 +90,7 @@ func ProviderChoices() []models.Provider {
 		DynV6,
 		EasyDNS,
 		Example,
+		Exec,
 		FreeDNS,
 		Gandi,
 		GCP,
@@ -125,6 +128,7 @@ func ProviderChoices() []models.Provider {
 		Variomedia,
 		Vercel,
 		Vultr,
+		Webhook,
 		Zoneedit,
 	}
 }
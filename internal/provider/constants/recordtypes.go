@@ -5,4 +5,8 @@ const (
 	AAAA  = "AAAA"
 	CNAME = "CNAME"
 	ALIAS = "ALIAS"
+	TXT   = "TXT"
+	SPF   = "SPF"
+	SVCB  = "SVCB"
+	HTTPS = "HTTPS"
 )
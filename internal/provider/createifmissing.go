@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+)
+
+// recordCreators maps providers able to optionally create a missing record
+// to their default create-if-missing behavior, used if the setting is unset.
+// The default is true for providers which were always creating missing records.
+var recordCreators = map[models.Provider]bool{ //nolint:gochecknoglobals
+	constants.Aliyun:       true,
+	constants.Bunny:        true,
+	constants.Cloudflare:   true,
+	constants.DeSEC:        false,
+	constants.DigitalOcean: false,
+	constants.DNSPod:       false,
+	constants.GCP:          true,
+	constants.Hetzner:      true,
+	constants.HetznerCloud: true,
+	constants.Ionos:        true,
+	constants.Linode:       true,
+	constants.LuaDNS:       false,
+	constants.NameCom:      true,
+	constants.NameSilo:     true,
+	constants.Netcup:       true,
+	constants.OVH:          true,
+	constants.Porkbun:      true,
+	constants.Spaceship:    true,
+	constants.Vercel:       true,
+	constants.Vultr:        true,
+}
+
+// recordUpserters are providers whose API always creates the record
+// if it does not exist, which cannot be disabled.
+var recordUpserters = map[models.Provider]struct{}{ //nolint:gochecknoglobals
+	constants.Domeneshop: {},
+	constants.Dreamhost:  {},
+	constants.Gandi:      {},
+	constants.GoDaddy:    {},
+	constants.Hostinger:  {},
+	constants.Route53:    {},
+	constants.Scaleway:   {},
+	constants.Servercow:  {},
+}
+
+var ErrCreateIfMissingNotSupported = errors.New("create_if_missing is not supported")
+
+// resolveCreateIfMissing returns whether the provider should create its record
+// if it is missing, given the optional createIfMissing setting. It returns an
+// error if the setting value cannot be honored by the provider.
+func resolveCreateIfMissing(providerName models.Provider,
+	createIfMissing *bool,
+) (resolved bool, err error) {
+	if defaultValue, ok := recordCreators[providerName]; ok {
+		if createIfMissing == nil {
+			return defaultValue, nil
+		}
+		return *createIfMissing, nil
+	}
+
+	if _, ok := recordUpserters[providerName]; ok {
+		if createIfMissing != nil && !*createIfMissing {
+			return false, fmt.Errorf("%w: provider %s always creates missing records",
+				ErrCreateIfMissingNotSupported, providerName)
+		}
+		return true, nil
+	}
+
+	if createIfMissing != nil && *createIfMissing {
+		return false, fmt.Errorf("%w: provider %s cannot create records",
+			ErrCreateIfMissingNotSupported, providerName)
+	}
+	return false, nil
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptrTo[T any](value T) *T { return &value }
+
+func Test_resolveCreateIfMissing(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		providerName    models.Provider
+		createIfMissing *bool
+		resolved        bool
+		errWrapped      error
+		errMessage      string
+	}{
+		"creator_default_true": {
+			providerName: constants.Cloudflare,
+			resolved:     true,
+		},
+		"creator_default_false": {
+			providerName: constants.DigitalOcean,
+		},
+		"creator_disabled": {
+			providerName:    constants.Cloudflare,
+			createIfMissing: ptrTo(false),
+		},
+		"creator_enabled": {
+			providerName:    constants.DigitalOcean,
+			createIfMissing: ptrTo(true),
+			resolved:        true,
+		},
+		"upserter_default": {
+			providerName: constants.Route53,
+			resolved:     true,
+		},
+		"upserter_disabled": {
+			providerName:    constants.Route53,
+			createIfMissing: ptrTo(false),
+			errWrapped:      ErrCreateIfMissingNotSupported,
+			errMessage: "create_if_missing is not supported: " +
+				"provider route53 always creates missing records",
+		},
+		"upserter_enabled": {
+			providerName:    constants.Scaleway,
+			createIfMissing: ptrTo(true),
+			resolved:        true,
+		},
+		"unsupported_default": {
+			providerName: constants.DuckDNS,
+		},
+		"unsupported_disabled": {
+			providerName:    constants.DuckDNS,
+			createIfMissing: ptrTo(false),
+		},
+		"unsupported_enabled": {
+			providerName:    constants.DuckDNS,
+			createIfMissing: ptrTo(true),
+			errWrapped:      ErrCreateIfMissingNotSupported,
+			errMessage: "create_if_missing is not supported: " +
+				"provider duckdns cannot create records",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resolved, err := resolveCreateIfMissing(testCase.providerName, testCase.createIfMissing)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.resolved, resolved)
+		})
+	}
+}
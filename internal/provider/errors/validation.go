@@ -9,6 +9,7 @@ var (
 	ErrAPIKeyNotSet           = errors.New("API key is not set")
 	ErrAPISecretNotSet        = errors.New("API secret is not set")
 	ErrAppKeyNotSet           = errors.New("app key is not set")
+	ErrCommandNotSet          = errors.New("command is not set")
 	ErrConsumerKeyNotSet      = errors.New("consumer key is not set")
 	ErrCredentialsNotSet      = errors.New("credentials are not set")
 	ErrCredentialsNotValid    = errors.New("credentials are not valid")
@@ -20,15 +21,18 @@ var (
 	ErrDomainNotValid         = errors.New("domain is not valid")
 	ErrOwnerWildcard          = errors.New(`owner cannot be "*"`)
 	ErrKeyNotSet              = errors.New("key is not set")
+	ErrMethodNotValid         = errors.New("HTTP method is not valid")
 	ErrKeyNotValid            = errors.New("key is not valid")
 	ErrPasswordNotSet         = errors.New("password is not set")
 	ErrPasswordNotValid       = errors.New("password is not valid")
 	ErrSecretKeyNotSet        = errors.New("secret key is not set")
 	ErrSecretNotSet           = errors.New("secret is not set")
 	ErrSuccessRegexNotSet     = errors.New("success regex is not set")
+	ErrTimeoutNotValid        = errors.New("timeout is not valid")
 	ErrTokenNotSet            = errors.New("token is not set")
 	ErrTokenNotValid          = errors.New("token is not valid")
 	ErrTTLNotSet              = errors.New("TTL is not set")
+	ErrTTLNotSupported        = errors.New("TTL is not supported")
 	ErrTTLTooLow              = errors.New("TTL is too low")
 	ErrTTLTooHigh             = errors.New("TTL is too high")
 	ErrURLNotHTTPS            = errors.New("url is not https")
@@ -31,6 +31,7 @@ import (
 	"github.com/qdm12/ddns-updater/internal/provider/providers/dynv6"
 	"github.com/qdm12/ddns-updater/internal/provider/providers/easydns"
 	"github.com/qdm12/ddns-updater/internal/provider/providers/example"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/exec"
 	"github.com/qdm12/ddns-updater/internal/provider/providers/freedns"
 	"github.com/qdm12/ddns-updater/internal/provider/providers/gandi"
 	"github.com/qdm12/ddns-updater/internal/provider/providers/gcp"
@@ -69,6 +70,7 @@ import (
 	"github.com/qdm12/ddns-updater/internal/provider/providers/variomedia"
 	"github.com/qdm12/ddns-updater/internal/provider/providers/vercel"
 	"github.com/qdm12/ddns-updater/internal/provider/providers/vultr"
+	"github.com/qdm12/ddns-updater/internal/provider/providers/webhook"
 	"github.com/qdm12/ddns-updater/internal/provider/providers/zoneedit"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
@@ -85,23 +87,107 @@ type Provider interface {
 	Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error)
 }
 
-var ErrProviderUnknown = errors.New("unknown provider")
+// RecordReader is optionally implemented by providers able to read
+// the IP addresses currently set for their record through their API.
+// An empty slice of IP addresses is returned if the record does not exist.
+type RecordReader interface {
+	GetRecord(ctx context.Context, client *http.Client) (ips []netip.Addr, err error)
+}
+
+// RecordDeleter is optionally implemented by providers able to delete
+// their record of the given type, which is A or AAAA, through their API.
+// No error is returned if the record does not exist.
+type RecordDeleter interface {
+	DeleteRecord(ctx context.Context, client *http.Client, recordType string) (err error)
+}
+
+// AutoUpdater is optionally implemented by providers able to detect the
+// IP address to set from the source address of the update request.
+// The IP address detected is read back from the provider response, and
+// is the invalid zero address if the provider does not report it.
+type AutoUpdater interface {
+	UpdateAuto(ctx context.Context, client *http.Client) (newIP netip.Addr, err error)
+}
+
+// DualStackUpdater is optionally implemented by providers able to update
+// both the A and AAAA records of their record in a single API call.
+type DualStackUpdater interface {
+	UpdateDualStack(ctx context.Context, client *http.Client, ipv4, ipv6 netip.Addr) (
+		newIPv4, newIPv6 netip.Addr, err error)
+}
+
+// MultiUpdater is optionally implemented by providers able to set their
+// record to several IP addresses, as multi-value A and AAAA record sets.
+// The record set of each IP family of the record IP version is replaced
+// by the IP addresses of that family given, and is deleted if none is given.
+type MultiUpdater interface {
+	UpdateMulti(ctx context.Context, client *http.Client, ips []netip.Addr) (
+		newIPs []netip.Addr, err error)
+}
+
+// ContentUpdater is optionally implemented by providers able to set
+// records of types other than A and AAAA, such as TXT, SPF, SVCB and
+// HTTPS records, for the domain and owner of their record. The content
+// of TXT and SPF records is the unquoted text, and the content of other
+// record types is in the zone file presentation format.
+type ContentUpdater interface {
+	UpdateContent(ctx context.Context, client *http.Client, recordType, content string) (err error)
+}
+
+// AccountIdentifier is optionally implemented by providers whose
+// provider account cannot be found from the names of their settings.
+// AccountIdentity returns the settings identifying the provider account,
+// such as its credentials, which are only used to fingerprint the account.
+type AccountIdentifier interface {
+	AccountIdentity() string
+}
+
+// BatchUpdater is optionally implemented by providers able to update
+// several records in a single API call. Records of the same provider
+// account with the same batch key can be updated together, where the
+// batch key identifies the API scope of the record, such as its DNS zone.
+type BatchUpdater interface {
+	BatchKey() string
+	// UpdateBatch updates the records with the hostnames given, in the
+	// form owner.domain or domain for the @ owner, to the IP address given.
+	// The hostnames include the one of the batch updater, and their
+	// records have the same provider account and batch key. An error is
+	// returned for each hostname, in the order of the hostnames given,
+	// unless the whole batch failed.
+	UpdateBatch(ctx context.Context, client *http.Client, hostnames []string,
+		ip netip.Addr) (errs []error, err error)
+}
+
+var (
+	ErrProviderUnknown           = errors.New("unknown provider")
+	ErrRecordDeletionUnsupported = errors.New("provider does not support deleting records")
+)
 
 //nolint:gocyclo,maintidx
 func New(providerName models.Provider, data json.RawMessage, domain, owner string, //nolint:ireturn
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing *bool, ttl *uint32,
 ) (provider Provider, err error) {
+	create, err := resolveCreateIfMissing(providerName, createIfMissing)
+	if err != nil {
+		return nil, err
+	}
+
+	recordTTL, err := resolveTTL(providerName, ttl)
+	if err != nil {
+		return nil, err
+	}
+
 	switch providerName {
 	case constants.Aliyun:
-		return aliyun.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return aliyun.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.AllInkl:
 		return allinkl.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Bunny:
-		return bunny.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return bunny.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Changeip:
 		return changeip.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Cloudflare:
-		return cloudflare.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return cloudflare.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Custom:
 		return custom.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Dd24:
@@ -109,13 +195,13 @@ func New(providerName models.Provider, data json.RawMessage, domain, owner strin
 	case constants.DdnssDe:
 		return ddnss.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.DeSEC:
-		return desec.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return desec.New(data, domain, owner, ipVersion, ipv6Suffix, create)
 	case constants.DigitalOcean:
-		return digitalocean.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return digitalocean.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.DNSOMatic:
 		return dnsomatic.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.DNSPod:
-		return dnspod.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return dnspod.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Domeneshop:
 		return domeneshop.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.DonDominio:
@@ -134,50 +220,52 @@ func New(providerName models.Provider, data json.RawMessage, domain, owner strin
 		return easydns.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Example:
 		return example.New(data, domain, owner, ipVersion, ipv6Suffix)
+	case constants.Exec:
+		return exec.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.FreeDNS:
 		return freedns.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Gandi:
-		return gandi.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return gandi.New(data, domain, owner, ipVersion, ipv6Suffix, recordTTL)
 	case constants.GCP:
-		return gcp.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return gcp.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.GigahostNo:
 		return gigahostno.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.GoDaddy:
-		return godaddy.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return godaddy.New(data, domain, owner, ipVersion, ipv6Suffix, recordTTL)
 	case constants.GoIP:
 		return goip.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.HE:
 		return he.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Hetzner:
-		return hetzner.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return hetzner.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.HetznerCloud:
-		return hetznercloud.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return hetznercloud.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Hostinger:
-		return hostinger.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return hostinger.New(data, domain, owner, ipVersion, ipv6Suffix, recordTTL)
 	case constants.Infomaniak:
 		return infomaniak.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.INWX:
 		return inwx.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Ionos:
-		return ionos.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return ionos.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.IPv64:
 		return ipv64.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Linode:
-		return linode.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return linode.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Loopia:
 		return loopia.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.LuaDNS:
-		return luadns.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return luadns.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Myaddr:
 		return myaddr.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Namecheap:
 		return namecheap.New(data, domain, owner)
 	case constants.NameCom:
-		return namecom.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return namecom.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.NameSilo:
-		return namesilo.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return namesilo.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Netcup:
-		return netcup.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return netcup.New(data, domain, owner, ipVersion, ipv6Suffix, create)
 	case constants.Njalla:
 		return njalla.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.NoIP:
@@ -187,19 +275,19 @@ func New(providerName models.Provider, data json.RawMessage, domain, owner strin
 	case constants.OpenDNS:
 		return opendns.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.OVH:
-		return ovh.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return ovh.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Porkbun:
-		return porkbun.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return porkbun.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Route53:
-		return route53.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return route53.New(data, domain, owner, ipVersion, ipv6Suffix, recordTTL)
 	case constants.Scaleway:
-		return scaleway.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return scaleway.New(data, domain, owner, ipVersion, ipv6Suffix, recordTTL)
 	case constants.SelfhostDe:
 		return selfhostde.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Servercow:
-		return servercow.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return servercow.New(data, domain, owner, ipVersion, ipv6Suffix, recordTTL)
 	case constants.Spaceship:
-		return spaceship.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return spaceship.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Spdyn:
 		return spdyn.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Strato:
@@ -207,9 +295,11 @@ func New(providerName models.Provider, data json.RawMessage, domain, owner strin
 	case constants.Variomedia:
 		return variomedia.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Vercel:
-		return vercel.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return vercel.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
 	case constants.Vultr:
-		return vultr.New(data, domain, owner, ipVersion, ipv6Suffix)
+		return vultr.New(data, domain, owner, ipVersion, ipv6Suffix, create, recordTTL)
+	case constants.Webhook:
+		return webhook.New(data, domain, owner, ipVersion, ipv6Suffix)
 	case constants.Zoneedit:
 		return zoneedit.New(data, domain, owner, ipVersion, ipv6Suffix)
 	default:
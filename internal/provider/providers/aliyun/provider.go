@@ -16,17 +16,19 @@ import (
 )
 
 type Provider struct {
-	domain       string
-	owner        string
-	ipVersion    ipversion.IPVersion
-	ipv6Suffix   netip.Prefix
-	accessKeyID  string
-	accessSecret string
-	region       string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	accessKeyID     string
+	accessSecret    string
+	region          string
+	createIfMissing bool
+	ttl             uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -49,13 +51,15 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:       domain,
-		owner:        owner,
-		ipVersion:    ipVersion,
-		ipv6Suffix:   ipv6Suffix,
-		accessKeyID:  extraSettings.AccessKeyID,
-		accessSecret: extraSettings.AccessSecret,
-		region:       region,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		accessKeyID:     extraSettings.AccessKeyID,
+		accessSecret:    extraSettings.AccessSecret,
+		region:          region,
+		createIfMissing: createIfMissing,
+		ttl:             ttl,
 	}, nil
 }
 
@@ -119,7 +123,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	recordID, err := p.getRecordID(ctx, client, recordType)
-	if stderrors.Is(err, errors.ErrRecordNotFound) {
+	if stderrors.Is(err, errors.ErrRecordNotFound) && p.createIfMissing {
 		recordID, err = p.createRecord(ctx, client, ip)
 		if err != nil {
 			return newIP, fmt.Errorf("creating record: %w", err)
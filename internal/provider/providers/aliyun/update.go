@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/netip"
 	"net/url"
+	"strconv"
 
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
 	"github.com/qdm12/ddns-updater/internal/provider/errors"
@@ -30,6 +31,9 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 	values.Set("RR", p.owner)
 	values.Set("Type", recordType)
 	values.Set("Value", ip.String())
+	if p.ttl != 0 {
+		values.Set("TTL", strconv.FormatUint(uint64(p.ttl), 10))
+	}
 
 	sign(http.MethodGet, values, p.accessSecret)
 
@@ -21,39 +21,40 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	apiKey     string
-	ttl        uint32
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	apiKey          string
+	ttl             uint32
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
 		APIKey string `json:"api_key"`
-		TTL    uint32 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	err = validateSettings(domain, extraSettings.APIKey, extraSettings.TTL)
+	err = validateSettings(domain, extraSettings.APIKey, ttl)
 	if err != nil {
 		return nil, fmt.Errorf("validating provider specific settings: %w", err)
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		apiKey:     extraSettings.APIKey,
-		ttl:        extraSettings.TTL,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		apiKey:          extraSettings.APIKey,
+		ttl:             ttl,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -132,7 +133,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 
 	record, err := p.getRecord(ctx, client, zoneID, recordType)
 	if err != nil {
-		if !stderrors.Is(err, errors.ErrRecordNotFound) {
+		if !stderrors.Is(err, errors.ErrRecordNotFound) || !p.createIfMissing {
 			return netip.Addr{}, fmt.Errorf("getting record: %w", err)
 		}
 
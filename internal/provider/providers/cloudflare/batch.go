@@ -0,0 +1,178 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// BatchKey returns the zone identifier, TTL, proxied and create if missing
+// settings of the record, since records of the same zone can be updated in
+// a single batch request, which uses the settings of the first record.
+func (p *Provider) BatchKey() string {
+	return p.zoneIdentifier + "/" + strconv.FormatUint(uint64(p.ttl), 10) + "/" +
+		strconv.FormatBool(p.proxied) + "/" + strconv.FormatBool(p.createIfMissing)
+}
+
+type batchRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content"`
+	Proxied bool   `json:"proxied"`
+	TTL     uint32 `json:"ttl"`
+}
+
+type batchRequest struct {
+	Posts   []batchRecord `json:"posts,omitempty"`
+	Patches []batchRecord `json:"patches,omitempty"`
+}
+
+// UpdateBatch updates the records with the hostnames given to the IP
+// address given in a single batch request, which Cloudflare applies
+// atomically. Missing records are created in the same batch request
+// if createIfMissing is set.
+// See https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/batch/
+func (p *Provider) UpdateBatch(ctx context.Context, client *http.Client,
+	hostnames []string, ip netip.Addr,
+) (errs []error, err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+
+	zoneRecords, err := p.listZoneRecords(ctx, client, recordType)
+	if err != nil {
+		return nil, fmt.Errorf("listing zone records: %w", err)
+	}
+	nameToRecords := make(map[string][]dnsRecord, len(zoneRecords))
+	for _, record := range zoneRecords {
+		name := strings.ToLower(record.Name)
+		nameToRecords[name] = append(nameToRecords[name], record)
+	}
+
+	errs = make([]error, len(hostnames))
+	var request batchRequest
+	for i, hostname := range hostnames {
+		records := nameToRecords[strings.ToLower(hostname)]
+		switch {
+		case len(records) == 0 && !p.createIfMissing:
+			errs[i] = fmt.Errorf("%w", errors.ErrRecordNotFound)
+		case len(records) == 0:
+			request.Posts = append(request.Posts, batchRecord{
+				Type:    recordType,
+				Name:    hostname,
+				Content: ip.String(),
+				Proxied: p.proxied,
+				TTL:     p.ttl,
+			})
+		case len(records) > 1:
+			errs[i] = fmt.Errorf("%w: %d instead of 1",
+				errors.ErrResultsCountReceived, len(records))
+		case records[0].Content == ip.String(): // up to date
+		default:
+			request.Patches = append(request.Patches, batchRecord{
+				ID:      records[0].ID,
+				Content: ip.String(),
+				Proxied: p.proxied,
+				TTL:     p.ttl,
+			})
+		}
+	}
+
+	if len(request.Posts) == 0 && len(request.Patches) == 0 {
+		return errs, nil
+	}
+	err = p.sendBatch(ctx, client, request)
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// listZoneRecords lists all the records of the given type in the zone.
+func (p *Provider) listZoneRecords(ctx context.Context, client *http.Client,
+	recordType string,
+) (records []dnsRecord, err error) {
+	const perPage = 5000
+	values := url.Values{}
+	values.Set("type", recordType)
+	values.Set("per_page", strconv.Itoa(perPage))
+	for page := 1; ; page++ {
+		values.Set("page", strconv.Itoa(page))
+		pageRecords, totalPages, err := p.listRecordsPage(ctx, client, values)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+		records = append(records, pageRecords...)
+		if page >= totalPages {
+			return records, nil
+		}
+	}
+}
+
+func (p *Provider) sendBatch(ctx context.Context, client *http.Client,
+	batch batchRequest,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudflare.com",
+		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records/batch", p.zoneIdentifier),
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(batch)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode > http.StatusUnsupportedMediaType {
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var parsedJSON struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	err = decoder.Decode(&parsedJSON)
+	if err != nil {
+		return fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	if !parsedJSON.Success {
+		var sb strings.Builder
+		for _, e := range parsedJSON.Errors {
+			fmt.Fprintf(&sb, "error %d: %s; ", e.Code, e.Message)
+		}
+		return fmt.Errorf("%w: %s", errors.ErrUnsuccessful, sb.String())
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package cloudflare
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rewriteTransport redirects all requests (to api.cloudflare.com) to the
+// test server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+// newTestClient returns an HTTP client sending all its requests to
+// a test server running the handler given.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &http.Client{Transport: rewriteTransport{
+		host: strings.TrimPrefix(server.URL, "http://"),
+		base: http.DefaultTransport,
+	}}
+}
+
+func Test_Provider_UpdateBatch(t *testing.T) {
+	t.Parallel()
+
+	const listBody = `{"success":true,"result_info":{"total_pages":1},"result":[` +
+		`{"id":"1","name":"a.example.com","content":"1.2.3.4"},` +
+		`{"id":"2","name":"b.example.com","content":"5.6.7.8"},` +
+		`{"id":"3","name":"dup.example.com","content":"5.6.7.8"},` +
+		`{"id":"4","name":"dup.example.com","content":"5.6.7.8"}]}`
+
+	testCases := map[string]struct {
+		createIfMissing bool
+		hostnames       []string
+		batchStatus     int
+		batchBody       string
+		wantBatch       string
+		errs            []error
+		errWrapped      error
+		errMessage      string
+	}{
+		"all_up_to_date": {
+			hostnames: []string{"a.example.com"},
+			errs:      []error{nil},
+		},
+		"update_and_skip_invalid": {
+			hostnames:   []string{"a.example.com", "B.example.com", "dup.example.com", "c.example.com"},
+			batchStatus: http.StatusOK,
+			batchBody:   `{"success":true}`,
+			wantBatch: `{"patches":[{"id":"2","content":"1.2.3.4","proxied":false,"ttl":1}]}` +
+				"\n",
+			errs: []error{nil, nil, errors.ErrResultsCountReceived, errors.ErrRecordNotFound},
+		},
+		"create_missing": {
+			createIfMissing: true,
+			hostnames:       []string{"c.example.com"},
+			batchStatus:     http.StatusOK,
+			batchBody:       `{"success":true}`,
+			wantBatch: `{"posts":[{"type":"A","name":"c.example.com",` +
+				`"content":"1.2.3.4","proxied":false,"ttl":1}]}` + "\n",
+			errs: []error{nil},
+		},
+		"batch_unsuccessful": {
+			hostnames:   []string{"b.example.com"},
+			batchStatus: http.StatusBadRequest,
+			batchBody:   `{"success":false,"errors":[{"code":1004,"message":"bad"}]}`,
+			wantBatch: `{"patches":[{"id":"2","content":"1.2.3.4","proxied":false,"ttl":1}]}` +
+				"\n",
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: "unsuccessful result: error 1004: bad; ",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotBatch string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/client/v4/zones/zone/dns_records":
+					assert.Equal(t, "A", r.URL.Query().Get("type"))
+					_, _ = io.WriteString(w, listBody)
+				case "/client/v4/zones/zone/dns_records/batch":
+					body, err := io.ReadAll(r.Body)
+					assert.NoError(t, err)
+					gotBatch = string(body)
+					w.WriteHeader(testCase.batchStatus)
+					_, _ = io.WriteString(w, testCase.batchBody)
+				default:
+					t.Errorf("unexpected request path %s", r.URL.Path)
+				}
+			})
+
+			provider := &Provider{
+				token:           "token",
+				zoneIdentifier:  "zone",
+				ttl:             1,
+				createIfMissing: testCase.createIfMissing,
+			}
+
+			errs, err := provider.UpdateBatch(context.Background(), client,
+				testCase.hostnames, netip.MustParseAddr("1.2.3.4"))
+
+			assert.Equal(t, testCase.wantBatch, gotBatch)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.Len(t, errs, len(testCase.errs))
+			for i, wantErr := range testCase.errs {
+				if wantErr == nil {
+					assert.NoError(t, errs[i])
+				} else {
+					assert.ErrorIs(t, errs[i], wantErr)
+				}
+			}
+		})
+	}
+}
+
+func Test_Provider_listZoneRecords(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		_, _ = io.WriteString(w, `{"success":true,"result_info":{"total_pages":2},`+
+			`"result":[{"id":"`+page+`","name":"a.example.com","content":"1.2.3.4"}]}`)
+	})
+	provider := &Provider{token: "token", zoneIdentifier: "zone"}
+
+	records, err := provider.listZoneRecords(context.Background(), client, "A")
+
+	require.NoError(t, err)
+	expected := []dnsRecord{
+		{ID: "1", Name: "a.example.com", Content: "1.2.3.4"},
+		{ID: "2", Name: "a.example.com", Content: "1.2.3.4"},
+	}
+	assert.Equal(t, expected, records)
+}
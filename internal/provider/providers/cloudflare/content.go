@@ -0,0 +1,133 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// UpdateContent sets the record of the given type to the content given,
+// creating it if it does not exist and createIfMissing is set.
+// See https://developers.cloudflare.com/api/operations/dns-records-for-a-zone-update-dns-record
+func (p *Provider) UpdateContent(ctx context.Context, client *http.Client,
+	recordType, content string,
+) (err error) {
+	records, err := p.listRecords(ctx, client, recordType)
+	if err != nil {
+		return fmt.Errorf("listing records: %w", err)
+	}
+
+	contents := make([]string, len(records))
+	for i, record := range records {
+		contents[i] = record.Content
+		if utils.IsTXTType(recordType) {
+			contents[i] = utils.UnquoteTXT(record.Content)
+		}
+	}
+	index, err := utils.SelectContentRecord(contents, content)
+	switch {
+	case err != nil:
+		return err
+	case index == -1 && !p.createIfMissing:
+		return fmt.Errorf("%w", errors.ErrRecordNotFound)
+	case index == -1:
+		path := fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier)
+		return p.sendContentRecord(ctx, client, http.MethodPost, path, recordType, content)
+	case contents[index] == content:
+		return nil
+	}
+	path := fmt.Sprintf("/client/v4/zones/%s/dns_records/%s", p.zoneIdentifier, records[index].ID)
+	return p.sendContentRecord(ctx, client, http.MethodPut, path, recordType, content)
+}
+
+type svcbData struct {
+	Priority uint16 `json:"priority"`
+	Target   string `json:"target"`
+	Value    string `json:"value"`
+}
+
+func (p *Provider) sendContentRecord(ctx context.Context, client *http.Client,
+	method, path, recordType, content string,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudflare.com",
+		Path:   path,
+	}
+
+	requestData := struct {
+		Type    string    `json:"type"`
+		Name    string    `json:"name"`
+		Content string    `json:"content,omitempty"`
+		Data    *svcbData `json:"data,omitempty"`
+		TTL     uint32    `json:"ttl"`
+	}{
+		Type: recordType,
+		Name: utils.BuildURLQueryHostname(p.owner, p.domain),
+		TTL:  p.ttl,
+	}
+	if utils.IsTXTType(recordType) {
+		requestData.Content = content
+	} else {
+		// SVCB and HTTPS records are set with their data fields.
+		data := &svcbData{}
+		data.Priority, data.Target, data.Value, err = utils.SplitSVCB(content)
+		if err != nil {
+			return err
+		}
+		requestData.Data = data
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode > http.StatusUnsupportedMediaType {
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var parsedJSON struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	err = decoder.Decode(&parsedJSON)
+	if err != nil {
+		return fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	if !parsedJSON.Success {
+		var sb strings.Builder
+		for _, e := range parsedJSON.Errors {
+			fmt.Fprintf(&sb, "error %d: %s; ", e.Code, e.Message)
+		}
+		return fmt.Errorf("%w: %s", errors.ErrUnsuccessful, sb.String())
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// DeleteRecord deletes the records of the given type for the owner and domain.
+// See https://developers.cloudflare.com/api/operations/dns-records-for-a-zone-delete-dns-record
+func (p *Provider) DeleteRecord(ctx context.Context, client *http.Client, recordType string) (err error) {
+	records, err := p.listRecords(ctx, client, recordType)
+	if err != nil {
+		return fmt.Errorf("listing records: %w", err)
+	}
+
+	for _, record := range records {
+		err = p.deleteRecord(ctx, client, record.ID)
+		if err != nil {
+			return fmt.Errorf("deleting record %s: %w", record.ID, err)
+		}
+	}
+	return nil
+}
+
+type dnsRecord struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+func (p *Provider) listRecords(ctx context.Context, client *http.Client,
+	recordType string,
+) (records []dnsRecord, err error) {
+	values := url.Values{}
+	values.Set("type", recordType)
+	values.Set("name", utils.BuildURLQueryHostname(p.owner, p.domain))
+	records, _, err = p.listRecordsPage(ctx, client, values)
+	return records, err
+}
+
+// listRecordsPage lists the zone records matching the query values given,
+// and returns the total number of pages of results.
+func (p *Provider) listRecordsPage(ctx context.Context, client *http.Client,
+	values url.Values,
+) (records []dnsRecord, totalPages int, err error) {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "api.cloudflare.com",
+		Path:     fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier),
+		RawQuery: values.Encode(),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	listRecordsResponse := struct {
+		Success    bool        `json:"success"`
+		Errors     []string    `json:"errors"`
+		Result     []dnsRecord `json:"result"`
+		ResultInfo struct {
+			TotalPages int `json:"total_pages"`
+		} `json:"result_info"`
+	}{}
+	err = decoder.Decode(&listRecordsResponse)
+	if err != nil {
+		return nil, 0, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	switch {
+	case len(listRecordsResponse.Errors) > 0:
+		return nil, 0, fmt.Errorf("%w: %s",
+			errors.ErrUnsuccessful, strings.Join(listRecordsResponse.Errors, ","))
+	case !listRecordsResponse.Success:
+		return nil, 0, fmt.Errorf("%w", errors.ErrUnsuccessful)
+	}
+
+	return listRecordsResponse.Result, listRecordsResponse.ResultInfo.TotalPages, nil
+}
+
+func (p *Provider) deleteRecord(ctx context.Context, client *http.Client,
+	recordID string,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudflare.com",
+		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records/%s", p.zoneIdentifier, recordID),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,89 @@
+package cloudflare
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_DeleteRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		listBody     string
+		deleteStatus int
+		requests     []string
+		errWrapped   error
+		errMessage   string
+	}{
+		"records_deleted": {
+			listBody: `{"success":true,"result":[` +
+				`{"id":"1","content":"1.2.3.4"},{"id":"2","content":"5.6.7.8"}]}`,
+			deleteStatus: http.StatusOK,
+			requests: []string{
+				"GET /client/v4/zones/zone/dns_records",
+				"DELETE /client/v4/zones/zone/dns_records/1",
+				"DELETE /client/v4/zones/zone/dns_records/2",
+			},
+		},
+		"no_record": {
+			listBody: `{"success":true,"result":[]}`,
+			requests: []string{"GET /client/v4/zones/zone/dns_records"},
+		},
+		"record_already_deleted": {
+			listBody:     `{"success":true,"result":[{"id":"1","content":"1.2.3.4"}]}`,
+			deleteStatus: http.StatusNotFound,
+			requests: []string{
+				"GET /client/v4/zones/zone/dns_records",
+				"DELETE /client/v4/zones/zone/dns_records/1",
+			},
+		},
+		"delete_failed": {
+			listBody:     `{"success":true,"result":[{"id":"1","content":"1.2.3.4"}]}`,
+			deleteStatus: http.StatusInternalServerError,
+			requests: []string{
+				"GET /client/v4/zones/zone/dns_records",
+				"DELETE /client/v4/zones/zone/dns_records/1",
+			},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "deleting record 1: HTTP status is not valid: 500: error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests []string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if r.Method == http.MethodGet {
+					assert.Equal(t, "A", r.URL.Query().Get("type"))
+					assert.Equal(t, "sub.example.com", r.URL.Query().Get("name"))
+					_, _ = io.WriteString(w, testCase.listBody)
+					return
+				}
+				w.WriteHeader(testCase.deleteStatus)
+				_, _ = io.WriteString(w, "error")
+			})
+			provider := &Provider{
+				domain:         "example.com",
+				owner:          "sub",
+				token:          "token",
+				zoneIdentifier: "zone",
+			}
+
+			err := provider.DeleteRecord(context.Background(), client, "A")
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.requests, requests)
+		})
+	}
+}
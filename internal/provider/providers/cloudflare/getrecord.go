@@ -0,0 +1,89 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// GetRecord returns the IP addresses currently set for the record,
+// which reflects the origin IP address even for proxied records.
+func (p *Provider) GetRecord(ctx context.Context, client *http.Client) (ips []netip.Addr, err error) {
+	for _, recordType := range utils.RecordTypes(p.ipVersion) {
+		recordIPs, err := p.listRecordIPs(ctx, client, recordType)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s records: %w", recordType, err)
+		}
+		ips = append(ips, recordIPs...)
+	}
+	return ips, nil
+}
+
+func (p *Provider) listRecordIPs(ctx context.Context, client *http.Client,
+	recordType string,
+) (ips []netip.Addr, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.cloudflare.com",
+		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier),
+	}
+
+	values := url.Values{}
+	values.Set("type", recordType)
+	values.Set("name", utils.BuildURLQueryHostname(p.owner, p.domain))
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	listRecordsResponse := struct {
+		Success bool     `json:"success"`
+		Errors  []string `json:"errors"`
+		Result  []struct {
+			Content string `json:"content"`
+		} `json:"result"`
+	}{}
+	err = decoder.Decode(&listRecordsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	switch {
+	case len(listRecordsResponse.Errors) > 0:
+		return nil, fmt.Errorf("%w: %s",
+			errors.ErrUnsuccessful, strings.Join(listRecordsResponse.Errors, ","))
+	case !listRecordsResponse.Success:
+		return nil, fmt.Errorf("%w", errors.ErrUnsuccessful)
+	}
+
+	ips = make([]netip.Addr, len(listRecordsResponse.Result))
+	for i, result := range listRecordsResponse.Result {
+		ips[i], err = netip.ParseAddr(result.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+		}
+	}
+	return ips, nil
+}
@@ -0,0 +1,91 @@
+package cloudflare
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_GetRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ipVersion  ipversion.IPVersion
+		statusCode int
+		bodies     map[string]string
+		ips        []netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"dual_stack": {
+			ipVersion:  ipversion.IP4or6,
+			statusCode: http.StatusOK,
+			bodies: map[string]string{
+				"A":    `{"success":true,"result":[{"content":"1.2.3.4"}]}`,
+				"AAAA": `{"success":true,"result":[{"content":"::1"}]}`,
+			},
+			ips: []netip.Addr{netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("::1")},
+		},
+		"no_record": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			bodies:     map[string]string{"A": `{"success":true,"result":[]}`},
+		},
+		"malformed_ip": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			bodies:     map[string]string{"A": `{"success":true,"result":[{"content":"x"}]}`},
+			errWrapped: errors.ErrIPReceivedMalformed,
+			errMessage: `listing A records: malformed IP address received: ` +
+				`ParseAddr("x"): unable to parse IP`,
+		},
+		"unsuccessful": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			bodies:     map[string]string{"A": `{"success":false,"errors":["bad"]}`},
+			errWrapped: errors.ErrUnsuccessful,
+			errMessage: "listing A records: unsuccessful result: bad",
+		},
+		"bad_status": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusForbidden,
+			bodies:     map[string]string{"A": `forbidden`},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "listing A records: HTTP status is not valid: 403: forbidden",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/client/v4/zones/zone/dns_records", r.URL.Path)
+				assert.Equal(t, "sub.example.com", r.URL.Query().Get("name"))
+				w.WriteHeader(testCase.statusCode)
+				_, _ = io.WriteString(w, testCase.bodies[r.URL.Query().Get("type")])
+			})
+			provider := &Provider{
+				domain:         "example.com",
+				owner:          "sub",
+				ipVersion:      testCase.ipVersion,
+				token:          "token",
+				zoneIdentifier: "zone",
+			}
+
+			ips, err := provider.GetRecord(context.Background(), client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ips, ips)
+		})
+	}
+}
@@ -21,21 +21,22 @@ import (
 )
 
 type Provider struct {
-	domain         string
-	owner          string
-	ipVersion      ipversion.IPVersion
-	ipv6Suffix     netip.Prefix
-	key            string
-	token          string
-	email          string
-	userServiceKey string
-	zoneIdentifier string
-	proxied        bool
-	ttl            uint32
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	key             string
+	token           string
+	email           string
+	userServiceKey  string
+	zoneIdentifier  string
+	proxied         bool
+	ttl             uint32
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -45,7 +46,6 @@ func New(data json.RawMessage, domain, owner string,
 		UserServiceKey string `json:"user_service_key"`
 		ZoneIdentifier string `json:"zone_identifier"`
 		Proxied        bool   `json:"proxied"`
-		TTL            uint32 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -53,23 +53,24 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	err = validateSettings(domain, extraSettings.Email, extraSettings.Key, extraSettings.UserServiceKey,
-		extraSettings.ZoneIdentifier, extraSettings.TTL)
+		extraSettings.ZoneIdentifier, ttl)
 	if err != nil {
 		return nil, fmt.Errorf("validating provider specific settings: %w", err)
 	}
 
 	return &Provider{
-		domain:         domain,
-		owner:          owner,
-		ipVersion:      ipVersion,
-		ipv6Suffix:     ipv6Suffix,
-		key:            extraSettings.Key,
-		token:          extraSettings.Token,
-		email:          extraSettings.Email,
-		userServiceKey: extraSettings.UserServiceKey,
-		zoneIdentifier: extraSettings.ZoneIdentifier,
-		proxied:        extraSettings.Proxied,
-		ttl:            extraSettings.TTL,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		key:             extraSettings.Key,
+		token:           extraSettings.Token,
+		email:           extraSettings.Email,
+		userServiceKey:  extraSettings.UserServiceKey,
+		zoneIdentifier:  extraSettings.ZoneIdentifier,
+		proxied:         extraSettings.Proxied,
+		ttl:             ttl,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -322,7 +323,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	identifier, upToDate, err := p.getRecordID(ctx, client, ip)
 
 	switch {
-	case stderrors.Is(err, errors.ErrReceivedNoResult):
+	case stderrors.Is(err, errors.ErrReceivedNoResult) && p.createIfMissing:
 		identifier, err = p.createRecord(ctx, client, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
@@ -411,3 +412,57 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	return newIP, nil
 }
+
+// UpdateMulti sets the A and AAAA records to the IPv4 and IPv6 addresses
+// given, creating a record for each new IP address before deleting the
+// records of the IP addresses no longer given. All the records of an IP
+// family of the record IP version are deleted if no IP address of this
+// family is given.
+func (p *Provider) UpdateMulti(ctx context.Context, client *http.Client,
+	ips []netip.Addr,
+) (newIPs []netip.Addr, err error) {
+	for _, recordSet := range utils.GroupManagedRecordSets(p.ipVersion, ips) {
+		err = p.setRecords(ctx, client, recordSet)
+		if err != nil {
+			return nil, fmt.Errorf("setting %s records: %w", recordSet.Type, err)
+		}
+	}
+	return ips, nil
+}
+
+func (p *Provider) setRecords(ctx context.Context, client *http.Client,
+	recordSet utils.RecordSet,
+) (err error) {
+	records, err := p.listRecords(ctx, client, recordSet.Type)
+	if err != nil {
+		return fmt.Errorf("listing records: %w", err)
+	} else if len(records) == 0 && len(recordSet.IPs) > 0 && !p.createIfMissing {
+		return fmt.Errorf("%w", errors.ErrRecordNotFound)
+	}
+
+	contentToID := make(map[string]string, len(records))
+	for _, record := range records {
+		contentToID[record.Content] = record.ID
+	}
+
+	for _, ip := range recordSet.IPs {
+		_, exists := contentToID[ip.String()]
+		if exists {
+			delete(contentToID, ip.String())
+			continue
+		}
+		_, err = p.createRecord(ctx, client, ip)
+		if err != nil {
+			return fmt.Errorf("creating record for %s: %w", ip, err)
+		}
+	}
+
+	// Records left are for IP addresses no longer given.
+	for content, recordID := range contentToID {
+		err = p.deleteRecord(ctx, client, recordID)
+		if err != nil {
+			return fmt.Errorf("deleting record for %s: %w", content, err)
+		}
+	}
+	return nil
+}
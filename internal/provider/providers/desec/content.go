@@ -0,0 +1,139 @@
+package desec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// UpdateContent sets the record of the given type matching the content
+// given in the existing record set, using the deSEC REST API since the
+// dynDNS API only handles A and AAAA records.
+// See https://desec.readthedocs.io/en/latest/dns/rrsets.html
+func (p *Provider) UpdateContent(ctx context.Context, client *http.Client,
+	recordType, content string,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "desec.io",
+		Path:   fmt.Sprintf("/api/v1/domains/%s/rrsets/%s/%s/", p.domain, p.owner, recordType),
+	}
+
+	values, err := p.getRRSetRecords(ctx, client, u)
+	if err != nil {
+		return fmt.Errorf("getting record set: %w", err)
+	}
+
+	contents := make([]string, len(values))
+	for i, value := range values {
+		contents[i] = value
+		if utils.IsTXTType(recordType) {
+			contents[i] = utils.UnquoteTXT(value)
+		}
+	}
+	index, err := utils.SelectContentRecord(contents, content)
+	switch {
+	case err != nil:
+		return err
+	case index == -1:
+		// The record set exists, the new content is added to it.
+		values = append(values, "")
+		index = len(values) - 1
+	case contents[index] == content:
+		return nil
+	}
+	values[index] = content
+	if utils.IsTXTType(recordType) {
+		values[index] = utils.QuoteTXT(content)
+	}
+
+	return p.patchRRSetRecords(ctx, client, u, values)
+}
+
+func (p *Provider) getRRSetRecords(ctx context.Context, client *http.Client,
+	u url.URL,
+) (values []string, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setRESTHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w", errors.ErrRecordNotFound)
+	default:
+		return nil, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var rrSet struct {
+		Records []string `json:"records"`
+	}
+	err = decoder.Decode(&rrSet)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+	return slices.Clip(rrSet.Records), nil
+}
+
+func (p *Provider) patchRRSetRecords(ctx context.Context, client *http.Client,
+	u url.URL, values []string,
+) (err error) {
+	requestData := struct {
+		Records []string `json:"records"`
+	}{Records: values}
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setRESTHeaders(request)
+	headers.SetContentType(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
+
+func (p *Provider) setRESTHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("Authorization", "Token "+p.token)
+}
@@ -0,0 +1,91 @@
+package desec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// createRRSets creates the record sets given which have IP addresses,
+// using the deSEC REST API, since the dynDNS API cannot create records.
+// See https://desec.readthedocs.io/en/latest/dns/rrsets.html#bulk-creation-of-rrsets
+func (p *Provider) createRRSets(ctx context.Context, client *http.Client,
+	recordSets []utils.RecordSet,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "desec.io",
+		Path:   fmt.Sprintf("/api/v1/domains/%s/rrsets/", p.domain),
+	}
+
+	// deSEC requires a TTL to create a record set, and
+	// 3600 seconds is the minimum TTL allowed by default.
+	const ttl = 3600
+	subname := p.owner
+	if subname == "@" {
+		subname = ""
+	}
+
+	type rrSet struct {
+		Subname string   `json:"subname"`
+		Type    string   `json:"type"`
+		TTL     uint32   `json:"ttl"`
+		Records []string `json:"records"`
+	}
+	requestData := make([]rrSet, 0, len(recordSets))
+	for _, recordSet := range recordSets {
+		if len(recordSet.IPs) == 0 {
+			continue
+		}
+		records := make([]string, len(recordSet.IPs))
+		for i, ip := range recordSet.IPs {
+			records[i] = ip.String()
+		}
+		requestData = append(requestData, rrSet{
+			Subname: subname,
+			Type:    recordSet.Type,
+			TTL:     ttl,
+			Records: records,
+		})
+	}
+	if len(requestData) == 0 {
+		return nil
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setRESTHeaders(request)
+	headers.SetContentType(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusCreated:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
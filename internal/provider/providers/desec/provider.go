@@ -3,6 +3,7 @@ package desec
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"net/http"
 	"net/netip"
@@ -18,15 +19,16 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	token      string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	token           string
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -46,11 +48,12 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		token:      extraSettings.Token,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		token:           extraSettings.Token,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -104,6 +107,49 @@ func (p *Provider) HTML() models.HTMLRow {
 }
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	err = p.updateOrCreate(ctx, client, utils.GroupRecordSets([]netip.Addr{ip}))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return ip, nil
+}
+
+// UpdateMulti sets the A and AAAA record sets to the IPv4 and IPv6
+// addresses given, in a single request. The record set of an IP family
+// of the record IP version is deleted if no IP address of this family
+// is given.
+func (p *Provider) UpdateMulti(ctx context.Context, client *http.Client,
+	ips []netip.Addr,
+) (newIPs []netip.Addr, err error) {
+	err = p.updateOrCreate(ctx, client, utils.GroupManagedRecordSets(p.ipVersion, ips))
+	if err != nil {
+		return nil, err
+	}
+	return ips, nil
+}
+
+// updateOrCreate updates the record with the record sets given, and
+// creates it if it does not exist and createIfMissing is set.
+func (p *Provider) updateOrCreate(ctx context.Context, client *http.Client,
+	recordSets []utils.RecordSet,
+) (err error) {
+	err = p.update(ctx, client, recordSets)
+	if !p.createIfMissing || !stderrors.Is(err, errors.ErrHostnameNotExists) {
+		return err
+	}
+	err = p.createRRSets(ctx, client, recordSets)
+	if err != nil {
+		return fmt.Errorf("creating record: %w", err)
+	}
+	return nil
+}
+
+// update sets the A and AAAA record sets to the IP addresses of the
+// record sets given, deleting a record set given without IP address,
+// and preserving the record set of an IP family not given.
+func (p *Provider) update(ctx context.Context, client *http.Client,
+	recordSets []utils.RecordSet,
+) (err error) {
 	u := url.URL{
 		Scheme: "https",
 		User:   url.UserPassword(p.BuildDomainName(), p.token),
@@ -112,52 +158,64 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	values := url.Values{}
 	values.Set("hostname", utils.BuildURLQueryHostname(p.owner, p.domain))
-	if ip.Is6() {
-		values.Set("myipv6", ip.String())
-		values.Set("myipv4", "preserve")
-	} else {
-		values.Set("myipv4", ip.String())
-		values.Set("myipv6", "preserve")
-	}
-	values.Set("myip", ip.String())
+	values.Set("myipv4", "preserve")
+	values.Set("myipv6", "preserve")
+	// Multiple IP addresses of the same family are separated by commas,
+	// and an empty value deletes the record set of the family.
+	var ips []netip.Addr
+	for _, recordSet := range recordSets {
+		ipStrings := make([]string, len(recordSet.IPs))
+		for i, ip := range recordSet.IPs {
+			ipStrings[i] = ip.String()
+		}
+		key := "myipv4"
+		if recordSet.Type == constants.AAAA {
+			key = "myipv6"
+		}
+		values.Set(key, strings.Join(ipStrings, ","))
+		ips = append(ips, recordSet.IPs...)
+	}
+	if len(ips) == 1 {
+		values.Set("myip", ips[0].String())
+	}
 	u.RawQuery = values.Encode()
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+		return fmt.Errorf("creating http request: %w", err)
 	}
 	headers.SetUserAgent(request)
 
 	response, err := client.Do(request)
 	if err != nil {
-		return netip.Addr{}, err
+		return err
 	}
 	defer response.Body.Close()
 
 	s, err := utils.ReadAndCleanBody(response.Body)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("reading response: %w", err)
+		return fmt.Errorf("reading response: %w", err)
 	}
 
 	switch response.StatusCode {
 	case http.StatusOK:
 	case http.StatusUnauthorized:
-		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrAuth, utils.ToSingleLine(s))
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.ToSingleLine(s))
 	case http.StatusNotFound:
-		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrHostnameNotExists, utils.ToSingleLine(s))
+		return fmt.Errorf("%w: %s", errors.ErrHostnameNotExists, utils.ToSingleLine(s))
 	default:
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
+		return fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid,
 			response.StatusCode, utils.ToSingleLine(s))
 	}
 
 	switch {
 	case strings.HasPrefix(s, constants.Notfqdn):
-		return netip.Addr{}, fmt.Errorf("%w", errors.ErrHostnameNotExists)
+		return fmt.Errorf("%w", errors.ErrHostnameNotExists)
 	case strings.HasPrefix(s, "badrequest"):
-		return netip.Addr{}, fmt.Errorf("%w", errors.ErrBadRequest)
+		return fmt.Errorf("%w", errors.ErrBadRequest)
 	case strings.HasPrefix(s, "good"):
-		return ip, nil
+		return nil
 	default:
-		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, utils.ToSingleLine(s))
+		return fmt.Errorf("%w: %s", errors.ErrUnknownResponse, utils.ToSingleLine(s))
 	}
 }
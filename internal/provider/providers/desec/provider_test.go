@@ -0,0 +1,105 @@
+package desec
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport redirects all requests (to update.dedyn.io and
+// desec.io) to the test server, without having to make the provider
+// code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+func Test_Provider_Update_createIfMissing(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		createIfMissing bool
+		createStatus    int
+		paths           []string
+		errWrapped      error
+		errMessage      string
+	}{
+		"record_created": {
+			createIfMissing: true,
+			createStatus:    http.StatusCreated,
+			paths:           []string{"/", "/api/v1/domains/example.com/rrsets/"},
+		},
+		"create_failed": {
+			createIfMissing: true,
+			createStatus:    http.StatusBadRequest,
+			paths:           []string{"/", "/api/v1/domains/example.com/rrsets/"},
+			errWrapped:      errors.ErrHTTPStatusNotValid,
+			errMessage:      "creating record: HTTP status is not valid: 400: bad request",
+		},
+		"create_disabled": {
+			paths:      []string{"/"},
+			errWrapped: errors.ErrHostnameNotExists,
+			errMessage: "hostname does not exist: not found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				if r.URL.Path == "/" {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = io.WriteString(w, "not found")
+					return
+				}
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "Token token", r.Header.Get("Authorization"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, `[{"subname":"sub","type":"A","ttl":3600,"records":["1.2.3.4"]}]`+"\n",
+					string(body))
+				w.WriteHeader(testCase.createStatus)
+				if testCase.createStatus != http.StatusCreated {
+					_, _ = io.WriteString(w, "bad request")
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			client := &http.Client{Transport: rewriteTransport{
+				host: server.Listener.Addr().String(),
+				base: http.DefaultTransport,
+			}}
+			provider := &Provider{
+				domain:          "example.com",
+				owner:           "sub",
+				token:           "token",
+				createIfMissing: testCase.createIfMissing,
+			}
+
+			newIP, err := provider.Update(context.Background(), client, netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.Equal(t, netip.MustParseAddr("1.2.3.4"), newIP)
+			}
+			assert.Equal(t, testCase.paths, paths)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package digitalocean
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// UpdateContent sets the record of the given type matching the content
+// given, creating it if it does not exist and createIfMissing is set.
+// See https://docs.digitalocean.com/reference/api/digitalocean/#tag/Domain-Records/operation/domains_update_record
+func (p *Provider) UpdateContent(ctx context.Context, client *http.Client,
+	recordType, content string,
+) (err error) {
+	records, err := p.listRecords(ctx, client, recordType)
+	if err != nil {
+		return fmt.Errorf("listing records: %w", err)
+	}
+
+	contents := make([]string, len(records))
+	for i, record := range records {
+		contents[i] = record.Data
+	}
+	index, err := utils.SelectContentRecord(contents, content)
+	switch {
+	case err != nil:
+		return err
+	case index == -1 && !p.createIfMissing:
+		return fmt.Errorf("%w", errors.ErrRecordNotFound)
+	case index == -1:
+		return p.sendContentRecord(ctx, client, http.MethodPost,
+			"/v2/domains/"+p.domain+"/records", http.StatusCreated, recordType, content)
+	case contents[index] == content:
+		return nil
+	}
+	return p.sendContentRecord(ctx, client, http.MethodPut,
+		fmt.Sprintf("/v2/domains/%s/records/%d", p.domain, records[index].ID),
+		http.StatusOK, recordType, content)
+}
+
+type domainRecord struct {
+	ID   int    `json:"id"`
+	Data string `json:"data"`
+}
+
+func (p *Provider) listRecords(ctx context.Context, client *http.Client,
+	recordType string,
+) (records []domainRecord, err error) {
+	values := url.Values{}
+	values.Set("name", utils.BuildURLQueryHostname(p.owner, p.domain))
+	values.Set("type", recordType)
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "api.digitalocean.com",
+		Path:     "/v2/domains/" + p.domain + "/records",
+		RawQuery: values.Encode(),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setCommonHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var result struct {
+		DomainRecords []domainRecord `json:"domain_records"`
+	}
+	err = decoder.Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+	return result.DomainRecords, nil
+}
+
+func (p *Provider) sendContentRecord(ctx context.Context, client *http.Client,
+	method, path string, expectedStatus int, recordType, content string,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.digitalocean.com",
+		Path:   path,
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	requestData := struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+		Data string `json:"data"`
+		TTL  uint32 `json:"ttl,omitempty"`
+	}{
+		Type: recordType,
+		Name: p.owner,
+		Data: content,
+		TTL:  p.ttl,
+	}
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setCommonHeaders(request)
+	headers.SetContentType(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != expectedStatus {
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package digitalocean
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// DeleteRecord deletes the record of the given type for the owner and domain.
+// See https://docs.digitalocean.com/reference/api/digitalocean/#tag/Domain-Records/operation/domains_delete_record
+func (p *Provider) DeleteRecord(ctx context.Context, client *http.Client, recordType string) (err error) {
+	recordID, err := p.getRecordID(ctx, recordType, client)
+	switch {
+	case stderrors.Is(err, errors.ErrReceivedNoResult):
+		return nil
+	case err != nil:
+		return fmt.Errorf("getting record id: %w", err)
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.digitalocean.com",
+		Path:   fmt.Sprintf("/v2/domains/%s/records/%d", p.domain, recordID),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setCommonHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,82 @@
+package digitalocean
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_DeleteRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		listBody     string
+		deleteStatus int
+		requests     []string
+		errWrapped   error
+		errMessage   string
+	}{
+		"record_deleted": {
+			listBody:     `{"domain_records":[{"id":5}]}`,
+			deleteStatus: http.StatusNoContent,
+			requests: []string{
+				"GET /v2/domains/example.com/records",
+				"DELETE /v2/domains/example.com/records/5",
+			},
+		},
+		"no_record": {
+			listBody: `{"domain_records":[]}`,
+			requests: []string{"GET /v2/domains/example.com/records"},
+		},
+		"record_already_deleted": {
+			listBody:     `{"domain_records":[{"id":5}]}`,
+			deleteStatus: http.StatusNotFound,
+			requests: []string{
+				"GET /v2/domains/example.com/records",
+				"DELETE /v2/domains/example.com/records/5",
+			},
+		},
+		"delete_failed": {
+			listBody:     `{"domain_records":[{"id":5}]}`,
+			deleteStatus: http.StatusInternalServerError,
+			requests: []string{
+				"GET /v2/domains/example.com/records",
+				"DELETE /v2/domains/example.com/records/5",
+			},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "HTTP status is not valid: 500: error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests []string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if r.Method == http.MethodGet {
+					assert.Equal(t, "AAAA", r.URL.Query().Get("type"))
+					assert.Equal(t, "sub.example.com", r.URL.Query().Get("name"))
+					_, _ = io.WriteString(w, testCase.listBody)
+					return
+				}
+				w.WriteHeader(testCase.deleteStatus)
+				_, _ = io.WriteString(w, "error")
+			})
+			provider := &Provider{domain: "example.com", owner: "sub", token: "token"}
+
+			err := provider.DeleteRecord(context.Background(), client, "AAAA")
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.requests, requests)
+		})
+	}
+}
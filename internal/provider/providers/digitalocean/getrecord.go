@@ -0,0 +1,76 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// GetRecord returns the IP addresses currently set for the record.
+func (p *Provider) GetRecord(ctx context.Context, client *http.Client) (ips []netip.Addr, err error) {
+	for _, recordType := range utils.RecordTypes(p.ipVersion) {
+		recordIPs, err := p.listRecordIPs(ctx, client, recordType)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s records: %w", recordType, err)
+		}
+		ips = append(ips, recordIPs...)
+	}
+	return ips, nil
+}
+
+func (p *Provider) listRecordIPs(ctx context.Context, client *http.Client,
+	recordType string,
+) (ips []netip.Addr, err error) {
+	values := url.Values{}
+	values.Set("name", utils.BuildURLQueryHostname(p.owner, p.domain))
+	values.Set("type", recordType)
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "api.digitalocean.com",
+		Path:     "/v2/domains/" + p.domain + "/records",
+		RawQuery: values.Encode(),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setCommonHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var result struct {
+		DomainRecords []struct {
+			Data string `json:"data"`
+		} `json:"domain_records"`
+	}
+	err = decoder.Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	ips = make([]netip.Addr, len(result.DomainRecords))
+	for i, domainRecord := range result.DomainRecords {
+		ips[i], err = netip.ParseAddr(domainRecord.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+		}
+	}
+	return ips, nil
+}
@@ -0,0 +1,110 @@
+package digitalocean
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport redirects all requests (to api.digitalocean.com) to the
+// test server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+// newTestClient returns an HTTP client sending all its requests to
+// a test server running the handler given.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &http.Client{Transport: rewriteTransport{
+		host: strings.TrimPrefix(server.URL, "http://"),
+		base: http.DefaultTransport,
+	}}
+}
+
+func Test_Provider_GetRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ipVersion  ipversion.IPVersion
+		statusCode int
+		bodies     map[string]string
+		ips        []netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"dual_stack": {
+			ipVersion:  ipversion.IP4or6,
+			statusCode: http.StatusOK,
+			bodies: map[string]string{
+				"A":    `{"domain_records":[{"data":"1.2.3.4"}]}`,
+				"AAAA": `{"domain_records":[{"data":"::1"}]}`,
+			},
+			ips: []netip.Addr{netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("::1")},
+		},
+		"no_record": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			bodies:     map[string]string{"A": `{"domain_records":[]}`},
+		},
+		"malformed_ip": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			bodies:     map[string]string{"A": `{"domain_records":[{"data":"x"}]}`},
+			errWrapped: errors.ErrIPReceivedMalformed,
+			errMessage: `listing A records: malformed IP address received: ` +
+				`ParseAddr("x"): unable to parse IP`,
+		},
+		"bad_status": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusUnauthorized,
+			bodies:     map[string]string{"A": `unauthorized`},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "listing A records: HTTP status is not valid: 401: unauthorized",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/domains/example.com/records", r.URL.Path)
+				assert.Equal(t, "sub.example.com", r.URL.Query().Get("name"))
+				w.WriteHeader(testCase.statusCode)
+				_, _ = io.WriteString(w, testCase.bodies[r.URL.Query().Get("type")])
+			})
+			provider := &Provider{
+				domain:    "example.com",
+				owner:     "sub",
+				ipVersion: testCase.ipVersion,
+				token:     "token",
+			}
+
+			ips, err := provider.GetRecord(context.Background(), client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ips, ips)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"net/http"
 	"net/netip"
@@ -18,15 +19,17 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	token      string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	token           string
+	createIfMissing bool
+	ttl             uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -43,11 +46,13 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		token:      extraSettings.Token,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		token:           extraSettings.Token,
+		createIfMissing: createIfMissing,
+		ttl:             ttl,
 	}, nil
 }
 
@@ -163,7 +168,14 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	recordID, err := p.getRecordID(ctx, recordType, client)
-	if err != nil {
+	switch {
+	case stderrors.Is(err, errors.ErrReceivedNoResult) && p.createIfMissing:
+		err = p.createRecord(ctx, client, recordType, ip)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
+		}
+		return ip, nil
+	case err != nil:
 		return netip.Addr{}, fmt.Errorf("getting record id: %w", err)
 	}
 
@@ -179,10 +191,12 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		Type string `json:"type"`
 		Name string `json:"name"`
 		Data string `json:"data"`
+		TTL  uint32 `json:"ttl,omitempty"`
 	}{
 		Type: recordType,
 		Name: p.owner,
 		Data: ip.String(),
+		TTL:  p.ttl,
 	}
 	err = encoder.Encode(requestData)
 	if err != nil {
@@ -108,6 +108,66 @@ func (p *Provider) HTML() models.HTMLRow {
 }
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	hostname, wildcard := utils.BuildURLQueryHostname(p.owner, p.domain), "NOCHG"
+	if p.owner == "*" {
+		hostname, wildcard = p.domain, "ON"
+	}
+	s, statusCode, err := p.request(ctx, client, hostname, wildcard, ip)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return parseResponse(s, statusCode, ip)
+}
+
+// BatchKey returns the username of the record, since records of the
+// same username can be updated in a single request. Wildcard records
+// are keyed by their domain as well, since they cannot be batched with
+// other records.
+func (p *Provider) BatchKey() string {
+	if p.owner == "*" {
+		return p.username + " *." + p.domain
+	}
+	return p.username
+}
+
+// UpdateBatch updates the records with the hostnames given to the
+// IP address given in a single request.
+func (p *Provider) UpdateBatch(ctx context.Context, client *http.Client,
+	hostnames []string, ip netip.Addr,
+) (errs []error, err error) {
+	if p.owner == "*" {
+		// a wildcard record is always alone in its batch
+		_, err = p.Update(ctx, client, ip)
+		return []error{err}, nil
+	}
+
+	s, statusCode, err := p.request(ctx, client, strings.Join(hostnames, ","), "NOCHG", ip)
+	if err != nil {
+		return nil, err
+	}
+
+	// A line is received for each hostname, unless the whole request failed.
+	lines := strings.Split(s, "\n")
+	if len(lines) != len(hostnames) {
+		_, err = parseResponse(s, statusCode, ip)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %d lines for %d hostnames",
+			errors.ErrResultsCountReceived, len(lines), len(hostnames))
+	}
+	errs = make([]error, len(hostnames))
+	for i, line := range lines {
+		_, errs[i] = parseResponse(strings.TrimSpace(line), statusCode, ip)
+	}
+	return errs, nil
+}
+
+// request sends an update request for the comma separated hostnames
+// given, and returns the cleaned response body and status code.
+func (p *Provider) request(ctx context.Context, client *http.Client,
+	hostnames, wildcard string, ip netip.Addr,
+) (s string, statusCode int, err error) {
 	// Multiple hostnames can be updated in one query, see https://www.dnsomatic.com/docs/api
 	u := url.URL{
 		Scheme: "https",
@@ -117,37 +177,36 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	values := url.Values{}
 	values.Set("myip", ip.String())
-	values.Set("wildcard", "NOCHG")
-	if p.owner == "*" {
-		values.Set("hostname", p.domain)
-		values.Set("wildcard", "ON")
-	} else {
-		values.Set("hostname", utils.BuildURLQueryHostname(p.owner, p.domain))
-	}
+	values.Set("wildcard", wildcard)
+	values.Set("hostname", hostnames)
 	values.Set("mx", "NOCHG")
 	values.Set("backmx", "NOCHG")
 	u.RawQuery = values.Encode()
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+		return "", 0, fmt.Errorf("creating http request: %w", err)
 	}
 	headers.SetUserAgent(request)
 
 	response, err := client.Do(request)
 	if err != nil {
-		return netip.Addr{}, err
+		return "", 0, err
 	}
 	defer response.Body.Close()
 
-	s, err := utils.ReadAndCleanBody(response.Body)
+	s, err = utils.ReadAndCleanBody(response.Body)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("reading response: %w", err)
+		return "", 0, fmt.Errorf("reading response: %w", err)
 	}
+	return s, response.StatusCode, nil
+}
 
-	if response.StatusCode != http.StatusOK {
+// parseResponse parses the response s for a single hostname.
+func parseResponse(s string, statusCode int, ip netip.Addr) (newIP netip.Addr, err error) {
+	if statusCode != http.StatusOK {
 		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, s)
+			errors.ErrHTTPStatusNotValid, statusCode, s)
 	}
 
 	switch s {
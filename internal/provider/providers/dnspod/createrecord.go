@@ -0,0 +1,79 @@
+package dnspod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// defaultRecordLine is the record line for all resolvers,
+// named "默认" (default) by the DNSPod API.
+const defaultRecordLine = "默认"
+
+// See https://docs.dnspod.cn/api/5f562a1de75cf42d25bf6780/
+func (p *Provider) createRecord(ctx context.Context, client *http.Client,
+	recordType string, ip netip.Addr,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "dnsapi.cn",
+		Path:   "/Record.Create",
+	}
+
+	values := url.Values{}
+	values.Set("login_token", p.token)
+	values.Set("format", "json")
+	values.Set("domain", p.domain)
+	values.Set("sub_domain", p.owner)
+	values.Set("record_type", recordType)
+	values.Set("record_line", defaultRecordLine)
+	values.Set("value", ip.String())
+	if p.ttl != 0 {
+		values.Set("ttl", strconv.FormatUint(uint64(p.ttl), 10))
+	}
+	buffer := bytes.NewBufferString(values.Encode())
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var createResp struct {
+		Status struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"status"`
+	}
+	err = decoder.Decode(&createResp)
+	if err != nil {
+		return fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	const successCode = "1"
+	if createResp.Status.Code != successCode {
+		return fmt.Errorf("%w: code %s: %s", errors.ErrUnsuccessful,
+			createResp.Status.Code, createResp.Status.Message)
+	}
+	return nil
+}
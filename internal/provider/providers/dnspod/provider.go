@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/netip"
 	"net/url"
+	"strconv"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
@@ -19,15 +20,17 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	token      string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	token           string
+	createIfMissing bool
+	ttl             uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -44,11 +47,13 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		token:      extraSettings.Token,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		token:           extraSettings.Token,
+		createIfMissing: createIfMissing,
+		ttl:             ttl,
 	}, nil
 }
 
@@ -173,7 +178,14 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		}
 	}
 	if recordID == "" {
-		return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		if !p.createIfMissing {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
+		err = p.createRecord(ctx, client, recordType, ip)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
+		}
+		return ip, nil
 	}
 
 	u.Path = "/Record.Ddns"
@@ -185,6 +197,13 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	values.Set("value", ip.String())
 	values.Set("record_line", recordLine)
 	values.Set("sub_domain", p.owner)
+	if p.ttl != 0 {
+		// Record.Ddns cannot set the TTL, so use Record.Modify instead.
+		// See https://docs.dnspod.cn/api/5f5627a1e75cf42d25bf6789/
+		u.Path = "/Record.Modify"
+		values.Set("record_type", recordType)
+		values.Set("ttl", strconv.FormatUint(uint64(p.ttl), 10))
+	}
 	encodedValues = values.Encode()
 	buffer = bytes.NewBufferString(encodedValues)
 
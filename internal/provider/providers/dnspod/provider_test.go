@@ -0,0 +1,159 @@
+package dnspod
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport redirects all requests (to dnsapi.cn) to the test
+// server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+func Test_Provider_Update_createIfMissing(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		createIfMissing bool
+		createBody      string
+		paths           []string
+		errWrapped      error
+		errMessage      string
+	}{
+		"record_created": {
+			createIfMissing: true,
+			createBody:      `{"status":{"code":"1","message":"Action completed successful"}}`,
+			paths:           []string{"/Record.List", "/Record.Create"},
+		},
+		"create_failed": {
+			createIfMissing: true,
+			createBody:      `{"status":{"code":"7","message":"Domain not found"}}`,
+			paths:           []string{"/Record.List", "/Record.Create"},
+			errWrapped:      errors.ErrUnsuccessful,
+			errMessage:      "creating record: unsuccessful result: code 7: Domain not found",
+		},
+		"create_disabled": {
+			paths:      []string{"/Record.List"},
+			errWrapped: errors.ErrRecordNotFound,
+			errMessage: "record not found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "sub", r.PostForm.Get("sub_domain"))
+				if r.URL.Path == "/Record.List" {
+					_, _ = io.WriteString(w, `{"records":[{"id":"1","type":"AAAA","name":"sub"}]}`)
+					return
+				}
+				assert.Equal(t, "A", r.PostForm.Get("record_type"))
+				assert.Equal(t, defaultRecordLine, r.PostForm.Get("record_line"))
+				assert.Equal(t, "1.2.3.4", r.PostForm.Get("value"))
+				_, _ = io.WriteString(w, testCase.createBody)
+			}))
+			t.Cleanup(server.Close)
+
+			client := &http.Client{Transport: rewriteTransport{
+				host: server.Listener.Addr().String(),
+				base: http.DefaultTransport,
+			}}
+			provider := &Provider{
+				domain:          "example.com",
+				owner:           "sub",
+				token:           "id,token",
+				createIfMissing: testCase.createIfMissing,
+			}
+
+			newIP, err := provider.Update(context.Background(), client, netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.Equal(t, netip.MustParseAddr("1.2.3.4"), newIP)
+			}
+			assert.Equal(t, testCase.paths, paths)
+		})
+	}
+}
+
+func Test_Provider_Update_ttl(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ttl        uint32
+		updatePath string
+		recordType string
+		formTTL    string
+	}{
+		"provider_default": {
+			updatePath: "/Record.Ddns",
+		},
+		"ttl_set": {
+			ttl:        600,
+			updatePath: "/Record.Modify",
+			recordType: "A",
+			formTTL:    "600",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				assert.NoError(t, r.ParseForm())
+				if r.URL.Path == "/Record.List" {
+					_, _ = io.WriteString(w, `{"records":[{"id":"1","type":"A","name":"sub",`+
+						`"value":"5.6.7.8","line":"默认"}]}`)
+					return
+				}
+				assert.Equal(t, "1", r.PostForm.Get("record_id"))
+				assert.Equal(t, testCase.recordType, r.PostForm.Get("record_type"))
+				assert.Equal(t, testCase.formTTL, r.PostForm.Get("ttl"))
+				_, _ = io.WriteString(w, `{"record":{"id":1,"name":"sub","value":"1.2.3.4"}}`)
+			}))
+			t.Cleanup(server.Close)
+
+			client := &http.Client{Transport: rewriteTransport{
+				host: server.Listener.Addr().String(),
+				base: http.DefaultTransport,
+			}}
+			provider := &Provider{
+				domain: "example.com",
+				owner:  "sub",
+				token:  "id,token",
+				ttl:    testCase.ttl,
+			}
+
+			newIP, err := provider.Update(context.Background(), client, netip.MustParseAddr("1.2.3.4"))
+
+			assert.NoError(t, err)
+			assert.Equal(t, netip.MustParseAddr("1.2.3.4"), newIP)
+			assert.Equal(t, []string{"/Record.List", testCase.updatePath}, paths)
+		})
+	}
+}
@@ -133,6 +133,18 @@ func (p *Provider) HTML() models.HTMLRow {
 }
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	return p.update(ctx, client, ip)
+}
+
+// UpdateAuto updates the record without specifying an IP address,
+// letting the provider detect it from the source address of the request.
+func (p *Provider) UpdateAuto(ctx context.Context, client *http.Client) (newIP netip.Addr, err error) {
+	return p.update(ctx, client, netip.Addr{})
+}
+
+// update updates the record with the IP address given, or with the IP
+// address detected by the provider if the IP address given is invalid.
+func (p *Provider) update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   "www.duckdns.org",
@@ -142,9 +154,10 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	values.Set("verbose", "true")
 	values.Set("domains", p.BuildDomainName())
 	values.Set("token", p.token)
-	if ip.Is6() {
+	switch {
+	case ip.Is6():
 		values.Set("ipv6", ip.String())
-	} else {
+	case ip.Is4():
 		values.Set("ip", ip.String())
 	}
 	u.RawQuery = values.Encode()
@@ -179,16 +192,19 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrAuth)
 	case s[0:minChars] == "ok":
 		var ips []netip.Addr
-		if ip.Is6() {
+		switch {
+		case ip.Is6():
 			ips = ipextract.IPv6(s)
-		} else {
+		case ip.Is4():
 			ips = ipextract.IPv4(s)
+		default: // IP address detected by the provider
+			ips = append(ipextract.IPv4(s), ipextract.IPv6(s)...)
 		}
 		if len(ips) == 0 {
 			return netip.Addr{}, fmt.Errorf("%w", errors.ErrReceivedNoIP)
 		}
 		newIP = ips[0]
-		if newIP.Compare(ip) != 0 {
+		if ip.IsValid() && newIP.Compare(ip) != 0 {
 			return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
 				errors.ErrIPReceivedMismatch, ip, newIP)
 		}
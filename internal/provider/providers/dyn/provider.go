@@ -14,6 +14,7 @@ import (
 	"github.com/qdm12/ddns-updater/internal/provider/errors"
 	"github.com/qdm12/ddns-updater/internal/provider/headers"
 	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/ipextract"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
@@ -115,6 +116,63 @@ func (p *Provider) HTML() models.HTMLRow {
 // Update updates the IP address for the provider.
 // See https://help.dyn.com/remote-access-api/perform-update/
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	return p.update(ctx, client, ip)
+}
+
+// UpdateAuto updates the record without specifying an IP address,
+// letting the provider detect it from the source address of the request.
+func (p *Provider) UpdateAuto(ctx context.Context, client *http.Client) (newIP netip.Addr, err error) {
+	return p.update(ctx, client, netip.Addr{})
+}
+
+// BatchKey returns the username of the record, since records
+// of the same username can be updated in a single request.
+func (p *Provider) BatchKey() string {
+	return p.username
+}
+
+// UpdateBatch updates the records with the hostnames given to the
+// IP address given in a single request.
+func (p *Provider) UpdateBatch(ctx context.Context, client *http.Client,
+	hostnames []string, ip netip.Addr,
+) (errs []error, err error) {
+	s, statusCode, err := p.request(ctx, client, strings.Join(hostnames, ","), ip)
+	if err != nil {
+		return nil, err
+	}
+
+	// A line is received for each hostname, unless the whole request failed.
+	lines := strings.Split(s, "\n")
+	if len(lines) != len(hostnames) {
+		_, err = parseResponse(s, statusCode, ip)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %d lines for %d hostnames",
+			errors.ErrResultsCountReceived, len(lines), len(hostnames))
+	}
+	errs = make([]error, len(hostnames))
+	for i, line := range lines {
+		_, errs[i] = parseResponse(strings.TrimSpace(line), statusCode, ip)
+	}
+	return errs, nil
+}
+
+// update updates the record with the IP address given, or with the IP
+// address detected by the provider if the IP address given is invalid.
+func (p *Provider) update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	s, statusCode, err := p.request(ctx, client, utils.BuildURLQueryHostname(p.owner, p.domain), ip)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return parseResponse(s, statusCode, ip)
+}
+
+// request sends an update request for the comma separated hostnames
+// given, and returns the cleaned response body and status code.
+func (p *Provider) request(ctx context.Context, client *http.Client,
+	hostnames string, ip netip.Addr,
+) (s string, statusCode int, err error) {
 	u := url.URL{
 		Scheme: "https",
 		User:   url.UserPassword(p.username, p.clientKey),
@@ -122,30 +180,36 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		Path:   "/v3/update",
 	}
 	values := url.Values{}
-	values.Set("hostname", utils.BuildURLQueryHostname(p.owner, p.domain))
-	values.Set("myip", ip.String())
+	values.Set("hostname", hostnames)
+	if ip.IsValid() {
+		values.Set("myip", ip.String())
+	}
 	u.RawQuery = values.Encode()
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+		return "", 0, fmt.Errorf("creating http request: %w", err)
 	}
 	headers.SetUserAgent(request)
 
 	response, err := client.Do(request)
 	if err != nil {
-		return netip.Addr{}, err
+		return "", 0, err
 	}
 	defer response.Body.Close()
 
-	s, err := utils.ReadAndCleanBody(response.Body)
+	s, err = utils.ReadAndCleanBody(response.Body)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("reading response: %w", err)
+		return "", 0, fmt.Errorf("reading response: %w", err)
 	}
+	return s, response.StatusCode, nil
+}
 
-	if response.StatusCode != http.StatusOK {
+// parseResponse parses the response s for a single hostname.
+func parseResponse(s string, statusCode int, ip netip.Addr) (newIP netip.Addr, err error) {
+	if statusCode != http.StatusOK {
 		return netip.Addr{}, fmt.Errorf("%w: %d: %s",
-			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.ToSingleLine(s))
+			errors.ErrHTTPStatusNotValid, statusCode, utils.ToSingleLine(s))
 	}
 
 	switch {
@@ -153,8 +217,14 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrHostnameNotExists)
 	case strings.HasPrefix(s, "badrequest"):
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrBadRequest)
-	case strings.HasPrefix(s, "good"):
+	case strings.HasPrefix(s, "good") && ip.IsValid():
 		return ip, nil
+	case strings.HasPrefix(s, "good"): // IP address detected by the provider
+		ips := append(ipextract.IPv4(s), ipextract.IPv6(s)...)
+		if len(ips) == 0 {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrReceivedNoIP)
+		}
+		return ips[0], nil
 	default:
 		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, s)
 	}
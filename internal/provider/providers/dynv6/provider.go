@@ -110,36 +110,84 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	} else {
 		host = "ipv6." + host
 	}
+	values := url.Values{}
+	if isIPv4 {
+		values.Set("ipv4", ip.String())
+	} else {
+		values.Set("ipv6", ip.String())
+	}
+	err = p.update(ctx, client, host, values)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return ip, nil
+}
+
+// UpdateDualStack updates both the A and AAAA records in a single API call.
+func (p *Provider) UpdateDualStack(ctx context.Context, client *http.Client,
+	ipv4, ipv6 netip.Addr,
+) (newIPv4, newIPv6 netip.Addr, err error) {
+	values := url.Values{}
+	values.Set("ipv4", ipv4.String())
+	values.Set("ipv6", ipv6.String())
+	err = p.update(ctx, client, "dynv6.com", values)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, err
+	}
+	return ipv4, ipv6, nil
+}
+
+// UpdateAuto updates the record with the source address of the update
+// request. DynV6 does not report the IP address it detected, so the
+// invalid zero address is returned.
+func (p *Provider) UpdateAuto(ctx context.Context, client *http.Client) (newIP netip.Addr, err error) {
+	values := url.Values{}
+	host := "dynv6.com"
+	switch p.ipVersion {
+	case ipversion.IP4:
+		host = "ipv4." + host
+		values.Set("ipv4", "auto")
+	case ipversion.IP6:
+		host = "ipv6." + host
+		values.Set("ipv6", "auto")
+	default:
+		values.Set("ipv4", "auto")
+		values.Set("ipv6", "auto")
+	}
+	err = p.update(ctx, client, host, values)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return netip.Addr{}, nil
+}
+
+func (p *Provider) update(ctx context.Context, client *http.Client,
+	host string, values url.Values,
+) (err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   host,
 		Path:   "/api/update",
 	}
-	values := url.Values{}
 	values.Set("token", p.token)
 	values.Set("zone", utils.BuildURLQueryHostname(p.owner, p.domain))
-	if isIPv4 {
-		values.Set("ipv4", ip.String())
-	} else {
-		values.Set("ipv6", ip.String())
-	}
 	u.RawQuery = values.Encode()
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+		return fmt.Errorf("creating http request: %w", err)
 	}
 	headers.SetUserAgent(request)
 
 	response, err := client.Do(request)
 	if err != nil {
-		return netip.Addr{}, err
+		return err
 	}
 	defer response.Body.Close()
 
 	if response.StatusCode == http.StatusOK {
-		return ip, nil
+		return nil
 	}
-	return netip.Addr{}, fmt.Errorf("%w: %d: %s",
+	return fmt.Errorf("%w: %d: %s",
 		errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
 }
@@ -0,0 +1,112 @@
+package dynv6
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport redirects all requests (to dynv6.com) to the test
+// server, without having to make the provider code test-aware, and
+// records the host requested.
+type rewriteTransport struct {
+	host          string
+	base          http.RoundTripper
+	requestedHost *string
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	*t.requestedHost = request.URL.Host
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+func Test_Provider_UpdateAuto(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ipVersion  ipversion.IPVersion
+		statusCode int
+		host       string
+		query      url.Values
+		errWrapped error
+		errMessage string
+	}{
+		"ipv4": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			host:       "ipv4.dynv6.com",
+			query:      url.Values{"ipv4": {"auto"}, "token": {"token"}, "zone": {"example.com"}},
+		},
+		"ipv6": {
+			ipVersion:  ipversion.IP6,
+			statusCode: http.StatusOK,
+			host:       "ipv6.dynv6.com",
+			query:      url.Values{"ipv6": {"auto"}, "token": {"token"}, "zone": {"example.com"}},
+		},
+		"ipv4_or_ipv6": {
+			ipVersion:  ipversion.IP4or6,
+			statusCode: http.StatusOK,
+			host:       "dynv6.com",
+			query: url.Values{
+				"ipv4": {"auto"}, "ipv6": {"auto"},
+				"token": {"token"}, "zone": {"example.com"},
+			},
+		},
+		"bad_status": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusUnauthorized,
+			host:       "ipv4.dynv6.com",
+			query:      url.Values{"ipv4": {"auto"}, "token": {"token"}, "zone": {"example.com"}},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "HTTP status is not valid: 401: invalid authentication token",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/update", r.URL.Path)
+				assert.Equal(t, testCase.query, r.URL.Query())
+				w.WriteHeader(testCase.statusCode)
+				if testCase.statusCode != http.StatusOK {
+					_, _ = w.Write([]byte("invalid authentication token"))
+					return
+				}
+				_, _ = w.Write([]byte("addresses updated"))
+			}))
+			t.Cleanup(server.Close)
+
+			var requestedHost string
+			client := &http.Client{Transport: rewriteTransport{
+				host:          server.Listener.Addr().String(),
+				base:          http.DefaultTransport,
+				requestedHost: &requestedHost,
+			}}
+			provider := &Provider{
+				domain:    "example.com",
+				owner:     "@",
+				ipVersion: testCase.ipVersion,
+				token:     "token",
+			}
+
+			newIP, err := provider.UpdateAuto(context.Background(), client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.False(t, newIP.IsValid())
+			assert.Equal(t, testCase.host, requestedHost)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package exec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// request is the JSON object written to the program standard input.
+type request struct {
+	Domain    string          `json:"domain"`
+	Owner     string          `json:"owner"`
+	FQDN      string          `json:"fqdn"`
+	IP        string          `json:"ip"`
+	IPVersion string          `json:"ip_version"`
+	Settings  json.RawMessage `json:"settings,omitempty"`
+}
+
+// response is the JSON object read from the program standard output.
+type response struct {
+	IP    string         `json:"ip"`
+	Error *responseError `json:"error"`
+}
+
+type responseError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// errorTypes maps the error types the program can return
+// to the provider sentinel errors.
+var errorTypes = map[string]error{ //nolint:gochecknoglobals
+	"auth":             errors.ErrAuth,
+	"bad_request":      errors.ErrBadRequest,
+	"banned":           errors.ErrBannedAbuse,
+	"conflict":         errors.ErrConflictingRecord,
+	"domain_not_found": errors.ErrDomainNotFound,
+	"ip_mismatch":      errors.ErrIPReceivedMismatch,
+	"rate_limit":       errors.ErrRateLimit,
+	"record_not_found": errors.ErrRecordNotFound,
+	"server":           errors.ErrDNSServerSide,
+	"zone_not_found":   errors.ErrZoneNotFound,
+}
+
+func (e responseError) toError() error {
+	sentinel, ok := errorTypes[e.Type]
+	if !ok {
+		sentinel = errors.ErrUnsuccessful
+	}
+	if e.Message == "" {
+		return fmt.Errorf("%w", sentinel)
+	}
+	return fmt.Errorf("%w: %s", sentinel, e.Message)
+}
@@ -0,0 +1,177 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain     string
+	owner      string
+	ipVersion  ipversion.IPVersion
+	ipv6Suffix netip.Prefix
+	command    []string
+	settings   json.RawMessage
+	timeout    time.Duration
+}
+
+func New(data json.RawMessage, domain, owner string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error,
+) {
+	extraSettings := struct {
+		Command  []string        `json:"command"`
+		Settings json.RawMessage `json:"settings"`
+		Timeout  string          `json:"timeout"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, fmt.Errorf("JSON decoding provider specific settings: %w", err)
+	}
+
+	timeout := time.Minute
+	if extraSettings.Timeout != "" {
+		timeout, err = time.ParseDuration(extraSettings.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("parsing timeout: %w", err)
+		}
+	}
+
+	err = validateSettings(domain, extraSettings.Command, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("validating provider specific settings: %w", err)
+	}
+
+	return &Provider{
+		domain:     domain,
+		owner:      owner,
+		ipVersion:  ipVersion,
+		ipv6Suffix: ipv6Suffix,
+		command:    extraSettings.Command,
+		settings:   extraSettings.Settings,
+		timeout:    timeout,
+	}, nil
+}
+
+func validateSettings(domain string, command []string, timeout time.Duration) (err error) {
+	err = utils.CheckDomain(domain)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errors.ErrDomainNotValid, err)
+	}
+
+	switch {
+	case len(command) == 0 || command[0] == "":
+		return fmt.Errorf("%w", errors.ErrCommandNotSet)
+	case timeout <= 0:
+		return fmt.Errorf("%w: %s", errors.ErrTimeoutNotValid, timeout)
+	default:
+		return nil
+	}
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.owner, constants.Exec, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Owner() string {
+	return p.owner
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.owner, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Owner:     p.Owner(),
+		Provider:  fmt.Sprintf("%s: %s", constants.Exec, filepath.Base(p.command[0])),
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+// Update runs the program with the update request JSON encoded on its
+// standard input, and decodes its response from its standard output.
+// The HTTP client is not used.
+func (p *Provider) Update(ctx context.Context, _ *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	request := request{
+		Domain:    p.domain,
+		Owner:     p.owner,
+		FQDN:      p.BuildDomainName(),
+		IP:        ip.String(),
+		IPVersion: ipversion.IP4.String(),
+		Settings:  p.settings,
+	}
+	if ip.Is6() {
+		request.IPVersion = ipversion.IP6.String()
+	}
+	input, err := json.Marshal(request)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("JSON encoding request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	cmd := osexec.CommandContext(ctx, p.command[0], p.command[1:]...) //nolint:gosec
+	cmd.Stdin = bytes.NewReader(input)
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	runErr := cmd.Run()
+
+	var response response
+	decodeErr := json.Unmarshal(stdout.Bytes(), &response)
+	switch {
+	case decodeErr == nil && response.Error != nil:
+		return netip.Addr{}, response.Error.toError()
+	case runErr != nil:
+		return netip.Addr{}, fmt.Errorf("running %s: %w: %s", p.command[0], runErr,
+			utils.ToSingleLine(strings.TrimSpace(stderr.String())))
+	case decodeErr != nil:
+		return netip.Addr{}, fmt.Errorf("%w: JSON decoding output: %w: %s",
+			errors.ErrUnknownResponse, decodeErr, utils.ToSingleLine(stdout.String()))
+	}
+
+	if response.IP == "" {
+		return ip, nil
+	}
+	newIP, err = netip.ParseAddr(response.IP)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+	} else if newIP.Compare(ip) != 0 {
+		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+			errors.ErrIPReceivedMismatch, ip, newIP)
+	}
+	return newIP, nil
+}
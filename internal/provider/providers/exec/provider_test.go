@@ -0,0 +1,101 @@
+package exec
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_helperPlugin is not a real test, but acts as the plugin
+// program when the test binary is run by the provider.
+func Test_helperPlugin(t *testing.T) { //nolint:paralleltest
+	mode := os.Getenv("DDNS_EXEC_PLUGIN_MODE")
+	if mode == "" {
+		return
+	}
+
+	var request request
+	err := json.NewDecoder(bufio.NewReader(os.Stdin)).Decode(&request)
+	if err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	switch mode {
+	case "success":
+		fmt.Printf(`{"ip": %q}`, request.IP)
+	case "settings":
+		message, _ := json.Marshal("token " + string(request.Settings) + " for " + request.FQDN)
+		fmt.Printf(`{"error": {"type": "auth", "message": %s}}`, message)
+	case "rate_limit":
+		fmt.Print(`{"error": {"type": "rate_limit"}}`)
+	case "crash":
+		fmt.Fprint(os.Stderr, "something bad happened")
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Setenv("DDNS_EXEC_PLUGIN_MODE", "")
+
+	testCases := map[string]struct {
+		mode       string
+		newIP      netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"success": {
+			mode:  "success",
+			newIP: netip.MustParseAddr("1.2.3.4"),
+		},
+		"settings_passed": {
+			mode:       "settings",
+			errWrapped: errors.ErrAuth,
+			errMessage: `bad authentication: token {"token":"x"} for sub.example.com`,
+		},
+		"typed_error": {
+			mode:       "rate_limit",
+			errWrapped: errors.ErrRateLimit,
+			errMessage: "rate limit exceeded",
+		},
+		"crash": {
+			mode:       "crash",
+			errMessage: "running " + os.Args[0] + ": exit status 1: something bad happened",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("DDNS_EXEC_PLUGIN_MODE", testCase.mode)
+
+			command, err := json.Marshal([]string{os.Args[0], "-test.run=^Test_helperPlugin$"})
+			require.NoError(t, err)
+			data := json.RawMessage(`{"command": ` + string(command) +
+				`, "settings": {"token": "x"}}`)
+			provider, err := New(data, "example.com", "sub", ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			newIP, err := provider.Update(context.Background(), nil, netip.MustParseAddr("1.2.3.4"))
+
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+			}
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
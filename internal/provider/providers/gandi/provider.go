@@ -33,13 +33,12 @@ type Provider struct {
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
 		PersonalAccessToken string `json:"personal_access_token"`
 		APIKey              string `json:"key"`
-		TTL                 uint32 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -58,7 +57,7 @@ func New(data json.RawMessage, domain, owner string,
 		ipv6Suffix:          ipv6Suffix,
 		personalAccessToken: extraSettings.PersonalAccessToken,
 		apiKey:              extraSettings.APIKey,
-		ttl:                 extraSettings.TTL,
+		ttl:                 ttl,
 	}, nil
 }
 
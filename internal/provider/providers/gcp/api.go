@@ -67,14 +67,15 @@ func (p *Provider) getRRSet(ctx context.Context, client *http.Client, fqdn, reco
 }
 
 func (p *Provider) createRRSet(ctx context.Context, client *http.Client, fqdn, recordType string,
-	ip netip.Addr,
+	rrdatas []string,
 ) (err error) {
 	urlPath := fmt.Sprintf("/dns/v1/projects/%s/managedZones/%s/rrsets", p.project, p.zone)
 	body := bytes.NewBuffer(nil)
 	encoder := json.NewEncoder(body)
 	rrSet := &recordResourceSet{
 		Name:    fqdn,
-		Rrdatas: []string{ip.String()},
+		Rrdatas: rrdatas,
+		TTL:     p.ttl,
 		Type:    recordType,
 	}
 	err = encoder.Encode(rrSet)
@@ -102,7 +103,7 @@ func (p *Provider) createRRSet(ctx context.Context, client *http.Client, fqdn, r
 }
 
 func (p *Provider) patchRRSet(ctx context.Context, client *http.Client, fqdn, recordType string,
-	ip netip.Addr,
+	rrdatas []string,
 ) (err error) {
 	urlPath := fmt.Sprintf("/dns/v1/projects/%s/managedZones/%s/rrsets/%s/%s",
 		p.project, p.zone, fqdn, recordType)
@@ -110,7 +111,8 @@ func (p *Provider) patchRRSet(ctx context.Context, client *http.Client, fqdn, re
 	encoder := json.NewEncoder(body)
 	rrSet := &recordResourceSet{
 		Name:    fqdn,
-		Rrdatas: []string{ip.String()},
+		Rrdatas: rrdatas,
+		TTL:     p.ttl,
 		Type:    recordType,
 	}
 	err = encoder.Encode(rrSet)
@@ -136,6 +138,14 @@ func (p *Provider) patchRRSet(ctx context.Context, client *http.Client, fqdn, re
 	return fmt.Errorf("%w: %s", errors.ErrHTTPStatusNotValid, errMessage)
 }
 
+func ipsToRrdatas(ips []netip.Addr) (rrdatas []string) {
+	rrdatas = make([]string, len(ips))
+	for i, ip := range ips {
+		rrdatas[i] = ip.String()
+	}
+	return rrdatas
+}
+
 func makeAPIURL(path string) string {
 	urlValues := make(url.Values)
 	urlValues.Set("alt", "json")
@@ -148,3 +158,118 @@ func makeAPIURL(path string) string {
 	}
 	return u.String()
 }
+
+func (p *Provider) deleteRRSet(ctx context.Context, client *http.Client,
+	fqdn, recordType string,
+) (err error) {
+	urlPath := fmt.Sprintf("/dns/v1/projects/%s/managedZones/%s/rrsets/%s/%s",
+		p.project, p.zone, fqdn, recordType)
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, makeAPIURL(urlPath), nil)
+	if err != nil {
+		return err
+	}
+	headers.SetUserAgent(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return response.Body.Close()
+	case response.StatusCode >= http.StatusOK &&
+		response.StatusCode < http.StatusMultipleChoices:
+		return response.Body.Close()
+	default:
+		errMessage := decodeError(response.Body)
+		return fmt.Errorf("%w: %s", errors.ErrHTTPStatusNotValid, errMessage)
+	}
+}
+
+// listRRSets lists all the record resource sets of the managed zone.
+func (p *Provider) listRRSets(ctx context.Context, client *http.Client) (
+	rrSets []recordResourceSet, err error,
+) {
+	urlPath := fmt.Sprintf("/dns/v1/projects/%s/managedZones/%s/rrsets", p.project, p.zone)
+	pageToken := ""
+	for {
+		apiURL := makeAPIURL(urlPath)
+		if pageToken != "" {
+			apiURL += "&pageToken=" + url.QueryEscape(pageToken)
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		headers.SetUserAgent(request)
+
+		response, err := client.Do(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode < http.StatusOK ||
+			response.StatusCode >= http.StatusMultipleChoices {
+			errMessage := decodeError(response.Body)
+			return nil, fmt.Errorf("%w: %s", errors.ErrHTTPStatusNotValid, errMessage)
+		}
+
+		var data struct {
+			RRSets        []recordResourceSet `json:"rrsets"`
+			NextPageToken string              `json:"nextPageToken"`
+		}
+		decoder := json.NewDecoder(response.Body)
+		err = decoder.Decode(&data)
+		if err != nil {
+			_ = response.Body.Close()
+			return nil, fmt.Errorf("json decoding rrsets: %w", err)
+		}
+		err = response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("closing response body: %w", err)
+		}
+
+		rrSets = append(rrSets, data.RRSets...)
+		if data.NextPageToken == "" {
+			return rrSets, nil
+		}
+		pageToken = data.NextPageToken
+	}
+}
+
+type change struct {
+	Additions []recordResourceSet `json:"additions,omitempty"`
+	Deletions []recordResourceSet `json:"deletions,omitempty"`
+}
+
+// createChange applies the change given to the managed zone atomically.
+func (p *Provider) createChange(ctx context.Context, client *http.Client,
+	zoneChange change,
+) (err error) {
+	urlPath := fmt.Sprintf("/dns/v1/projects/%s/managedZones/%s/changes", p.project, p.zone)
+	body := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(body)
+	err = encoder.Encode(zoneChange)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, makeAPIURL(urlPath), body)
+	if err != nil {
+		return err
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= http.StatusOK &&
+		response.StatusCode < http.StatusMultipleChoices {
+		return response.Body.Close()
+	}
+	errMessage := decodeError(response.Body)
+	return fmt.Errorf("%w: %s", errors.ErrHTTPStatusNotValid, errMessage)
+}
@@ -0,0 +1,89 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	ddnserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+)
+
+// BatchKey returns the project, managed zone, TTL and create if missing
+// settings of the record, since record resource sets of the same managed
+// zone can be updated in a single change, which uses the settings of the
+// first record.
+func (p *Provider) BatchKey() string {
+	return p.project + "/" + p.zone + "/" + strconv.FormatUint(uint64(p.ttl), 10) +
+		"/" + strconv.FormatBool(p.createIfMissing)
+}
+
+// UpdateBatch updates the record resource sets with the hostnames given
+// to the IP address given in a single change, which Google Cloud DNS
+// applies atomically. Missing record resource sets are created in the
+// same change if createIfMissing is set.
+// See https://cloud.google.com/dns/docs/reference/rest/v1/changes/create
+func (p *Provider) UpdateBatch(ctx context.Context, client *http.Client,
+	hostnames []string, ip netip.Addr,
+) (errs []error, err error) {
+	client, err = createOauth2Client(ctx, client, p.credentials, p.credType)
+	if err != nil {
+		return nil, fmt.Errorf("creating OAuth2 client: %w", err)
+	}
+	return p.updateBatch(ctx, client, hostnames, ip)
+}
+
+func (p *Provider) updateBatch(ctx context.Context, client *http.Client,
+	hostnames []string, ip netip.Addr,
+) (errs []error, err error) {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+
+	rrSets, err := p.listRRSets(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("listing record resource sets: %w", err)
+	}
+	fqdnToRRSet := make(map[string]recordResourceSet, len(rrSets))
+	for _, rrSet := range rrSets {
+		if rrSet.Type == recordType {
+			fqdnToRRSet[strings.ToLower(rrSet.Name)] = rrSet
+		}
+	}
+
+	errs = make([]error, len(hostnames))
+	var zoneChange change
+	for i, hostname := range hostnames {
+		fqdn := strings.ToLower(hostname) + "."
+		rrSet, found := fqdnToRRSet[fqdn]
+		switch {
+		case !found && !p.createIfMissing:
+			errs[i] = fmt.Errorf("%w: %s", ddnserrors.ErrRecordResourceSetNotFound, fqdn)
+			continue
+		case found && slices.Contains(rrSet.Rrdatas, ip.String()):
+			continue // already up to date
+		case found:
+			zoneChange.Deletions = append(zoneChange.Deletions, rrSet)
+		}
+		zoneChange.Additions = append(zoneChange.Additions, recordResourceSet{
+			Name:    fqdn,
+			Rrdatas: []string{ip.String()},
+			TTL:     p.ttl,
+			Type:    recordType,
+		})
+	}
+
+	if len(zoneChange.Additions) == 0 {
+		return errs, nil
+	}
+	err = p.createChange(ctx, client, zoneChange)
+	if err != nil {
+		return nil, fmt.Errorf("creating change: %w", err)
+	}
+	return errs, nil
+}
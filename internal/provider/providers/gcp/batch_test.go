@@ -0,0 +1,159 @@
+package gcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rewriteTransport redirects all requests (to dns.googleapis.com) to the
+// test server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+// newTestClient returns an HTTP client sending all its requests to
+// a test server running the handler given.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &http.Client{Transport: rewriteTransport{
+		host: strings.TrimPrefix(server.URL, "http://"),
+		base: http.DefaultTransport,
+	}}
+}
+
+func Test_Provider_updateBatch(t *testing.T) {
+	t.Parallel()
+
+	const rrSetsPath = "/dns/v1/projects/project/managedZones/zone/rrsets"
+	const changesPath = "/dns/v1/projects/project/managedZones/zone/changes"
+
+	testCases := map[string]struct {
+		createIfMissing bool
+		hostnames       []string
+		changeStatus    int
+		wantChange      string
+		errs            []error
+		errWrapped      error
+		errMessage      string
+	}{
+		"all_up_to_date": {
+			hostnames: []string{"a.example.com"},
+			errs:      []error{nil},
+		},
+		"update_and_skip_missing": {
+			hostnames:    []string{"a.example.com", "B.example.com", "c.example.com"},
+			changeStatus: http.StatusOK,
+			wantChange: `{"additions":[{"name":"b.example.com.","rrdatas":["1.2.3.4"],"ttl":300,"type":"A"}],` +
+				`"deletions":[{"name":"b.example.com.","rrdatas":["5.6.7.8"],"ttl":60,"type":"A"}]}` + "\n",
+			errs: []error{nil, nil, errors.ErrRecordResourceSetNotFound},
+		},
+		"create_missing": {
+			createIfMissing: true,
+			hostnames:       []string{"c.example.com"},
+			changeStatus:    http.StatusOK,
+			wantChange: `{"additions":[{"name":"c.example.com.","rrdatas":["1.2.3.4"],"ttl":300,"type":"A"}]}` +
+				"\n",
+			errs: []error{nil},
+		},
+		"change_failed": {
+			hostnames:    []string{"b.example.com"},
+			changeStatus: http.StatusBadRequest,
+			wantChange: `{"additions":[{"name":"b.example.com.","rrdatas":["1.2.3.4"],"ttl":300,"type":"A"}],` +
+				`"deletions":[{"name":"b.example.com.","rrdatas":["5.6.7.8"],"ttl":60,"type":"A"}]}` + "\n",
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "creating change: HTTP status is not valid: status 400; bad request",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotChange string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case rrSetsPath:
+					_, _ = io.WriteString(w, `{"rrsets":[`+
+						`{"name":"a.example.com.","rrdatas":["1.2.3.4"],"ttl":60,"type":"A"},`+
+						`{"name":"b.example.com.","rrdatas":["5.6.7.8"],"ttl":60,"type":"A"},`+
+						`{"name":"c.example.com.","rrdatas":["::1"],"ttl":60,"type":"AAAA"}]}`)
+				case changesPath:
+					body, err := io.ReadAll(r.Body)
+					assert.NoError(t, err)
+					gotChange = string(body)
+					w.WriteHeader(testCase.changeStatus)
+					_, _ = io.WriteString(w, `{"error":{"code":400,"message":"bad request"}}`)
+				default:
+					t.Errorf("unexpected request path %s", r.URL.Path)
+				}
+			})
+
+			provider := &Provider{
+				project:         "project",
+				zone:            "zone",
+				ttl:             300,
+				createIfMissing: testCase.createIfMissing,
+			}
+
+			errs, err := provider.updateBatch(context.Background(), client,
+				testCase.hostnames, netip.MustParseAddr("1.2.3.4"))
+
+			assert.Equal(t, testCase.wantChange, gotChange)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.Len(t, errs, len(testCase.errs))
+			for i, wantErr := range testCase.errs {
+				if wantErr == nil {
+					assert.NoError(t, errs[i])
+				} else {
+					assert.ErrorIs(t, errs[i], wantErr)
+				}
+			}
+		})
+	}
+}
+
+func Test_Provider_listRRSets(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			_, _ = io.WriteString(w, `{"rrsets":[{"name":"a.example.com.","type":"A"}],`+
+				`"nextPageToken":"next"}`)
+		case "next":
+			_, _ = io.WriteString(w, `{"rrsets":[{"name":"b.example.com.","type":"A"}]}`)
+		}
+	})
+	provider := &Provider{project: "project", zone: "zone"}
+
+	rrSets, err := provider.listRRSets(context.Background(), client)
+
+	require.NoError(t, err)
+	expected := []recordResourceSet{
+		{Name: "a.example.com.", Type: "A"},
+		{Name: "b.example.com.", Type: "A"},
+	}
+	assert.Equal(t, expected, rrSets)
+}
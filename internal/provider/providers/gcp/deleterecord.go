@@ -0,0 +1,22 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DeleteRecord deletes the record resource set of the given type.
+func (p *Provider) DeleteRecord(ctx context.Context, client *http.Client, recordType string) (err error) {
+	client, err = createOauth2Client(ctx, client, p.credentials, p.credType)
+	if err != nil {
+		return fmt.Errorf("creating OAuth2 client: %w", err)
+	}
+
+	fqdn := fmt.Sprintf("%s.%s.", p.owner, p.domain)
+	err = p.deleteRRSet(ctx, client, fqdn, recordType)
+	if err != nil {
+		return fmt.Errorf("deleting %s record resource set: %w", recordType, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package gcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_deleteRRSet(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		statusCode int
+		errWrapped error
+		errMessage string
+	}{
+		"deleted": {
+			statusCode: http.StatusOK,
+		},
+		"already_deleted": {
+			statusCode: http.StatusNotFound,
+		},
+		"bad_status": {
+			statusCode: http.StatusForbidden,
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "HTTP status is not valid: status 403; forbidden",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var request string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				request = r.Method + " " + r.URL.Path
+				w.WriteHeader(testCase.statusCode)
+				_, _ = io.WriteString(w, `{"error":{"code":403,"message":"forbidden"}}`)
+			})
+			provider := &Provider{project: "project", zone: "zone"}
+
+			err := provider.deleteRRSet(context.Background(), client, "sub.example.com.", "AAAA")
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, "DELETE /dns/v1/projects/project/managedZones/zone/rrsets/sub.example.com./AAAA",
+				request)
+		})
+	}
+}
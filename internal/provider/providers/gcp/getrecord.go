@@ -0,0 +1,46 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	ddnserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// GetRecord returns the IP addresses currently set for the record.
+func (p *Provider) GetRecord(ctx context.Context, client *http.Client) (ips []netip.Addr, err error) {
+	client, err = createOauth2Client(ctx, client, p.credentials, p.credType)
+	if err != nil {
+		return nil, fmt.Errorf("creating OAuth2 client: %w", err)
+	}
+	return p.getRecord(ctx, client)
+}
+
+func (p *Provider) getRecord(ctx context.Context, client *http.Client) (ips []netip.Addr, err error) {
+	fqdn := fmt.Sprintf("%s.%s.", p.owner, p.domain)
+
+	for _, recordType := range utils.RecordTypes(p.ipVersion) {
+		recordResourceSet, err := p.getRRSet(ctx, client, fqdn, recordType)
+		switch {
+		case errors.Is(err, ddnserrors.ErrRecordResourceSetNotFound):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("getting %s record resource set: %w", recordType, err)
+		case recordResourceSet == nil:
+			continue
+		}
+
+		for _, rrData := range recordResourceSet.Rrdatas {
+			ip, err := netip.ParseAddr(rrData)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ddnserrors.ErrIPReceivedMalformed, err)
+			}
+			ips = append(ips, ip)
+		}
+	}
+	return ips, nil
+}
@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_getRecord(t *testing.T) {
+	t.Parallel()
+
+	const rrSetsPath = "/dns/v1/projects/project/managedZones/zone/rrsets/sub.example.com./"
+
+	testCases := map[string]struct {
+		ipVersion  ipversion.IPVersion
+		statuses   map[string]int
+		bodies     map[string]string
+		ips        []netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"dual_stack": {
+			ipVersion: ipversion.IP4or6,
+			statuses:  map[string]int{"A": http.StatusOK, "AAAA": http.StatusOK},
+			bodies: map[string]string{
+				"A":    `{"rrdatas":["1.2.3.4","5.6.7.8"]}`,
+				"AAAA": `{"rrdatas":["::1"]}`,
+			},
+			ips: []netip.Addr{
+				netip.MustParseAddr("1.2.3.4"),
+				netip.MustParseAddr("5.6.7.8"),
+				netip.MustParseAddr("::1"),
+			},
+		},
+		"missing_record_types": {
+			ipVersion: ipversion.IP4or6,
+			statuses:  map[string]int{"A": http.StatusNotFound, "AAAA": http.StatusNoContent},
+			bodies:    map[string]string{"A": `{"error":{"code":404,"message":"not found"}}`},
+		},
+		"malformed_ip": {
+			ipVersion:  ipversion.IP4,
+			statuses:   map[string]int{"A": http.StatusOK},
+			bodies:     map[string]string{"A": `{"rrdatas":["x"]}`},
+			errWrapped: errors.ErrIPReceivedMalformed,
+			errMessage: `malformed IP address received: ParseAddr("x"): unable to parse IP`,
+		},
+		"bad_status": {
+			ipVersion:  ipversion.IP4,
+			statuses:   map[string]int{"A": http.StatusForbidden},
+			bodies:     map[string]string{"A": `{"error":{"code":403,"message":"forbidden"}}`},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "getting A record resource set: HTTP status is not valid: status 403; forbidden",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				recordType := r.URL.Path[len(rrSetsPath):]
+				w.WriteHeader(testCase.statuses[recordType])
+				_, _ = io.WriteString(w, testCase.bodies[recordType])
+			})
+			provider := &Provider{
+				domain:    "example.com",
+				owner:     "sub",
+				ipVersion: testCase.ipVersion,
+				project:   "project",
+				zone:      "zone",
+			}
+
+			ips, err := provider.getRecord(context.Background(), client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ips, ips)
+		})
+	}
+}
@@ -16,18 +16,20 @@ import (
 )
 
 type Provider struct {
-	domain      string
-	owner       string
-	ipVersion   ipversion.IPVersion
-	ipv6Suffix  netip.Prefix
-	project     string
-	zone        string
-	credentials json.RawMessage
-	credType    google.CredentialsType
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	project         string
+	zone            string
+	credentials     json.RawMessage
+	credType        google.CredentialsType
+	createIfMissing bool
+	ttl             uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	var extraSettings struct {
@@ -47,14 +49,16 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:      domain,
-		owner:       owner,
-		ipVersion:   ipVersion,
-		ipv6Suffix:  ipv6Suffix,
-		project:     extraSettings.Project,
-		zone:        extraSettings.Zone,
-		credentials: extraSettings.Credentials,
-		credType:    credType,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		project:         extraSettings.Project,
+		zone:            extraSettings.Zone,
+		credentials:     extraSettings.Credentials,
+		credType:        credType,
+		createIfMissing: createIfMissing,
+		ttl:             ttl,
 	}, nil
 }
 
@@ -114,6 +118,12 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.owner, constants.GCP, p.ipVersion)
 }
 
+// AccountIdentity returns the service account credentials, which
+// identify the account regardless of the project and zone.
+func (p *Provider) AccountIdentity() string {
+	return string(p.credentials)
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
 	ddnserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
 )
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
@@ -36,7 +37,10 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	if !rrSetFound {
-		err = p.createRRSet(ctx, client, fqdn, recordType, ip)
+		if !p.createIfMissing {
+			return netip.Addr{}, fmt.Errorf("getting record resource set: %w", err)
+		}
+		err = p.createRRSet(ctx, client, fqdn, recordType, []string{ip.String()})
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
@@ -48,10 +52,114 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		return ip, nil
 	}
 
-	err = p.patchRRSet(ctx, client, fqdn, recordType, ip)
+	err = p.patchRRSet(ctx, client, fqdn, recordType, []string{ip.String()})
 	if err != nil {
 		return netip.Addr{}, fmt.Errorf("updating record: %w", err)
 	}
 
 	return ip, nil
 }
+
+// UpdateMulti sets the A and AAAA record sets to the IPv4 and IPv6
+// addresses given, and deletes the record set of an IP family of the
+// record IP version if no IP address of this family is given.
+func (p *Provider) UpdateMulti(ctx context.Context, client *http.Client,
+	ips []netip.Addr,
+) (newIPs []netip.Addr, err error) {
+	client, err = createOauth2Client(ctx, client, p.credentials, p.credType)
+	if err != nil {
+		return nil, fmt.Errorf("creating OAuth2 client: %w", err)
+	}
+
+	fqdn := fmt.Sprintf("%s.%s.", p.owner, p.domain)
+
+	for _, recordSet := range utils.GroupManagedRecordSets(p.ipVersion, ips) {
+		if len(recordSet.IPs) == 0 {
+			err = p.deleteRRSet(ctx, client, fqdn, recordSet.Type)
+			if err != nil {
+				return nil, fmt.Errorf("deleting %s record resource set: %w", recordSet.Type, err)
+			}
+			continue
+		}
+		err = p.setRRSet(ctx, client, fqdn, recordSet.Type, ipsToRrdatas(recordSet.IPs))
+		if err != nil {
+			return nil, fmt.Errorf("setting %s record resource set: %w", recordSet.Type, err)
+		}
+	}
+	return ips, nil
+}
+
+// setRRSet sets the record resource set of the type given to the
+// resource record data given, creating it if it does not exist and
+// if allowed.
+func (p *Provider) setRRSet(ctx context.Context, client *http.Client,
+	fqdn, recordType string, rrdatas []string,
+) (err error) {
+	recordResourceSet, err := p.getRRSet(ctx, client, fqdn, recordType)
+	switch {
+	case errors.Is(err, ddnserrors.ErrRecordResourceSetNotFound) && p.createIfMissing:
+		err = p.createRRSet(ctx, client, fqdn, recordType, rrdatas)
+		if err != nil {
+			return fmt.Errorf("creating record: %w", err)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("getting record resource set: %w", err)
+	case recordResourceSet != nil && slices.Equal(recordResourceSet.Rrdatas, rrdatas):
+		return nil // already up to date
+	}
+
+	err = p.patchRRSet(ctx, client, fqdn, recordType, rrdatas)
+	if err != nil {
+		return fmt.Errorf("updating record: %w", err)
+	}
+	return nil
+}
+
+// UpdateContent sets the resource record data of the record resource set
+// of the given type matching the content given, keeping the other
+// resource record data of the record resource set.
+func (p *Provider) UpdateContent(ctx context.Context, client *http.Client,
+	recordType, content string,
+) (err error) {
+	client, err = createOauth2Client(ctx, client, p.credentials, p.credType)
+	if err != nil {
+		return fmt.Errorf("creating OAuth2 client: %w", err)
+	}
+
+	fqdn := fmt.Sprintf("%s.%s.", p.owner, p.domain)
+
+	var rrdatas []string
+	recordResourceSet, err := p.getRRSet(ctx, client, fqdn, recordType)
+	switch {
+	case errors.Is(err, ddnserrors.ErrRecordResourceSetNotFound):
+	case err != nil:
+		return fmt.Errorf("getting record resource set: %w", err)
+	default:
+		rrdatas = slices.Clone(recordResourceSet.Rrdatas)
+	}
+
+	contents := make([]string, len(rrdatas))
+	for i, rrdata := range rrdatas {
+		contents[i] = rrdata
+		if utils.IsTXTType(recordType) {
+			contents[i] = utils.UnquoteTXT(rrdata)
+		}
+	}
+	rrdata := content
+	if utils.IsTXTType(recordType) {
+		rrdata = utils.QuoteTXT(content)
+	}
+	index, err := utils.SelectContentRecord(contents, content)
+	switch {
+	case err != nil:
+		return err
+	case index == -1:
+		rrdatas = append(rrdatas, rrdata)
+	case contents[index] == content:
+		return nil
+	default:
+		rrdatas[index] = rrdata
+	}
+	return p.setRRSet(ctx, client, fqdn, recordType, rrdatas)
+}
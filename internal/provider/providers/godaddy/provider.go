@@ -26,10 +26,11 @@ type Provider struct {
 	ipv6Suffix netip.Prefix
 	key        string
 	secret     string
+	ttl        uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -53,6 +54,7 @@ func New(data json.RawMessage, domain, owner string,
 		ipv6Suffix: ipv6Suffix,
 		key:        extraSettings.Key,
 		secret:     extraSettings.Secret,
+		ttl:        ttl,
 	}, nil
 }
 
@@ -118,6 +120,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	type goDaddyPutBody struct {
 		Data string `json:"data"` // IP address to update to
+		TTL  uint32 `json:"ttl,omitempty"`
 	}
 	u := url.URL{
 		Scheme: "https",
@@ -128,7 +131,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	buffer := bytes.NewBuffer(nil)
 	encoder := json.NewEncoder(buffer)
 	requestData := []goDaddyPutBody{
-		{Data: ip.String()},
+		{Data: ip.String(), TTL: p.ttl},
 	}
 	err = encoder.Encode(requestData)
 	if err != nil {
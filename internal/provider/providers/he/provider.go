@@ -102,6 +102,18 @@ func (p *Provider) HTML() models.HTMLRow {
 }
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	return p.update(ctx, client, ip)
+}
+
+// UpdateAuto updates the record without specifying an IP address,
+// letting the provider detect it from the source address of the request.
+func (p *Provider) UpdateAuto(ctx context.Context, client *http.Client) (newIP netip.Addr, err error) {
+	return p.update(ctx, client, netip.Addr{})
+}
+
+// update updates the record with the IP address given, or with the IP
+// address detected by the provider if the IP address given is invalid.
+func (p *Provider) update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
 	fqdn := p.BuildDomainName()
 	u := url.URL{
 		Scheme: "https",
@@ -111,7 +123,9 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 	values := url.Values{}
 	values.Set("hostname", fqdn)
-	values.Set("myip", ip.String())
+	if ip.IsValid() {
+		values.Set("myip", ip.String())
+	}
 	u.RawQuery = values.Encode()
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
@@ -143,10 +157,13 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	var ips []netip.Addr
-	if ip.Is4() {
+	switch {
+	case ip.Is4():
 		ips = ipextract.IPv4(s)
-	} else {
+	case ip.Is6():
 		ips = ipextract.IPv6(s)
+	default: // IP address detected by the provider
+		ips = append(ipextract.IPv4(s), ipextract.IPv6(s)...)
 	}
 
 	if len(ips) == 0 {
@@ -154,7 +171,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	newIP = ips[0]
-	if ip.Compare(newIP) != 0 {
+	if ip.IsValid() && ip.Compare(newIP) != 0 {
 		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
 			errors.ErrIPReceivedMismatch, ip, newIP)
 	}
@@ -0,0 +1,101 @@
+package hetzner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// UpdateContent sets the record of the given type matching the content
+// given, creating it if it does not exist and createIfMissing is set.
+// See https://dns.hetzner.com/api-docs#operation/UpdateRecord
+func (p *Provider) UpdateContent(ctx context.Context, client *http.Client,
+	recordType, content string,
+) (err error) {
+	records, err := p.listRecords(ctx, client, recordType)
+	if err != nil {
+		return fmt.Errorf("listing records: %w", err)
+	}
+
+	contents := make([]string, len(records))
+	for i, record := range records {
+		contents[i] = record.Value
+		if utils.IsTXTType(recordType) {
+			contents[i] = utils.UnquoteTXT(record.Value)
+		}
+	}
+	value := content
+	if utils.IsTXTType(recordType) {
+		value = utils.QuoteTXT(content)
+	}
+	index, err := utils.SelectContentRecord(contents, content)
+	switch {
+	case err != nil:
+		return err
+	case index == -1 && !p.createIfMissing:
+		return fmt.Errorf("%w", errors.ErrRecordNotFound)
+	case index == -1:
+		return p.sendContentRecord(ctx, client, http.MethodPost, "/api/v1/records", recordType, value)
+	case contents[index] == content:
+		return nil
+	}
+	return p.sendContentRecord(ctx, client, http.MethodPut, "/api/v1/records/"+records[index].ID,
+		recordType, value)
+}
+
+func (p *Provider) sendContentRecord(ctx context.Context, client *http.Client,
+	method, path, recordType, value string,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "dns.hetzner.com",
+		Path:   path,
+	}
+
+	requestData := struct {
+		Type           string `json:"type"`
+		Name           string `json:"name"`
+		Value          string `json:"value"`
+		ZoneIdentifier string `json:"zone_id"`
+		TTL            uint32 `json:"ttl"`
+	}{
+		Type:           recordType,
+		Name:           p.owner,
+		Value:          value,
+		ZoneIdentifier: p.zoneIdentifier,
+		TTL:            p.ttl,
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode,
+			utils.BodyToSingleLine(response.Body))
+	}
+	return nil
+}
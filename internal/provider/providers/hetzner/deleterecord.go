@@ -0,0 +1,119 @@
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// DeleteRecord deletes the records of the given type for the owner.
+// See https://dns.hetzner.com/api-docs#operation/DeleteRecord
+func (p *Provider) DeleteRecord(ctx context.Context, client *http.Client, recordType string) (err error) {
+	records, err := p.listRecords(ctx, client, recordType)
+	if err != nil {
+		return fmt.Errorf("listing records: %w", err)
+	}
+
+	for _, record := range records {
+		err = p.deleteRecord(ctx, client, record.ID)
+		if err != nil {
+			return fmt.Errorf("deleting record %s: %w", record.ID, err)
+		}
+	}
+	return nil
+}
+
+type dnsRecord struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// listRecords returns the records of the given type for the owner.
+func (p *Provider) listRecords(ctx context.Context, client *http.Client,
+	recordType string,
+) (records []dnsRecord, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "dns.hetzner.com",
+		Path:   "/api/v1/records",
+	}
+
+	values := url.Values{}
+	values.Set("zone_id", p.zoneIdentifier)
+	values.Set("name", p.owner)
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	listRecordsResponse := struct {
+		Records []dnsRecord `json:"records"`
+	}{}
+	err = decoder.Decode(&listRecordsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	for _, record := range listRecordsResponse.Records {
+		if record.Name == p.owner && record.Type == recordType {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (p *Provider) deleteRecord(ctx context.Context, client *http.Client,
+	recordID string,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "dns.hetzner.com",
+		Path:   "/api/v1/records/" + recordID,
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,85 @@
+package hetzner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_DeleteRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		listStatus   int
+		listBody     string
+		deleteStatus int
+		requests     []string
+		errWrapped   error
+		errMessage   string
+	}{
+		"records_deleted": {
+			listStatus: http.StatusOK,
+			listBody: `{"records":[` +
+				`{"id":"1","name":"sub","type":"A","value":"1.2.3.4"},` +
+				`{"id":"2","name":"sub","type":"AAAA","value":"::1"},` +
+				`{"id":"3","name":"sub","type":"A","value":"5.6.7.8"}]}`,
+			deleteStatus: http.StatusOK,
+			requests: []string{
+				"GET /api/v1/records",
+				"DELETE /api/v1/records/1",
+				"DELETE /api/v1/records/3",
+			},
+		},
+		"zone_not_found": {
+			listStatus: http.StatusNotFound,
+			requests:   []string{"GET /api/v1/records"},
+		},
+		"delete_failed": {
+			listStatus:   http.StatusOK,
+			listBody:     `{"records":[{"id":"1","name":"sub","type":"A","value":"1.2.3.4"}]}`,
+			deleteStatus: http.StatusInternalServerError,
+			requests: []string{
+				"GET /api/v1/records",
+				"DELETE /api/v1/records/1",
+			},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "deleting record 1: HTTP status is not valid: 500: error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests []string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				if r.Method == http.MethodGet {
+					w.WriteHeader(testCase.listStatus)
+					_, _ = io.WriteString(w, testCase.listBody)
+					return
+				}
+				w.WriteHeader(testCase.deleteStatus)
+				_, _ = io.WriteString(w, "error")
+			})
+			provider := &Provider{
+				domain:         "example.com",
+				owner:          "sub",
+				token:          "token",
+				zoneIdentifier: "zone",
+			}
+
+			err := provider.DeleteRecord(context.Background(), client, "A")
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.requests, requests)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/netip"
 	"net/url"
+	"slices"
 
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
 	"github.com/qdm12/ddns-updater/internal/provider/errors"
@@ -80,3 +81,64 @@ func (p *Provider) getRecordID(ctx context.Context, client *http.Client, ip neti
 	upToDate = listRecordsResponse.Records[0].Value.Compare(ip) == 0
 	return identifier, upToDate, nil
 }
+
+// GetRecord returns the IP addresses currently set for the record.
+func (p *Provider) GetRecord(ctx context.Context, client *http.Client) (ips []netip.Addr, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "dns.hetzner.com",
+		Path:   "/api/v1/records",
+	}
+
+	values := url.Values{}
+	values.Set("zone_id", p.zoneIdentifier)
+	values.Set("name", p.owner)
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: %d: %s",
+			errors.ErrHTTPStatusNotValid, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	listRecordsResponse := struct {
+		Records []struct {
+			Name  string `json:"name"`
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"records"`
+	}{}
+	err = decoder.Decode(&listRecordsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	recordTypes := utils.RecordTypes(p.ipVersion)
+	for _, record := range listRecordsResponse.Records {
+		if record.Name != p.owner || !slices.Contains(recordTypes, record.Type) {
+			continue
+		}
+		ip, err := netip.ParseAddr(record.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
@@ -0,0 +1,119 @@
+package hetzner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport redirects all requests (to dns.hetzner.com) to the
+// test server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+// newTestClient returns an HTTP client sending all its requests to
+// a test server running the handler given.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &http.Client{Transport: rewriteTransport{
+		host: strings.TrimPrefix(server.URL, "http://"),
+		base: http.DefaultTransport,
+	}}
+}
+
+func Test_Provider_GetRecord(t *testing.T) {
+	t.Parallel()
+
+	const recordsBody = `{"records":[` +
+		`{"name":"sub","type":"A","value":"1.2.3.4"},` +
+		`{"name":"sub","type":"AAAA","value":"::1"},` +
+		`{"name":"sub","type":"TXT","value":"text"},` +
+		`{"name":"other","type":"A","value":"5.6.7.8"}]}`
+
+	testCases := map[string]struct {
+		ipVersion  ipversion.IPVersion
+		statusCode int
+		body       string
+		ips        []netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"ipv4_only": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			body:       recordsBody,
+			ips:        []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+		},
+		"dual_stack": {
+			ipVersion:  ipversion.IP4or6,
+			statusCode: http.StatusOK,
+			body:       recordsBody,
+			ips:        []netip.Addr{netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("::1")},
+		},
+		"not_found": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusNotFound,
+		},
+		"malformed_ip": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			body:       `{"records":[{"name":"sub","type":"A","value":"x"}]}`,
+			errWrapped: errors.ErrIPReceivedMalformed,
+			errMessage: `malformed IP address received: ParseAddr("x"): unable to parse IP`,
+		},
+		"bad_status": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusUnauthorized,
+			body:       `unauthorized`,
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "HTTP status is not valid: 401: unauthorized",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v1/records", r.URL.Path)
+				assert.Equal(t, "zone", r.URL.Query().Get("zone_id"))
+				assert.Equal(t, "sub", r.URL.Query().Get("name"))
+				w.WriteHeader(testCase.statusCode)
+				_, _ = io.WriteString(w, testCase.body)
+			})
+			provider := &Provider{
+				domain:         "example.com",
+				owner:          "sub",
+				ipVersion:      testCase.ipVersion,
+				token:          "token",
+				zoneIdentifier: "zone",
+			}
+
+			ips, err := provider.GetRecord(context.Background(), client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ips, ips)
+		})
+	}
+}
@@ -16,32 +16,31 @@ import (
 )
 
 type Provider struct {
-	domain         string
-	owner          string
-	ipVersion      ipversion.IPVersion
-	ipv6Suffix     netip.Prefix
-	token          string
-	zoneIdentifier string
-	ttl            uint32
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	token           string
+	zoneIdentifier  string
+	ttl             uint32
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
 		Token          string `json:"token"`
 		ZoneIdentifier string `json:"zone_identifier"`
-		TTL            uint32 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	ttl := uint32(1)
-	if extraSettings.TTL > 0 {
-		ttl = extraSettings.TTL
+	if ttl == 0 {
+		ttl = 1
 	}
 
 	err = validateSettings(domain, extraSettings.ZoneIdentifier, extraSettings.Token)
@@ -50,13 +49,14 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:         domain,
-		owner:          owner,
-		ipVersion:      ipVersion,
-		ipv6Suffix:     ipv6Suffix,
-		token:          extraSettings.Token,
-		zoneIdentifier: extraSettings.ZoneIdentifier,
-		ttl:            ttl,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		token:           extraSettings.Token,
+		zoneIdentifier:  extraSettings.ZoneIdentifier,
+		ttl:             ttl,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -115,7 +115,7 @@ func (p *Provider) HTML() models.HTMLRow {
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
 	recordID, upToDate, err := p.getRecordID(ctx, client, ip)
 	switch {
-	case stderrors.Is(err, errors.ErrReceivedNoResult):
+	case stderrors.Is(err, errors.ErrReceivedNoResult) && p.createIfMissing:
 		err = p.createRecord(ctx, client, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
@@ -6,19 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/netip"
-
-	"github.com/qdm12/ddns-updater/internal/provider/constants"
 )
 
-// createRRSet creates a new RRSet with an A or AAAA record.
+// createRRSet creates a new RRSet of the given type with the records given.
 // It should only be called if the record type for the owner name does not exist.
 // See https://docs.hetzner.cloud/reference/cloud#tag/zone-rrsets/create_zone_rrset
-func (p *Provider) createRRSet(ctx context.Context, client *http.Client, ip netip.Addr) (err error) {
-	recordType := constants.A
-	if ip.Is6() {
-		recordType = constants.AAAA
-	}
+func (p *Provider) createRRSet(ctx context.Context, client *http.Client,
+	recordType string, records []record,
+) (err error) {
 	url := fmt.Sprintf("https://api.hetzner.cloud/v1/zones/%s/rrsets", p.domain)
 
 	requestData := struct {
@@ -27,12 +22,10 @@ func (p *Provider) createRRSet(ctx context.Context, client *http.Client, ip neti
 		TTL     uint32   `json:"ttl,omitempty"`
 		Records []record `json:"records"`
 	}{
-		Name: p.owner,
-		Type: recordType,
-		TTL:  p.ttl,
-		Records: []record{
-			{Value: ip.String()},
-		},
+		Name:    p.owner,
+		Type:    recordType,
+		TTL:     p.ttl,
+		Records: records,
 	}
 
 	buffer := bytes.NewBuffer(nil)
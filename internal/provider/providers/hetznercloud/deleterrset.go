@@ -0,0 +1,47 @@
+package hetznercloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// deleteRRSet deletes the RRSet of the given type, and does nothing
+// if it does not exist.
+// See https://docs.hetzner.cloud/reference/cloud#tag/zone-rrsets/delete_zone_rrset
+func (p *Provider) deleteRRSet(ctx context.Context, client *http.Client,
+	recordType string,
+) (err error) {
+	url := fmt.Sprintf("https://api.hetzner.cloud/v1/zones/%s/rrsets/%s/%s",
+		p.domain, p.owner, recordType)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusNotFound:
+		return nil
+	default:
+		return handleErrorResponse(response)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var responseData actionResponse
+	err = decoder.Decode(&responseData)
+	if err != nil {
+		return fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	return p.handleActionResponse(ctx, client, responseData)
+}
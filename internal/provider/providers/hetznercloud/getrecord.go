@@ -12,7 +12,6 @@ import (
 
 // getRecord checks if the record exists and if it is already up to date
 // regarding its IP address.
-// See https://docs.hetzner.cloud/reference/cloud#tag/zone-rrsets/get_zone_rrset
 func (p *Provider) getRecord(ctx context.Context, client *http.Client, ip netip.Addr) (
 	exists, upToDate bool, err error,
 ) {
@@ -20,48 +19,64 @@ func (p *Provider) getRecord(ctx context.Context, client *http.Client, ip netip.
 	if ip.Is6() {
 		recordType = constants.AAAA
 	}
+	values, exists, err := p.getRRSetValues(ctx, client, recordType)
+	if err != nil || !exists {
+		return exists, false, err
+	}
+
+	for _, value := range values {
+		recordIP, err := netip.ParseAddr(value)
+		if err == nil && recordIP == ip {
+			return true, true, nil
+		}
+	}
+
+	return true, false, nil
+}
+
+// getRRSetValues returns the values of the records of the RRSet of the
+// given type, and false if the RRSet does not exist.
+// See https://docs.hetzner.cloud/reference/cloud#tag/zone-rrsets/get_zone_rrset
+func (p *Provider) getRRSetValues(ctx context.Context, client *http.Client, recordType string) (
+	values []string, exists bool, err error,
+) {
 	url := fmt.Sprintf("https://api.hetzner.cloud/v1/zones/%s/rrsets/%s/%s",
 		p.domain, p.owner, recordType)
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return false, false, fmt.Errorf("creating http request: %w", err)
+		return nil, false, fmt.Errorf("creating http request: %w", err)
 	}
 	p.setHeaders(request)
 
 	response, err := client.Do(request)
 	if err != nil {
-		return false, false, err
+		return nil, false, err
 	}
 	defer response.Body.Close()
 
 	switch response.StatusCode {
 	case http.StatusOK:
 	case http.StatusNotFound:
-		return false, false, nil
+		return nil, false, nil
 	default:
-		return false, false, handleErrorResponse(response)
+		return nil, false, handleErrorResponse(response)
 	}
 
 	decoder := json.NewDecoder(response.Body)
 	var responseData struct {
 		RRSet struct {
-			Records []struct {
-				Value string `json:"value"`
-			} `json:"records"`
+			Records []record `json:"records"`
 		} `json:"rrset"`
 	}
 	err = decoder.Decode(&responseData)
 	if err != nil {
-		return true, false, fmt.Errorf("json decoding response body: %w", err)
+		return nil, true, fmt.Errorf("json decoding response body: %w", err)
 	}
 
-	for _, record := range responseData.RRSet.Records {
-		recordIP, err := netip.ParseAddr(record.Value)
-		if err == nil && recordIP == ip {
-			return true, true, nil
-		}
+	values = make([]string, len(responseData.RRSet.Records))
+	for i, record := range responseData.RRSet.Records {
+		values[i] = record.Value
 	}
-
-	return true, false, nil
+	return values, true, nil
 }
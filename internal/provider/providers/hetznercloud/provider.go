@@ -23,34 +23,35 @@ type Provider struct {
 	// ttl is the Time To Live for the DNS record in seconds.
 	// It is optional, and is ONLY used to add a record to the rrset.
 	// See https://docs.hetzner.cloud/reference/cloud#tag/zone-rrset-actions/add_zone_rrset_records.body.ttl
-	ttl uint32
+	ttl             uint32
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
 		Token string `json:"token"`
-		TTL   uint32 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	err = validateSettings(domain, extraSettings.Token, extraSettings.TTL)
+	err = validateSettings(domain, extraSettings.Token, ttl)
 	if err != nil {
 		return nil, fmt.Errorf("validating provider specific settings: %w", err)
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		token:      extraSettings.Token,
-		ttl:        extraSettings.TTL,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		token:           extraSettings.Token,
+		ttl:             ttl,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -124,15 +125,105 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	case upToDate:
 		return ip, nil
 	case exists:
-		err = p.setRecord(ctx, client, ip)
+		err = p.setRecord(ctx, client, ipToRecordType(ip), makeRecords([]netip.Addr{ip}))
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("updating record: %w", err)
 		}
+	case !p.createIfMissing:
+		return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
 	default:
-		err = p.createRRSet(ctx, client, ip)
+		err = p.createRRSet(ctx, client, ipToRecordType(ip), makeRecords([]netip.Addr{ip}))
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
 	}
 	return ip, nil
 }
+
+// UpdateMulti sets the A and AAAA record sets to the IPv4 and IPv6
+// addresses given, and deletes the record set of an IP family of the
+// record IP version if no IP address of this family is given.
+func (p *Provider) UpdateMulti(ctx context.Context, client *http.Client,
+	ips []netip.Addr,
+) (newIPs []netip.Addr, err error) {
+	for _, recordSet := range utils.GroupManagedRecordSets(p.ipVersion, ips) {
+		if len(recordSet.IPs) == 0 {
+			err = p.deleteRRSet(ctx, client, recordSet.Type)
+			if err != nil {
+				return nil, fmt.Errorf("deleting %s record: %w", recordSet.Type, err)
+			}
+			continue
+		}
+		exists, _, err := p.getRecord(ctx, client, recordSet.IPs[0])
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("getting %s record: %w", recordSet.Type, err)
+		case exists:
+			err = p.setRecord(ctx, client, recordSet.Type, makeRecords(recordSet.IPs))
+			if err != nil {
+				return nil, fmt.Errorf("updating %s record: %w", recordSet.Type, err)
+			}
+		case !p.createIfMissing:
+			return nil, fmt.Errorf("%w: %s", errors.ErrRecordNotFound, recordSet.Type)
+		default:
+			err = p.createRRSet(ctx, client, recordSet.Type, makeRecords(recordSet.IPs))
+			if err != nil {
+				return nil, fmt.Errorf("creating %s record: %w", recordSet.Type, err)
+			}
+		}
+	}
+	return ips, nil
+}
+
+// UpdateContent sets the record of the RRSet of the given type matching
+// the content given, keeping the other records of the RRSet.
+func (p *Provider) UpdateContent(ctx context.Context, client *http.Client,
+	recordType, content string,
+) (err error) {
+	values, exists, err := p.getRRSetValues(ctx, client, recordType)
+	if err != nil {
+		return fmt.Errorf("getting %s record: %w", recordType, err)
+	} else if !exists && !p.createIfMissing {
+		return fmt.Errorf("%w: %s", errors.ErrRecordNotFound, recordType)
+	}
+
+	contents := make([]string, len(values))
+	for i, value := range values {
+		contents[i] = value
+		if utils.IsTXTType(recordType) {
+			contents[i] = utils.UnquoteTXT(value)
+		}
+	}
+	value := content
+	if utils.IsTXTType(recordType) {
+		value = utils.QuoteTXT(content)
+	}
+	index, err := utils.SelectContentRecord(contents, content)
+	switch {
+	case err != nil:
+		return err
+	case index == -1:
+		values = append(values, value)
+	case contents[index] == content:
+		return nil
+	default:
+		values[index] = value
+	}
+
+	records := make([]record, len(values))
+	for i, value := range values {
+		records[i] = record{Value: value}
+	}
+	if !exists {
+		err = p.createRRSet(ctx, client, recordType, records)
+		if err != nil {
+			return fmt.Errorf("creating %s record: %w", recordType, err)
+		}
+		return nil
+	}
+	err = p.setRecord(ctx, client, recordType, records)
+	if err != nil {
+		return fmt.Errorf("updating %s record: %w", recordType, err)
+	}
+	return nil
+}
@@ -6,29 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/netip"
-
-	"github.com/qdm12/ddns-updater/internal/provider/constants"
 )
 
 // setRecord updates an existing DNS record using the set_records action.
-// It replaces all existing A or AAAA records with the new IP address.
+// It replaces all existing records of the given type with the records given.
 // See https://docs.hetzner.cloud/reference/cloud#tag/zone-rrset-actions/set_zone_rrset_records
-func (p *Provider) setRecord(ctx context.Context, client *http.Client, ip netip.Addr) (err error) {
-	recordType := constants.A
-	if ip.Is6() {
-		recordType = constants.AAAA
-	}
-
+func (p *Provider) setRecord(ctx context.Context, client *http.Client,
+	recordType string, records []record,
+) (err error) {
 	const urlTemplate = "https://api.hetzner.cloud/v1/zones/%s/rrsets/%s/%s/actions/set_records"
 	urlString := fmt.Sprintf(urlTemplate, p.domain, p.owner, recordType)
 
 	requestData := struct {
 		Records []record `json:"records"`
 	}{
-		Records: []record{
-			{Value: ip.String()},
-		},
+		Records: records,
 	}
 
 	buffer := bytes.NewBuffer(nil)
@@ -2,13 +2,31 @@ package hetznercloud
 
 import (
 	"fmt"
+	"net/netip"
 	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
 )
 
 type record struct {
 	Value string `json:"value"`
 }
 
+func ipToRecordType(ip netip.Addr) string {
+	if ip.Is6() {
+		return constants.AAAA
+	}
+	return constants.A
+}
+
+func makeRecords(ips []netip.Addr) (records []record) {
+	records = make([]record, len(ips))
+	for i, ip := range ips {
+		records[i] = record{Value: ip.String()}
+	}
+	return records
+}
+
 type actionResponse struct {
 	Action struct {
 		ID     uint64 `json:"id"`
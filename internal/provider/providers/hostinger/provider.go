@@ -30,11 +30,10 @@ type Provider struct {
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, ttl uint32,
 ) (p *Provider, err error) {
 	extraSettings := struct {
 		Token string `json:"token"`
-		TTL   uint32 `json:"ttl"`
 	}{}
 
 	err = json.Unmarshal(data, &extraSettings)
@@ -43,8 +42,8 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	const defaultTTL uint32 = 14400
-	if extraSettings.TTL == 0 {
-		extraSettings.TTL = defaultTTL
+	if ttl == 0 {
+		ttl = defaultTTL
 	}
 
 	err = validateSettings(domain, extraSettings.Token)
@@ -58,7 +57,7 @@ func New(data json.RawMessage, domain, owner string,
 		ipVersion:  ipVersion,
 		ipv6Suffix: ipv6Suffix,
 		token:      extraSettings.Token,
-		ttl:        extraSettings.TTL,
+		ttl:        ttl,
 	}, nil
 }
 
@@ -29,13 +29,17 @@ func (p *Provider) createRecord(ctx context.Context, client *http.Client,
 	}
 
 	const defaultTTL = 3600
+	ttl := uint32(defaultTTL)
+	if p.ttl > 0 {
+		ttl = p.ttl
+	}
 	const defaultPrio = 0
 	recordsList := []apiRecord{
 		{
 			Name:     utils.BuildURLQueryHostname(p.owner, p.domain),
 			Type:     recordType,
 			Content:  ip.String(),
-			TTL:      defaultTTL,
+			TTL:      ttl,
 			Prio:     defaultPrio,
 			Disabled: false,
 		},
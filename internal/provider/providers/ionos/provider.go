@@ -15,15 +15,17 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	apiKey     string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	apiKey          string
+	createIfMissing bool
+	ttl             uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -39,11 +41,13 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		apiKey:     extraSettings.APIKey,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		apiKey:          extraSettings.APIKey,
+		createIfMissing: createIfMissing,
+		ttl:             ttl,
 	}, nil
 }
 
@@ -139,6 +143,9 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	if len(matchingRecords) == 0 {
+		if !p.createIfMissing {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
 		err = p.createRecord(ctx, client, zoneID, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
@@ -32,6 +32,9 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 		Prio:     existingRecord.Prio,
 		Disabled: existingRecord.Disabled,
 	}
+	if p.ttl > 0 {
+		recordUpdate.TTL = p.ttl
+	}
 
 	buffer := bytes.NewBuffer(nil)
 	encoder := json.NewEncoder(buffer)
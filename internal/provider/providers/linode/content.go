@@ -0,0 +1,148 @@
+package linode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// UpdateContent sets the record of the given type matching the content
+// given, creating it if it does not exist and createIfMissing is set.
+// See https://techdocs.akamai.com/linode-api/reference/put-domain-record
+func (p *Provider) UpdateContent(ctx context.Context, client *http.Client,
+	recordType, content string,
+) (err error) {
+	domainID, err := p.getDomainID(ctx, client)
+	if err != nil {
+		return fmt.Errorf("getting domain id: %w", err)
+	}
+
+	records, err := p.listRecords(ctx, client, domainID, recordType)
+	if err != nil {
+		return fmt.Errorf("listing records: %w", err)
+	}
+
+	contents := make([]string, len(records))
+	for i, record := range records {
+		contents[i] = record.Target
+	}
+	index, err := utils.SelectContentRecord(contents, content)
+	switch {
+	case err != nil:
+		return err
+	case index == -1 && !p.createIfMissing:
+		return fmt.Errorf("%w", errors.ErrRecordNotFound)
+	case index == -1:
+		return p.sendContentRecord(ctx, client, http.MethodPost,
+			fmt.Sprintf("/v4/domains/%d/records", domainID), recordType, content)
+	case contents[index] == content:
+		return nil
+	}
+	return p.sendContentRecord(ctx, client, http.MethodPut,
+		fmt.Sprintf("/v4/domains/%d/records/%d", domainID, records[index].ID), recordType, content)
+}
+
+type domainRecord struct {
+	ID     int    `json:"id"`
+	Host   string `json:"name"`
+	Type   string `json:"type"`
+	Target string `json:"target"`
+}
+
+func (p *Provider) listRecords(ctx context.Context, client *http.Client,
+	domainID int, recordType string,
+) (records []domainRecord, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.linode.com",
+		Path:   fmt.Sprintf("/v4/domains/%d/records", domainID),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+	headers.SetOauth(request, "domains:read_only")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		return nil, fmt.Errorf("%w: %s", err, p.getErrorMessage(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var obj struct {
+		Data []domainRecord `json:"data"`
+	}
+	err = decoder.Decode(&obj)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	for _, record := range obj.Data {
+		if record.Type == recordType && record.Host == p.owner {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (p *Provider) sendContentRecord(ctx context.Context, client *http.Client,
+	method, path, recordType, content string,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.linode.com",
+		Path:   path,
+	}
+
+	requestData := struct {
+		Type   string `json:"type"`
+		Host   string `json:"name"`
+		Target string `json:"target"`
+		TTL    uint32 `json:"ttl_sec,omitempty"`
+	}{
+		Type:   recordType,
+		Host:   p.BuildDomainName(),
+		Target: content,
+		TTL:    p.ttl,
+	}
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(requestData)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+	headers.SetOauth(request, "domains:read_write")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		return fmt.Errorf("%w: %s", err, p.getErrorMessage(response.Body))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package linode
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+)
+
+// DeleteRecord deletes the record of the given type for the owner and domain.
+// See https://techdocs.akamai.com/linode-api/reference/delete-domain-record
+func (p *Provider) DeleteRecord(ctx context.Context, client *http.Client, recordType string) (err error) {
+	domainID, err := p.getDomainID(ctx, client)
+	if err != nil {
+		return fmt.Errorf("getting domain id: %w", err)
+	}
+
+	recordID, err := p.getRecordID(ctx, client, domainID, recordType)
+	switch {
+	case goerrors.Is(err, errors.ErrRecordNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("getting record id: %w", err)
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.linode.com",
+		Path:   fmt.Sprintf("/v4/domains/%d/records/%d", domainID, recordID),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+	headers.SetOauth(request, "domains:read_write")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNotFound:
+		return nil
+	default:
+		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		return fmt.Errorf("%w: %s", err, p.getErrorMessage(response.Body))
+	}
+}
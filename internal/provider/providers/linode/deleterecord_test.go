@@ -0,0 +1,81 @@
+package linode
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_DeleteRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		recordsBody  string
+		deleteStatus int
+		requests     []string
+		errWrapped   error
+		errMessage   string
+	}{
+		"record_deleted": {
+			recordsBody: `{"data":[{"id":1,"name":"sub","type":"AAAA"},` +
+				`{"id":2,"name":"sub","type":"A"}]}`,
+			deleteStatus: http.StatusOK,
+			requests: []string{
+				"GET /v4/domains",
+				"GET /v4/domains/10/records",
+				"DELETE /v4/domains/10/records/2",
+			},
+		},
+		"no_record": {
+			recordsBody: `{"data":[{"id":1,"name":"sub","type":"AAAA"}]}`,
+			requests: []string{
+				"GET /v4/domains",
+				"GET /v4/domains/10/records",
+			},
+		},
+		"delete_failed": {
+			recordsBody:  `{"data":[{"id":2,"name":"sub","type":"A"}]}`,
+			deleteStatus: http.StatusInternalServerError,
+			requests: []string{
+				"GET /v4/domains",
+				"GET /v4/domains/10/records",
+				"DELETE /v4/domains/10/records/2",
+			},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "HTTP status is not valid: 500: error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests []string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch {
+				case r.URL.Path == "/v4/domains":
+					_, _ = io.WriteString(w, `{"data":[{"id":10,"status":"active"}]}`)
+				case r.Method == http.MethodGet:
+					_, _ = io.WriteString(w, testCase.recordsBody)
+				default:
+					w.WriteHeader(testCase.deleteStatus)
+					_, _ = io.WriteString(w, "error")
+				}
+			})
+			provider := &Provider{domain: "example.com", owner: "sub", token: "token"}
+
+			err := provider.DeleteRecord(context.Background(), client, "A")
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.requests, requests)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package linode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// GetRecord returns the IP addresses currently set for the record.
+func (p *Provider) GetRecord(ctx context.Context, client *http.Client) (ips []netip.Addr, err error) {
+	domainID, err := p.getDomainID(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("getting domain id: %w", err)
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.linode.com",
+		Path:   fmt.Sprintf("/v4/domains/%d/records", domainID),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request)
+	headers.SetOauth(request, "domains:read_only")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		return nil, fmt.Errorf("%w: %s", err, p.getErrorMessage(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var obj struct {
+		Data []struct {
+			Host   string `json:"name"`
+			Type   string `json:"type"`
+			Target string `json:"target"`
+		} `json:"data"`
+	}
+	err = decoder.Decode(&obj)
+	if err != nil {
+		return nil, fmt.Errorf("json decoding response body: %w", err)
+	}
+
+	recordTypes := utils.RecordTypes(p.ipVersion)
+	for _, domainRecord := range obj.Data {
+		if domainRecord.Host != p.owner || !slices.Contains(recordTypes, domainRecord.Type) {
+			continue
+		}
+		ip, err := netip.ParseAddr(domainRecord.Target)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
@@ -0,0 +1,120 @@
+package linode
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport redirects all requests (to api.linode.com) to the
+// test server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+// newTestClient returns an HTTP client sending all its requests to
+// a test server running the handler given.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &http.Client{Transport: rewriteTransport{
+		host: strings.TrimPrefix(server.URL, "http://"),
+		base: http.DefaultTransport,
+	}}
+}
+
+func Test_Provider_GetRecord(t *testing.T) {
+	t.Parallel()
+
+	const recordsBody = `{"data":[` +
+		`{"id":1,"name":"sub","type":"A","target":"1.2.3.4"},` +
+		`{"id":2,"name":"sub","type":"AAAA","target":"::1"},` +
+		`{"id":3,"name":"other","type":"A","target":"5.6.7.8"}]}`
+
+	testCases := map[string]struct {
+		ipVersion     ipversion.IPVersion
+		domainsBody   string
+		recordsStatus int
+		recordsBody   string
+		ips           []netip.Addr
+		errWrapped    error
+		errMessage    string
+	}{
+		"ipv6_only": {
+			ipVersion:     ipversion.IP6,
+			domainsBody:   `{"data":[{"id":10,"status":"active"}]}`,
+			recordsStatus: http.StatusOK,
+			recordsBody:   recordsBody,
+			ips:           []netip.Addr{netip.MustParseAddr("::1")},
+		},
+		"dual_stack": {
+			ipVersion:     ipversion.IP4or6,
+			domainsBody:   `{"data":[{"id":10,"status":"active"}]}`,
+			recordsStatus: http.StatusOK,
+			recordsBody:   recordsBody,
+			ips:           []netip.Addr{netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("::1")},
+		},
+		"domain_not_found": {
+			ipVersion:   ipversion.IP4,
+			domainsBody: `{"data":[]}`,
+			errWrapped:  errors.ErrDomainIDNotFound,
+			errMessage:  "getting domain id: ID not found in domain record",
+		},
+		"bad_status": {
+			ipVersion:     ipversion.IP4,
+			domainsBody:   `{"data":[{"id":10,"status":"active"}]}`,
+			recordsStatus: http.StatusUnauthorized,
+			recordsBody:   `unauthorized`,
+			errWrapped:    errors.ErrHTTPStatusNotValid,
+			errMessage:    "HTTP status is not valid: 401: unauthorized",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v4/domains":
+					_, _ = io.WriteString(w, testCase.domainsBody)
+				case "/v4/domains/10/records":
+					w.WriteHeader(testCase.recordsStatus)
+					_, _ = io.WriteString(w, testCase.recordsBody)
+				default:
+					t.Errorf("unexpected request path %s", r.URL.Path)
+				}
+			})
+			provider := &Provider{
+				domain:    "example.com",
+				owner:     "sub",
+				ipVersion: testCase.ipVersion,
+				token:     "token",
+			}
+
+			ips, err := provider.GetRecord(context.Background(), client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ips, ips)
+		})
+	}
+}
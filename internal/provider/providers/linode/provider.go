@@ -20,15 +20,17 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	token      string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	token           string
+	createIfMissing bool
+	ttl             uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -45,11 +47,13 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		token:      extraSettings.Token,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		token:           extraSettings.Token,
+		createIfMissing: createIfMissing,
+		ttl:             ttl,
 	}, nil
 }
 
@@ -116,7 +120,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	recordID, err := p.getRecordID(ctx, client, domainID, recordType)
-	if goerrors.Is(err, errors.ErrRecordNotFound) {
+	if goerrors.Is(err, errors.ErrRecordNotFound) && p.createIfMissing {
 		err := p.createRecord(ctx, client, domainID, recordType, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
@@ -269,12 +273,14 @@ func (p *Provider) createRecord(ctx context.Context, client *http.Client,
 		Type string `json:"type"`
 		Host string `json:"name"`
 		IP   string `json:"target"`
+		TTL  uint32 `json:"ttl_sec,omitempty"`
 	}
 
 	requestData := domainRecord{
 		Type: recordType,
 		Host: p.BuildDomainName(),
 		IP:   ip.String(),
+		TTL:  p.ttl,
 	}
 	buffer := bytes.NewBuffer(nil)
 	encoder := json.NewEncoder(buffer)
@@ -329,9 +335,11 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 	}
 
 	data := struct {
-		IP string `json:"target"`
+		IP  string `json:"target"`
+		TTL uint32 `json:"ttl_sec,omitempty"`
 	}{
-		IP: ip.String(),
+		IP:  ip.String(),
+		TTL: p.ttl,
 	}
 	buffer := bytes.NewBuffer(nil)
 	encoder := json.NewEncoder(buffer)
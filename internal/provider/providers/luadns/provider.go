@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,16 +21,18 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	email      string
-	token      string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	email           string
+	token           string
+	createIfMissing bool
+	ttl             uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -47,12 +50,14 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		email:      extraSettings.Email,
-		token:      extraSettings.Token,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		email:           extraSettings.Email,
+		token:           extraSettings.Token,
+		createIfMissing: createIfMissing,
+		ttl:             ttl,
 	}, nil
 }
 
@@ -125,12 +130,22 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	record, err := p.getRecord(ctx, client, zoneID, ip)
-	if err != nil {
+	switch {
+	case stderrors.Is(err, errors.ErrRecordNotFound) && p.createIfMissing:
+		err = p.createRecord(ctx, client, zoneID, ip)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
+		}
+		return ip, nil
+	case err != nil:
 		return netip.Addr{}, fmt.Errorf("getting record: %w", err)
 	}
 
 	newRecord := record
 	newRecord.Content = ip.String()
+	if p.ttl > 0 {
+		newRecord.TTL = p.ttl
+	}
 	err = p.updateRecord(ctx, client, zoneID, newRecord)
 	if err != nil {
 		return netip.Addr{}, fmt.Errorf("updating record: %w", err)
@@ -258,6 +273,68 @@ func (p *Provider) getRecord(ctx context.Context, client *http.Client, zoneID in
 		errors.ErrRecordNotFound, recordType, zoneID)
 }
 
+func (p *Provider) createRecord(ctx context.Context, client *http.Client,
+	zoneID int, ip netip.Addr,
+) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.luadns.com",
+		Path:   fmt.Sprintf("/v1/zones/%d/records", zoneID),
+		User:   url.UserPassword(p.email, p.token),
+	}
+
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+	const defaultTTL = 300
+	newRecord := luaDNSRecord{
+		Name:    utils.BuildURLQueryHostname(p.owner, p.domain) + ".",
+		Type:    recordType,
+		Content: ip.String(),
+		TTL:     defaultTTL,
+	}
+	if p.ttl > 0 {
+		newRecord.TTL = p.ttl
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	err = encoder.Encode(newRecord)
+	if err != nil {
+		return fmt.Errorf("json encoding request data: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
+	if err != nil {
+		return fmt.Errorf("creating http request: %w", err)
+	}
+	setHeaders(request)
+	headers.SetContentType(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated {
+		err = fmt.Errorf("%w: %d", errors.ErrHTTPStatusNotValid, response.StatusCode)
+		var errorObj luaDNSError
+		if jsonErr := json.Unmarshal(b, &errorObj); jsonErr != nil {
+			return fmt.Errorf("%w: %s", err, utils.ToSingleLine(string(b)))
+		}
+		return fmt.Errorf("%w: %s: %s",
+			err, errorObj.Status, errorObj.Message)
+	}
+	return nil
+}
+
 func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 	zoneID int, newRecord luaDNSRecord,
 ) (err error) {
@@ -16,42 +16,48 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	username   string
-	token      string
-	ttl        *uint32
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	username        string
+	token           string
+	ttl             *uint32
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
-		Username string  `json:"username"`
-		Token    string  `json:"token"`
-		TTL      *uint32 `json:"ttl,omitempty"`
+		Username string `json:"username"`
+		Token    string `json:"token"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	err = validateSettings(domain, extraSettings.Username, extraSettings.Token, extraSettings.TTL)
+	var ttlPtr *uint32
+	if ttl > 0 {
+		ttlPtr = &ttl
+	}
+
+	err = validateSettings(domain, extraSettings.Username, extraSettings.Token, ttlPtr)
 	if err != nil {
 		return nil, fmt.Errorf("validating provider specific settings: %w", err)
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		username:   extraSettings.Username,
-		token:      extraSettings.Token,
-		ttl:        extraSettings.TTL,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		username:        extraSettings.Username,
+		token:           extraSettings.Token,
+		ttl:             ttlPtr,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -121,7 +127,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 
 	recordID, err := p.getRecordID(ctx, client, recordType)
 
-	if stderrors.Is(err, errors.ErrRecordNotFound) {
+	if stderrors.Is(err, errors.ErrRecordNotFound) && p.createIfMissing {
 		err = p.createRecord(ctx, client, ip)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
@@ -20,12 +20,13 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	key        string
-	ttl        *uint32
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	key             string
+	ttl             *uint32
+	createIfMissing bool
 }
 
 type apiResponse struct {
@@ -42,30 +43,35 @@ type apiResponse struct {
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	provider *Provider, err error,
 ) {
 	var providerSpecificSettings struct {
-		Key string  `json:"key"`
-		TTL *uint32 `json:"ttl,omitempty"`
+		Key string `json:"key"`
 	}
 	err = json.Unmarshal(data, &providerSpecificSettings)
 	if err != nil {
 		return nil, fmt.Errorf("json decoding provider specific settings: %w", err)
 	}
 
-	err = validateSettings(domain, providerSpecificSettings.Key, providerSpecificSettings.TTL)
+	var ttlPtr *uint32
+	if ttl > 0 {
+		ttlPtr = &ttl
+	}
+
+	err = validateSettings(domain, providerSpecificSettings.Key, ttlPtr)
 	if err != nil {
 		return nil, fmt.Errorf("validating provider specific settings: %w", err)
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		key:        providerSpecificSettings.Key,
-		ttl:        providerSpecificSettings.TTL,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		key:             providerSpecificSettings.Key,
+		ttl:             ttlPtr,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -128,7 +134,7 @@ func (p *Provider) HTML() models.HTMLRow {
 }
 
 // Update does the following:
-// 1. if there's no record, create it.
+// 1. if there's no record, create it if createIfMissing is set.
 // 2. if it exists and ip is different, update it.
 // 3. if it exists and ip is the same, do nothing.
 func (p *Provider) Update(ctx context.Context, client *http.Client, newIP netip.Addr) (netip.Addr, error) {
@@ -139,7 +145,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, newIP netip.
 
 	recordID, currentIP, err := p.getRecord(ctx, client, recordType)
 
-	if stderrors.Is(err, errors.ErrRecordNotFound) {
+	if stderrors.Is(err, errors.ErrRecordNotFound) && p.createIfMissing {
 		if err := p.createRecord(ctx, client, recordType, newIP); err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
 		}
@@ -15,17 +15,18 @@ import (
 )
 
 type Provider struct {
-	domain         string
-	owner          string
-	ipVersion      ipversion.IPVersion
-	ipv6Suffix     netip.Prefix
-	customerNumber string
-	apiKey         string
-	password       string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	customerNumber  string
+	apiKey          string
+	password        string
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool) (
 	p *Provider, err error,
 ) {
 	var extraSettings struct {
@@ -45,13 +46,14 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:         domain,
-		owner:          owner,
-		ipVersion:      ipVersion,
-		ipv6Suffix:     ipv6Suffix,
-		customerNumber: extraSettings.CustomerNumber,
-		apiKey:         extraSettings.APIKey,
-		password:       extraSettings.Password,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		customerNumber:  extraSettings.CustomerNumber,
+		apiKey:          extraSettings.APIKey,
+		password:        extraSettings.Password,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
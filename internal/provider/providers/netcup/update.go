@@ -7,6 +7,7 @@ import (
 	"net/netip"
 
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
 )
 
 func (p *Provider) getRecordToUpdate(ctx context.Context,
@@ -30,6 +31,11 @@ func (p *Provider) getRecordToUpdate(ctx context.Context,
 		}
 	}
 
+	if !p.createIfMissing {
+		return dnsRecord{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+	}
+
+	// A record without identifier is created by updateDnsRecords.
 	return dnsRecord{
 		Hostname:    p.owner,
 		Type:        recordType,
@@ -0,0 +1,86 @@
+package netcup
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport redirects all requests (to ccp.netcup.net) to the
+// test server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+func Test_Provider_getRecordToUpdate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		createIfMissing bool
+		records         string
+		record          dnsRecord
+		errWrapped      error
+		errMessage      string
+	}{
+		"existing_record": {
+			records: `[{"id":"1","hostname":"sub","type":"A","destination":"5.6.7.8"}]`,
+			record: dnsRecord{
+				ID: "1", Hostname: "sub", Type: "A", Destination: "1.2.3.4",
+			},
+		},
+		"missing_record_created": {
+			createIfMissing: true,
+			records:         `[{"id":"1","hostname":"sub","type":"AAAA","destination":"::1"}]`,
+			record:          dnsRecord{Hostname: "sub", Type: "A", Destination: "1.2.3.4"},
+		},
+		"missing_record_not_created": {
+			records:    `[{"id":"1","hostname":"sub","type":"AAAA","destination":"::1"}]`,
+			errWrapped: errors.ErrRecordNotFound,
+			errMessage: "record not found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, `{"status":"success","responsedata":{"dnsrecords":`+
+					testCase.records+`}}`)
+			}))
+			t.Cleanup(server.Close)
+
+			client := &http.Client{Transport: rewriteTransport{
+				host: server.Listener.Addr().String(),
+				base: http.DefaultTransport,
+			}}
+			provider := &Provider{
+				domain:          "example.com",
+				owner:           "sub",
+				createIfMissing: testCase.createIfMissing,
+			}
+
+			record, err := provider.getRecordToUpdate(context.Background(), client,
+				"session", netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.record, record)
+		})
+	}
+}
@@ -113,6 +113,63 @@ func (p *Provider) HTML() models.HTMLRow {
 }
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	return p.update(ctx, client, ip)
+}
+
+// UpdateAuto updates the record without specifying an IP address,
+// letting the provider detect it from the source address of the request.
+func (p *Provider) UpdateAuto(ctx context.Context, client *http.Client) (newIP netip.Addr, err error) {
+	return p.update(ctx, client, netip.Addr{})
+}
+
+// BatchKey returns the username of the record, since records
+// of the same username can be updated in a single request.
+func (p *Provider) BatchKey() string {
+	return p.username
+}
+
+// UpdateBatch updates the records with the hostnames given to the
+// IP address given in a single request.
+func (p *Provider) UpdateBatch(ctx context.Context, client *http.Client,
+	hostnames []string, ip netip.Addr,
+) (errs []error, err error) {
+	s, statusCode, err := p.request(ctx, client, strings.Join(hostnames, ","), ip)
+	if err != nil {
+		return nil, err
+	}
+
+	// A line is received for each hostname, unless the whole request failed.
+	lines := strings.Split(s, "\n")
+	if len(lines) != len(hostnames) {
+		_, err = parseResponse(s, statusCode, ip)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %d lines for %d hostnames",
+			errors.ErrResultsCountReceived, len(lines), len(hostnames))
+	}
+	errs = make([]error, len(hostnames))
+	for i, line := range lines {
+		_, errs[i] = parseResponse(strings.TrimSpace(line), statusCode, ip)
+	}
+	return errs, nil
+}
+
+// update updates the record with the IP address given, or with the IP
+// address detected by the provider if the IP address given is invalid.
+func (p *Provider) update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	s, statusCode, err := p.request(ctx, client, utils.BuildURLQueryHostname(p.owner, p.domain), ip)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return parseResponse(s, statusCode, ip)
+}
+
+// request sends an update request for the comma separated hostnames
+// given, and returns the cleaned response body and status code.
+func (p *Provider) request(ctx context.Context, client *http.Client,
+	hostnames string, ip netip.Addr,
+) (s string, statusCode int, err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   "dynupdate.no-ip.com",
@@ -120,30 +177,36 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		User:   url.UserPassword(p.username, p.password),
 	}
 	values := url.Values{}
-	values.Set("hostname", utils.BuildURLQueryHostname(p.owner, p.domain))
+	values.Set("hostname", hostnames)
 	// See https://help.dyn.com/remote-access-api/perform-update/ stating:
 	// This authentication method supports both IPv6 and IPv4 addresses.
 	// Use commas to separate multiple IP addresses in the myip field.
-	values.Set("myip", ip.String())
+	if ip.IsValid() {
+		values.Set("myip", ip.String())
+	}
 	u.RawQuery = values.Encode()
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+		return "", 0, fmt.Errorf("creating http request: %w", err)
 	}
 	headers.SetUserAgent(request)
 
 	response, err := client.Do(request)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("doing http request: %w", err)
+		return "", 0, fmt.Errorf("doing http request: %w", err)
 	}
 	defer response.Body.Close()
 
-	s, err := utils.ReadAndCleanBody(response.Body)
+	s, err = utils.ReadAndCleanBody(response.Body)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("reading response: %w", err)
+		return "", 0, fmt.Errorf("reading response: %w", err)
 	}
+	return s, response.StatusCode, nil
+}
 
+// parseResponse parses the response s for a single hostname.
+func parseResponse(s string, statusCode int, ip netip.Addr) (newIP netip.Addr, err error) {
 	switch s {
 	case "":
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrReceivedNoResult)
@@ -161,17 +224,20 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		return netip.Addr{}, fmt.Errorf("%w", errors.ErrHostnameNotExists)
 	}
 
-	if response.StatusCode != http.StatusOK {
-		return netip.Addr{}, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid, response.StatusCode, s)
+	if statusCode != http.StatusOK {
+		return netip.Addr{}, fmt.Errorf("%w: %d: %s", errors.ErrHTTPStatusNotValid, statusCode, s)
 	} else if !strings.Contains(s, "nochg") && !strings.Contains(s, "good") {
 		return netip.Addr{}, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, s)
 	}
 
 	var ips []netip.Addr
-	if ip.Is4() {
+	switch {
+	case ip.Is4():
 		ips = ipextract.IPv4(s)
-	} else {
+	case ip.Is6():
 		ips = ipextract.IPv6(s)
+	default: // IP address detected by the provider
+		ips = append(ipextract.IPv4(s), ipextract.IPv6(s)...)
 	}
 
 	if len(ips) == 0 {
@@ -179,7 +245,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	newIP = ips[0]
-	if ip.Compare(newIP) != 0 {
+	if ip.IsValid() && ip.Compare(newIP) != 0 {
 		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
 			errors.ErrIPReceivedMismatch, ip, newIP)
 	}
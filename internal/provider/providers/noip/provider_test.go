@@ -0,0 +1,96 @@
+package noip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rewriteTransport redirects all requests (to dynupdate.no-ip.com) to the
+// test server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+func Test_Provider_UpdateBatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		statusCode int
+		body       string
+		errs       []error
+		errWrapped error
+		errMessage string
+	}{
+		"all_updated": {
+			statusCode: http.StatusOK,
+			body:       "good 1.2.3.4\nnochg 1.2.3.4\n",
+			errs:       []error{nil, nil},
+		},
+		"one_hostname_failed": {
+			statusCode: http.StatusOK,
+			body:       "good 1.2.3.4\nnohost\n",
+			errs:       []error{nil, errors.ErrHostnameNotExists},
+		},
+		"whole_batch_failed": {
+			statusCode: http.StatusUnauthorized,
+			body:       "badauth",
+			errWrapped: errors.ErrAuth,
+			errMessage: "bad authentication",
+		},
+		"missing_lines": {
+			statusCode: http.StatusOK,
+			body:       "good 1.2.3.4",
+			errWrapped: errors.ErrResultsCountReceived,
+			errMessage: "wrong number of results received: 1 lines for 2 hostnames",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/nic/update", r.URL.Path)
+				assert.Equal(t, "a.example.com,b.example.com", r.URL.Query().Get("hostname"))
+				assert.Equal(t, "1.2.3.4", r.URL.Query().Get("myip"))
+				w.WriteHeader(testCase.statusCode)
+				_, _ = w.Write([]byte(testCase.body))
+			}))
+			t.Cleanup(server.Close)
+
+			client := &http.Client{Transport: rewriteTransport{
+				host: server.Listener.Addr().String(),
+				base: http.DefaultTransport,
+			}}
+			provider := &Provider{username: "username", password: "password"}
+
+			errs, err := provider.UpdateBatch(context.Background(), client,
+				[]string{"a.example.com", "b.example.com"}, netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				assert.Nil(t, errs)
+				return
+			}
+			require.Len(t, errs, len(testCase.errs))
+			for i, expectedErr := range testCase.errs {
+				assert.ErrorIs(t, errs[i], expectedErr)
+			}
+		})
+	}
+}
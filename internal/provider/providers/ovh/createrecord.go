@@ -21,10 +21,12 @@ func (p *Provider) createRecord(ctx context.Context, client *http.Client,
 		FieldType string `json:"fieldType"`
 		SubDomain string `json:"subDomain"`
 		Target    string `json:"target"`
+		TTL       uint32 `json:"ttl,omitempty"`
 	}{
 		FieldType: recordType,
 		SubDomain: subdomain,
 		Target:    ipStr,
+		TTL:       p.ttl,
 	}
 	bodyBytes, err := json.Marshal(postRecordsParams)
 	if err != nil {
@@ -19,23 +19,25 @@ import (
 )
 
 type Provider struct {
-	domain      string
-	owner       string
-	ipVersion   ipversion.IPVersion
-	ipv6Suffix  netip.Prefix
-	username    string
-	password    string
-	mode        string
-	apiURL      *url.URL
-	appKey      string
-	appSecret   string
-	consumerKey string
-	timeNow     func() time.Time
-	serverDelta time.Duration
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	username        string
+	password        string
+	mode            string
+	apiURL          *url.URL
+	appKey          string
+	appSecret       string
+	consumerKey     string
+	timeNow         func() time.Time
+	serverDelta     time.Duration
+	createIfMissing bool
+	ttl             uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
@@ -58,29 +60,32 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	err = validateSettings(domain, extraSettings.Mode, owner, extraSettings.AppKey,
-		extraSettings.ConsumerKey, extraSettings.AppSecret, extraSettings.Username, extraSettings.Password)
+		extraSettings.ConsumerKey, extraSettings.AppSecret, extraSettings.Username,
+		extraSettings.Password, ttl)
 	if err != nil {
 		return nil, fmt.Errorf("validating provider specific settings: %w", err)
 	}
 
 	return &Provider{
-		domain:      domain,
-		owner:       owner,
-		ipVersion:   ipVersion,
-		ipv6Suffix:  ipv6Suffix,
-		username:    extraSettings.Username,
-		password:    extraSettings.Password,
-		mode:        extraSettings.Mode,
-		apiURL:      apiURL,
-		appKey:      extraSettings.AppKey,
-		appSecret:   extraSettings.AppSecret,
-		consumerKey: extraSettings.ConsumerKey,
-		timeNow:     time.Now,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		username:        extraSettings.Username,
+		password:        extraSettings.Password,
+		mode:            extraSettings.Mode,
+		apiURL:          apiURL,
+		appKey:          extraSettings.AppKey,
+		appSecret:       extraSettings.AppSecret,
+		consumerKey:     extraSettings.ConsumerKey,
+		timeNow:         time.Now,
+		createIfMissing: createIfMissing,
+		ttl:             ttl,
 	}, nil
 }
 
 func validateSettings(domain, mode, owner, appKey, consumerKey,
-	appSecret, username, password string,
+	appSecret, username, password string, ttl uint32,
 ) (err error) {
 	err = utils.CheckDomain(domain)
 	if err != nil {
@@ -104,6 +109,8 @@ func validateSettings(domain, mode, owner, appKey, consumerKey,
 			return fmt.Errorf("%w", errors.ErrPasswordNotSet)
 		case owner == "*":
 			return fmt.Errorf("%w", errors.ErrOwnerWildcard)
+		case ttl != 0:
+			return fmt.Errorf("%w: for the dynhost mode", errors.ErrTTLNotSupported)
 		}
 	}
 	return nil
@@ -223,6 +230,9 @@ func (p *Provider) updateWithZoneDNS(ctx context.Context, client *http.Client, i
 	}
 
 	if len(recordIDs) == 0 {
+		if !p.createIfMissing {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
 		err = p.createRecord(ctx, client, recordType, subDomain, ipStr, timestamp)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("creating record: %w", err)
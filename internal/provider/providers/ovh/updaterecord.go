@@ -19,8 +19,10 @@ func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
 	}
 	putRecordsParams := struct {
 		Target string `json:"target"`
+		TTL    uint32 `json:"ttl,omitempty"`
 	}{
 		Target: ipStr,
+		TTL:    p.ttl,
 	}
 	bodyBytes, err := json.Marshal(putRecordsParams)
 	if err != nil {
@@ -17,23 +17,23 @@ import (
 )
 
 type Provider struct {
-	domain       string
-	owner        string
-	ipVersion    ipversion.IPVersion
-	ipv6Suffix   netip.Prefix
-	ttl          uint32
-	apiKey       string
-	secretAPIKey string
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	ttl             uint32
+	apiKey          string
+	secretAPIKey    string
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
 		SecretAPIKey string `json:"secret_api_key"`
 		APIKey       string `json:"api_key"`
-		TTL          uint32 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -46,13 +46,14 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:       domain,
-		owner:        owner,
-		ipVersion:    ipVersion,
-		ipv6Suffix:   ipv6Suffix,
-		secretAPIKey: extraSettings.SecretAPIKey,
-		apiKey:       extraSettings.APIKey,
-		ttl:          extraSettings.TTL,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		secretAPIKey:    extraSettings.SecretAPIKey,
+		apiKey:          extraSettings.APIKey,
+		ttl:             ttl,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -128,6 +129,9 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	}
 
 	if len(records) == 0 {
+		if !p.createIfMissing {
+			return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+		}
 		err = p.deleteDefaultConflictingRecordsIfNeeded(ctx, client)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("deleting default conflicting records: %w", err)
@@ -5,6 +5,7 @@ import (
 	"net/netip"
 
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
 )
 
 // See https://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html#API_ChangeResourceRecordSets_RequestSyntax
@@ -47,6 +48,13 @@ type xmlChangeInfo struct {
 	SubmittedAt string `xml:"SubmittedAt"`
 }
 
+// See https://docs.aws.amazon.com/Route53/latest/APIReference/API_ListResourceRecordSets.html#API_ListResourceRecordSets_ResponseSyntax
+type listResourceRecordSetsResponse struct {
+	XMLNS              string              `xml:"xmlns,attr"`
+	XMLName            xml.Name            `xml:"ListResourceRecordSetsResponse"`
+	ResourceRecordSets []resourceRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+}
+
 // See https://docs.aws.amazon.com/Route53/latest/APIReference/requests-rest-responses.html
 type errorResponse struct {
 	XMLNS     string   `xml:"xmlns,attr"`
@@ -61,26 +69,101 @@ type xmlError struct {
 	Message string `xml:"Message"`
 }
 
-func newChangeRRSetRequest(name string, ttl uint32, ip netip.Addr) changeResourceRecordSetsRequest {
-	recordType := constants.A
-	if ip.Is6() {
-		recordType = constants.AAAA
+const xmlNamespace = "https://route53.amazonaws.com/doc/2013-04-01/"
+
+func newChangeRRSetRequest(name string, ttl uint32, ips ...netip.Addr) changeResourceRecordSetsRequest {
+	changes := make([]change, len(ips))
+	for i, ip := range ips {
+		changes[i] = newUpsertChange(name, ttl, ip)
+	}
+
+	return changeResourceRecordSetsRequest{
+		XMLNS: xmlNamespace,
+		ChangeBatch: changeBatch{
+			Changes: changes,
+		},
+	}
+}
+
+func newBatchChangeRRSetRequest(names []string, ttl uint32, ip netip.Addr) changeResourceRecordSetsRequest {
+	changes := make([]change, len(names))
+	for i, name := range names {
+		changes[i] = newUpsertChange(name, ttl, ip)
 	}
 
 	return changeResourceRecordSetsRequest{
-		XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+		XMLNS: xmlNamespace,
+		ChangeBatch: changeBatch{
+			Changes: changes,
+		},
+	}
+}
+
+func newMultiChangeRRSetRequest(name string, ttl uint32, ips []netip.Addr) changeResourceRecordSetsRequest {
+	recordSets := utils.GroupRecordSets(ips)
+	changes := make([]change, len(recordSets))
+	for i, recordSet := range recordSets {
+		resourceRecords := make([]resourceRecord, len(recordSet.IPs))
+		for j, ip := range recordSet.IPs {
+			resourceRecords[j] = resourceRecord{Value: ip.String()}
+		}
+		changes[i] = change{
+			Action: "UPSERT",
+			ResourceRecordSet: resourceRecordSet{
+				Name:            name,
+				Type:            recordSet.Type,
+				TTL:             ttl,
+				ResourceRecords: resourceRecords,
+			},
+		}
+	}
+
+	return changeResourceRecordSetsRequest{
+		XMLNS: xmlNamespace,
+		ChangeBatch: changeBatch{
+			Changes: changes,
+		},
+	}
+}
+
+func newContentChangeRRSetRequest(name string, ttl uint32, recordType string,
+	values []string,
+) changeResourceRecordSetsRequest {
+	resourceRecords := make([]resourceRecord, len(values))
+	for i, value := range values {
+		resourceRecords[i] = resourceRecord{Value: value}
+	}
+
+	return changeResourceRecordSetsRequest{
+		XMLNS: xmlNamespace,
 		ChangeBatch: changeBatch{
 			Changes: []change{{
 				Action: "UPSERT",
 				ResourceRecordSet: resourceRecordSet{
-					Name: name,
-					Type: recordType,
-					TTL:  ttl,
-					ResourceRecords: []resourceRecord{{
-						Value: ip.String(),
-					}},
+					Name:            name,
+					Type:            recordType,
+					TTL:             ttl,
+					ResourceRecords: resourceRecords,
 				},
 			}},
 		},
 	}
 }
+
+func newUpsertChange(name string, ttl uint32, ip netip.Addr) change {
+	recordType := constants.A
+	if ip.Is6() {
+		recordType = constants.AAAA
+	}
+	return change{
+		Action: "UPSERT",
+		ResourceRecordSet: resourceRecordSet{
+			Name: name,
+			Type: recordType,
+			TTL:  ttl,
+			ResourceRecords: []resourceRecord{{
+				Value: ip.String(),
+			}},
+		},
+	}
+}
@@ -69,6 +69,36 @@ func Test_changeResourceRecordSetsResponse_XML_Decode(t *testing.T) {
 	assert.Equal(t, expectedObject, parsed)
 }
 
+func Test_listResourceRecordSetsResponse_XML_Decode(t *testing.T) {
+	t.Parallel()
+
+	const response = `<?xml version="1.0"?>` +
+		`<ListResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">` +
+		`<ResourceRecordSets><ResourceRecordSet><Name>test.com.</Name><Type>A</Type><TTL>300</TTL>` +
+		`<ResourceRecords><ResourceRecord><Value>127.0.0.1</Value></ResourceRecord>` +
+		`</ResourceRecords></ResourceRecordSet></ResourceRecordSets>` +
+		`<IsTruncated>false</IsTruncated><MaxItems>1</MaxItems></ListResourceRecordSetsResponse>
+`
+
+	var parsed listResourceRecordSetsResponse
+	err := xml.Unmarshal([]byte(response), &parsed)
+	require.NoError(t, err)
+	expectedObject := listResourceRecordSetsResponse{
+		XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+		XMLName: xml.Name{
+			Space: "https://route53.amazonaws.com/doc/2013-04-01/",
+			Local: "ListResourceRecordSetsResponse",
+		},
+		ResourceRecordSets: []resourceRecordSet{{
+			Name:            "test.com.",
+			Type:            "A",
+			TTL:             300,
+			ResourceRecords: []resourceRecord{{Value: "127.0.0.1"}},
+		}},
+	}
+	assert.Equal(t, expectedObject, parsed)
+}
+
 func Test_errorResponse_XML_Decode(t *testing.T) {
 	t.Parallel()
 
@@ -104,13 +134,13 @@ func Test_newChangeRRSetRequest(t *testing.T) {
 	testCases := map[string]struct {
 		name     string
 		ttl      uint32
-		ip       netip.Addr
+		ips      []netip.Addr
 		expected changeResourceRecordSetsRequest
 	}{
 		"ipv4": {
 			name: "test.com",
 			ttl:  300,
-			ip:   netip.MustParseAddr("127.0.0.1"),
+			ips:  []netip.Addr{netip.MustParseAddr("127.0.0.1")},
 			expected: changeResourceRecordSetsRequest{
 				XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
 				ChangeBatch: changeBatch{
@@ -129,11 +159,38 @@ func Test_newChangeRRSetRequest(t *testing.T) {
 		"ipv6": {
 			name: "test.com",
 			ttl:  300,
-			ip:   netip.MustParseAddr("::1"),
+			ips:  []netip.Addr{netip.MustParseAddr("::1")},
+			expected: changeResourceRecordSetsRequest{
+				XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+				ChangeBatch: changeBatch{
+					Changes: []change{{
+						Action: "UPSERT",
+						ResourceRecordSet: resourceRecordSet{
+							Name:            "test.com",
+							Type:            "AAAA",
+							TTL:             300,
+							ResourceRecords: []resourceRecord{{Value: "::1"}},
+						},
+					}},
+				},
+			},
+		},
+		"dual_stack": {
+			name: "test.com",
+			ttl:  300,
+			ips:  []netip.Addr{netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("::1")},
 			expected: changeResourceRecordSetsRequest{
 				XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
 				ChangeBatch: changeBatch{
 					Changes: []change{{
+						Action: "UPSERT",
+						ResourceRecordSet: resourceRecordSet{
+							Name:            "test.com",
+							Type:            "A",
+							TTL:             300,
+							ResourceRecords: []resourceRecord{{Value: "127.0.0.1"}},
+						},
+					}, {
 						Action: "UPSERT",
 						ResourceRecordSet: resourceRecordSet{
 							Name:            "test.com",
@@ -151,8 +208,75 @@ func Test_newChangeRRSetRequest(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			actual := newChangeRRSetRequest(testCase.name, testCase.ttl, testCase.ip)
+			actual := newChangeRRSetRequest(testCase.name, testCase.ttl, testCase.ips...)
 			assert.Equal(t, testCase.expected, actual)
 		})
 	}
 }
+
+func Test_newBatchChangeRRSetRequest(t *testing.T) {
+	t.Parallel()
+
+	actual := newBatchChangeRRSetRequest([]string{"a.test.com", "b.test.com"},
+		300, netip.MustParseAddr("127.0.0.1"))
+
+	expected := changeResourceRecordSetsRequest{
+		XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+		ChangeBatch: changeBatch{
+			Changes: []change{{
+				Action: "UPSERT",
+				ResourceRecordSet: resourceRecordSet{
+					Name:            "a.test.com",
+					Type:            "A",
+					TTL:             300,
+					ResourceRecords: []resourceRecord{{Value: "127.0.0.1"}},
+				},
+			}, {
+				Action: "UPSERT",
+				ResourceRecordSet: resourceRecordSet{
+					Name:            "b.test.com",
+					Type:            "A",
+					TTL:             300,
+					ResourceRecords: []resourceRecord{{Value: "127.0.0.1"}},
+				},
+			}},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func Test_newMultiChangeRRSetRequest(t *testing.T) {
+	t.Parallel()
+
+	ips := []netip.Addr{
+		netip.MustParseAddr("1.1.1.1"),
+		netip.MustParseAddr("::1"),
+		netip.MustParseAddr("2.2.2.2"),
+	}
+
+	actual := newMultiChangeRRSetRequest("test.com", 300, ips)
+
+	expected := changeResourceRecordSetsRequest{
+		XMLNS: "https://route53.amazonaws.com/doc/2013-04-01/",
+		ChangeBatch: changeBatch{
+			Changes: []change{{
+				Action: "UPSERT",
+				ResourceRecordSet: resourceRecordSet{
+					Name:            "test.com",
+					Type:            "A",
+					TTL:             300,
+					ResourceRecords: []resourceRecord{{Value: "1.1.1.1"}, {Value: "2.2.2.2"}},
+				},
+			}, {
+				Action: "UPSERT",
+				ResourceRecordSet: resourceRecordSet{
+					Name:            "test.com",
+					Type:            "AAAA",
+					TTL:             300,
+					ResourceRecords: []resourceRecord{{Value: "::1"}},
+				},
+			}},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,54 @@
+package route53
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// UpdateContent sets the value of the record set of the given type
+// matching the content given, keeping the other values of the record set.
+// See https://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html
+func (p *Provider) UpdateContent(ctx context.Context, client *http.Client,
+	recordType, content string,
+) (err error) {
+	recordSet, err := p.getRecordSet(ctx, client, recordType)
+	if err != nil {
+		return fmt.Errorf("getting %s record set: %w", recordType, err)
+	}
+
+	var values, contents []string
+	if recordSet != nil {
+		values = make([]string, len(recordSet.ResourceRecords))
+		contents = make([]string, len(recordSet.ResourceRecords))
+		for i, record := range recordSet.ResourceRecords {
+			values[i] = record.Value
+			contents[i] = record.Value
+			if utils.IsTXTType(recordType) {
+				contents[i] = utils.UnquoteTXT(record.Value)
+			}
+		}
+	}
+
+	value := content
+	if utils.IsTXTType(recordType) {
+		value = utils.QuoteTXT(content)
+	}
+	index, err := utils.SelectContentRecord(contents, content)
+	switch {
+	case err != nil:
+		return err
+	case index == -1:
+		values = append(values, value)
+	case contents[index] == content:
+		return nil
+	default:
+		values[index] = value
+	}
+
+	changeRRSetRequest := newContentChangeRRSetRequest(utils.BuildURLQueryHostname(p.owner, p.domain),
+		p.ttl, recordType, values)
+	return p.changeRecordSets(ctx, client, changeRRSetRequest)
+}
@@ -0,0 +1,34 @@
+package route53
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// DeleteRecord deletes the record set of the given type for the owner and
+// domain. Route53 requires the current values of the record set to delete it,
+// so the record set is read first.
+func (p *Provider) DeleteRecord(ctx context.Context, client *http.Client, recordType string) (err error) {
+	recordSet, err := p.getRecordSet(ctx, client, recordType)
+	if err != nil {
+		return fmt.Errorf("getting %s record set: %w", recordType, err)
+	} else if recordSet == nil {
+		return nil
+	}
+
+	request := changeResourceRecordSetsRequest{
+		XMLNS: xmlNamespace,
+		ChangeBatch: changeBatch{
+			Changes: []change{{
+				Action:            "DELETE",
+				ResourceRecordSet: *recordSet,
+			}},
+		},
+	}
+	err = p.changeRecordSets(ctx, client, request)
+	if err != nil {
+		return fmt.Errorf("deleting %s record set: %w", recordType, err)
+	}
+	return nil
+}
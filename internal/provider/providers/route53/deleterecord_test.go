@@ -0,0 +1,57 @@
+package route53
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Provider_DeleteRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		listBody   string
+		changeBody string
+	}{
+		"record_set_deleted": {
+			listBody: makeListResponse("sub.example.com.", "AAAA", "::1"),
+			changeBody: `<ChangeResourceRecordSetsRequest xmlns="https://route53.amazonaws.com/doc/2013-04-01/">` +
+				`<ChangeBatch><Changes><Change><Action>DELETE</Action>` +
+				`<ResourceRecordSet><Name>sub.example.com.</Name><Type>AAAA</Type><TTL>300</TTL>` +
+				`<ResourceRecords><ResourceRecord><Value>::1</Value></ResourceRecord>` +
+				`</ResourceRecords></ResourceRecordSet></Change></Changes>` +
+				`</ChangeBatch></ChangeResourceRecordSetsRequest>`,
+		},
+		"no_record_set": {
+			listBody: makeListResponse("tub.example.com.", "AAAA", "::1"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var changeBody string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/2013-04-01/hostedzone/zone/rrset", r.URL.Path)
+				if r.Method == http.MethodGet {
+					_, _ = io.WriteString(w, testCase.listBody)
+					return
+				}
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				changeBody = string(body)
+			})
+			provider := newTestProvider(ipversion.IP6)
+
+			err := provider.DeleteRecord(context.Background(), client, "AAAA")
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.changeBody, changeBody)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package route53
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+)
+
+// GetRecord returns the IP addresses currently set for the record.
+func (p *Provider) GetRecord(ctx context.Context, client *http.Client) (ips []netip.Addr, err error) {
+	for _, recordType := range utils.RecordTypes(p.ipVersion) {
+		recordIPs, err := p.listRecordIPs(ctx, client, recordType)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s record set: %w", recordType, err)
+		}
+		ips = append(ips, recordIPs...)
+	}
+	return ips, nil
+}
+
+func (p *Provider) listRecordIPs(ctx context.Context, client *http.Client,
+	recordType string,
+) (ips []netip.Addr, err error) {
+	recordSet, err := p.getRecordSet(ctx, client, recordType)
+	if err != nil {
+		return nil, err
+	} else if recordSet == nil {
+		return nil, nil
+	}
+
+	ips = make([]netip.Addr, len(recordSet.ResourceRecords))
+	for i, record := range recordSet.ResourceRecords {
+		ips[i], err = netip.ParseAddr(record.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+		}
+	}
+	return ips, nil
+}
+
+// getRecordSet returns the record set of the given type for the owner
+// and domain, or nil if it does not exist.
+// See https://docs.aws.amazon.com/Route53/latest/APIReference/API_ListResourceRecordSets.html
+func (p *Provider) getRecordSet(ctx context.Context, client *http.Client,
+	recordType string,
+) (recordSet *resourceRecordSet, err error) {
+	name := utils.BuildURLQueryHostname(p.owner, p.domain)
+	values := url.Values{}
+	values.Set("name", name)
+	values.Set("type", recordType)
+	values.Set("maxitems", "1")
+	u := url.URL{
+		Scheme:   "https",
+		Host:     route53Domain,
+		Path:     "/2013-04-01/hostedzone/" + p.zoneID + "/rrset",
+		RawQuery: values.Encode(),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	p.setHeaders(request, nil)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	xmlDecoder := xml.NewDecoder(response.Body)
+	if response.StatusCode != http.StatusOK {
+		return nil, decodeErrorResponse(xmlDecoder, response.StatusCode)
+	}
+
+	var listResponse listResourceRecordSetsResponse
+	err = xmlDecoder.Decode(&listResponse)
+	if err != nil {
+		return nil, fmt.Errorf("XML decoding response body: %w", err)
+	}
+
+	// Record sets are listed starting from the name and type given,
+	// so the first record set returned may belong to another record.
+	for _, recordSet := range listResponse.ResourceRecordSets {
+		if strings.TrimSuffix(recordSet.Name, ".") == name && recordSet.Type == recordType {
+			return &recordSet, nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
@@ -0,0 +1,146 @@
+package route53
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+// rewriteTransport redirects all requests (to route53.amazonaws.com) to the
+// test server, without having to make the provider code test-aware.
+type rewriteTransport struct {
+	host string
+	base http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.URL.Scheme = "http"
+	request.URL.Host = t.host
+	return t.base.RoundTrip(request)
+}
+
+// newTestClient returns an HTTP client sending all its requests to
+// a test server running the handler given.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &http.Client{Transport: rewriteTransport{
+		host: strings.TrimPrefix(server.URL, "http://"),
+		base: http.DefaultTransport,
+	}}
+}
+
+func newTestProvider(ipVersion ipversion.IPVersion) *Provider {
+	return &Provider{
+		domain:    "example.com",
+		owner:     "sub",
+		ipVersion: ipVersion,
+		zoneID:    "zone",
+		ttl:       300,
+		signer: &signer{
+			accessKey:        "access",
+			secretkey:        "secret",
+			region:           "us-east-1",
+			service:          "route53",
+			signatureVersion: "aws4_request",
+		},
+	}
+}
+
+// makeListResponse returns a list resource record sets XML response
+// for the record set of the name, type and values given.
+func makeListResponse(name, recordType string, values ...string) string {
+	var records strings.Builder
+	for _, value := range values {
+		records.WriteString("<ResourceRecord><Value>" + value + "</Value></ResourceRecord>")
+	}
+	return `<?xml version="1.0"?>` +
+		`<ListResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">` +
+		`<ResourceRecordSets><ResourceRecordSet><Name>` + name + `</Name><Type>` + recordType +
+		`</Type><TTL>300</TTL><ResourceRecords>` + records.String() +
+		`</ResourceRecords></ResourceRecordSet></ResourceRecordSets>` +
+		`<IsTruncated>false</IsTruncated><MaxItems>1</MaxItems></ListResourceRecordSetsResponse>`
+}
+
+func Test_Provider_GetRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ipVersion  ipversion.IPVersion
+		statusCode int
+		bodies     map[string]string
+		ips        []netip.Addr
+		errWrapped error
+		errMessage string
+	}{
+		"dual_stack": {
+			ipVersion:  ipversion.IP4or6,
+			statusCode: http.StatusOK,
+			bodies: map[string]string{
+				"A":    makeListResponse("sub.example.com.", "A", "1.2.3.4", "5.6.7.8"),
+				"AAAA": makeListResponse("sub.example.com.", "AAAA", "::1"),
+			},
+			ips: []netip.Addr{
+				netip.MustParseAddr("1.2.3.4"),
+				netip.MustParseAddr("5.6.7.8"),
+				netip.MustParseAddr("::1"),
+			},
+		},
+		"other_record_listed": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			bodies:     map[string]string{"A": makeListResponse("tub.example.com.", "A", "1.2.3.4")},
+		},
+		"malformed_ip": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusOK,
+			bodies:     map[string]string{"A": makeListResponse("sub.example.com.", "A", "x")},
+			errWrapped: errors.ErrIPReceivedMalformed,
+			errMessage: `listing A record set: malformed IP address received: ` +
+				`ParseAddr("x"): unable to parse IP`,
+		},
+		"error_response": {
+			ipVersion:  ipversion.IP4,
+			statusCode: http.StatusForbidden,
+			bodies: map[string]string{"A": `<?xml version="1.0"?>` +
+				`<ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">` +
+				`<Error><Type>Sender</Type><Code>AccessDenied</Code><Message>denied</Message></Error>` +
+				`<RequestId>id</RequestId></ErrorResponse>`},
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "listing A record set: HTTP status is not valid: 403: " +
+				"request id Sender/AccessDenied: denied",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/2013-04-01/hostedzone/zone/rrset", r.URL.Path)
+				assert.Equal(t, "sub.example.com", r.URL.Query().Get("name"))
+				assert.NotEmpty(t, r.Header.Get("Authorization"))
+				w.WriteHeader(testCase.statusCode)
+				_, _ = io.WriteString(w, testCase.bodies[r.URL.Query().Get("type")])
+			})
+			provider := newTestProvider(testCase.ipVersion)
+
+			ips, err := provider.GetRecord(context.Background(), client)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ips, ips)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/netip"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
@@ -30,14 +31,13 @@ type Provider struct {
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, ttl uint32) (
 	provider *Provider, err error,
 ) {
 	var providerSpecificSettings struct {
-		AccessKey string  `json:"access_key"`
-		SecretKey string  `json:"secret_key"`
-		ZoneID    string  `json:"zone_id"`
-		TTL       *uint32 `json:"ttl,omitempty"`
+		AccessKey string `json:"access_key"`
+		SecretKey string `json:"secret_key"`
+		ZoneID    string `json:"zone_id"`
 	}
 	err = json.Unmarshal(data, &providerSpecificSettings)
 	if err != nil {
@@ -45,9 +45,8 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	const defaultTTL = 300
-	ttl := uint32(defaultTTL)
-	if providerSpecificSettings.TTL != nil {
-		ttl = *providerSpecificSettings.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
 	}
 
 	err = validateSettings(domain, providerSpecificSettings.AccessKey,
@@ -137,47 +136,127 @@ func (p *Provider) HTML() models.HTMLRow {
 // Update updates the IP address for the provider.
 // See https://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	changeRRSetRequest := newChangeRRSetRequest(utils.BuildURLQueryHostname(p.owner, p.domain), p.ttl, ip)
+	err = p.changeRecordSets(ctx, client, changeRRSetRequest)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return ip, nil
+}
+
+// UpdateDualStack updates both the A and AAAA record sets in a single
+// change batch, which Route53 applies atomically.
+func (p *Provider) UpdateDualStack(ctx context.Context, client *http.Client,
+	ipv4, ipv6 netip.Addr,
+) (newIPv4, newIPv6 netip.Addr, err error) {
+	changeRRSetRequest := newChangeRRSetRequest(utils.BuildURLQueryHostname(p.owner, p.domain),
+		p.ttl, ipv4, ipv6)
+	err = p.changeRecordSets(ctx, client, changeRRSetRequest)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, err
+	}
+	return ipv4, ipv6, nil
+}
+
+// UpdateMulti sets the A and AAAA record sets to the IPv4 and IPv6
+// addresses given in a single change batch. The record set of an IP
+// family of the record IP version is deleted in the same change batch
+// if no IP address of this family is given.
+func (p *Provider) UpdateMulti(ctx context.Context, client *http.Client,
+	ips []netip.Addr,
+) (newIPs []netip.Addr, err error) {
+	changeRRSetRequest := newMultiChangeRRSetRequest(utils.BuildURLQueryHostname(p.owner, p.domain),
+		p.ttl, ips)
+	for _, recordSet := range utils.GroupManagedRecordSets(p.ipVersion, ips) {
+		if len(recordSet.IPs) > 0 {
+			continue
+		}
+		// Route53 requires the current values of the record set to delete it.
+		existing, err := p.getRecordSet(ctx, client, recordSet.Type)
+		if err != nil {
+			return nil, fmt.Errorf("getting %s record set: %w", recordSet.Type, err)
+		} else if existing == nil {
+			continue
+		}
+		changeRRSetRequest.ChangeBatch.Changes = append(changeRRSetRequest.ChangeBatch.Changes,
+			change{Action: "DELETE", ResourceRecordSet: *existing})
+	}
+	if len(changeRRSetRequest.ChangeBatch.Changes) == 0 {
+		return ips, nil
+	}
+	err = p.changeRecordSets(ctx, client, changeRRSetRequest)
+	if err != nil {
+		return nil, err
+	}
+	return ips, nil
+}
+
+// BatchKey returns the hosted zone identifier and TTL of the record,
+// since records of the same hosted zone and TTL can be updated in a
+// single change batch.
+func (p *Provider) BatchKey() string {
+	return p.zoneID + "/" + strconv.FormatUint(uint64(p.ttl), 10)
+}
+
+// UpdateBatch updates the record sets with the hostnames given to the
+// IP address given in a single change batch, which Route53 applies
+// atomically.
+func (p *Provider) UpdateBatch(ctx context.Context, client *http.Client,
+	hostnames []string, ip netip.Addr,
+) (errs []error, err error) {
+	changeRRSetRequest := newBatchChangeRRSetRequest(hostnames, p.ttl, ip)
+	err = p.changeRecordSets(ctx, client, changeRRSetRequest)
+	if err != nil {
+		return nil, err
+	}
+	return make([]error, len(hostnames)), nil
+}
+
+// See https://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html
+func (p *Provider) changeRecordSets(ctx context.Context, client *http.Client,
+	changeRRSetRequest changeResourceRecordSetsRequest,
+) (err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   route53Domain,
 		Path:   "/2013-04-01/hostedzone/" + p.zoneID + "/rrset",
 	}
 
-	changeRRSetRequest := newChangeRRSetRequest(utils.BuildURLQueryHostname(p.owner, p.domain), p.ttl, ip)
-
 	// Note the AWS API does not accept JSON for this endpoint
 	buffer := bytes.NewBuffer(nil)
 	encoder := xml.NewEncoder(buffer)
 	err = encoder.Encode(changeRRSetRequest)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("XML encoding change RRSet request: %w", err)
+		return fmt.Errorf("XML encoding change RRSet request: %w", err)
 	}
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("creating http request: %w", err)
+		return fmt.Errorf("creating http request: %w", err)
 	}
 
 	p.setHeaders(request, buffer.Bytes())
 
 	response, err := client.Do(request)
 	if err != nil {
-		return netip.Addr{}, err
+		return err
 	}
 	defer response.Body.Close()
 
-	xmlDecoder := xml.NewDecoder(response.Body)
 	if response.StatusCode == http.StatusOK {
-		return ip, nil
+		return nil
 	}
+	return decodeErrorResponse(xml.NewDecoder(response.Body), response.StatusCode)
+}
 
+func decodeErrorResponse(xmlDecoder *xml.Decoder, statusCode int) (err error) {
 	var errorResponse errorResponse
 	err = xmlDecoder.Decode(&errorResponse)
 	if err != nil {
-		return netip.Addr{}, fmt.Errorf("XML decoding response body: %w", err)
+		return fmt.Errorf("XML decoding response body: %w", err)
 	}
-	return netip.Addr{}, fmt.Errorf("%w: %d: request %s %s/%s: %s",
-		errors.ErrHTTPStatusNotValid, response.StatusCode,
+	return fmt.Errorf("%w: %d: request %s %s/%s: %s",
+		errors.ErrHTTPStatusNotValid, statusCode,
 		errorResponse.RequestID, errorResponse.Error.Type,
 		errorResponse.Error.Code, errorResponse.Error.Message)
 }
@@ -189,6 +268,6 @@ func (p *Provider) setHeaders(request *http.Request, payload []byte) {
 	headers.SetAccept(request, "application/xml")
 	request.Header.Set("Date", now.Format(dateTimeFormat))
 	request.Header.Set("Host", route53Domain)
-	signature := p.signer.sign(request.Method, request.URL.Path, payload, now)
+	signature := p.signer.sign(request.Method, request.URL.Path, request.URL.RawQuery, payload, now)
 	request.Header.Set("Authorization", signature)
 }
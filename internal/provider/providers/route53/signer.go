@@ -25,14 +25,14 @@ type signer struct {
 	signatureVersion string
 }
 
-func (s *signer) sign(method, urlPath string, payload []byte, date time.Time) (
+func (s *signer) sign(method, urlPath, canonicalQuery string, payload []byte, date time.Time) (
 	headerValue string,
 ) {
 	credentialScope := fmt.Sprintf("%s/%s/%s/%s", date.Format(dateFormat),
 		s.region, s.service, s.signatureVersion)
 	credential := fmt.Sprintf("%s/%s", s.accessKey, credentialScope)
 	const signedHeaders = "content-type;host"
-	canonicalRequest := buildCanonicalRequest(method, urlPath, signedHeaders, payload)
+	canonicalRequest := buildCanonicalRequest(method, urlPath, canonicalQuery, signedHeaders, payload)
 	stringToSign := buildStringToSign(date, canonicalRequest, credentialScope)
 	signingKey := s.buildPrivateKey(date)
 	signature := hmacSha256Sum([]byte(signingKey), []byte(stringToSign))
@@ -41,11 +41,12 @@ func (s *signer) sign(method, urlPath string, payload []byte, date time.Time) (
 		credential, signedHeaders, signatureString)
 }
 
-func buildCanonicalRequest(method, path, headers string, payload []byte) (
+// buildCanonicalRequest builds the canonical request, where canonicalQuery
+// must have its parameters sorted by name, as done by url.Values Encode.
+func buildCanonicalRequest(method, path, canonicalQuery, headers string, payload []byte) (
 	canonicalRequest string,
 ) {
 	canonicalHeaders := "content-type:application/xml\nhost:" + route53Domain + "\n"
-	const canonicalQuery = "" // no query arg used
 	payloadHashDigest := hex.EncodeToString(sha256Sum(payload))
 	canonicalRequest = strings.Join([]string{
 		strings.ToUpper(method),
@@ -24,7 +24,7 @@ func Test_signer_sign(t *testing.T) {
 	payload := []byte{1, 2, 3, 4, 5}
 	date := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
 
-	headerValue := signer.sign(method, urlPath, payload, date)
+	headerValue := signer.sign(method, urlPath, "", payload, date)
 
 	const expected = "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE" +
 		"/20210101/us-east-1/route53/aws4_request," +
@@ -41,7 +41,7 @@ func Test_buildCanonicalRequest(t *testing.T) {
 	const headers = "content-type;host"
 	payload := []byte{1, 2, 3, 4, 5}
 
-	canonicalRequest := buildCanonicalRequest(method, urlPath, headers, payload)
+	canonicalRequest := buildCanonicalRequest(method, urlPath, "", headers, payload)
 
 	const expected = "POST\n/2013-04-01/hostedzone/Z148QEXAMPLE8V/rrset\n\n" +
 		"content-type:application/xml\nhost:route53.amazonaws.com\n\n" +
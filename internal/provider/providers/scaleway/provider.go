@@ -24,16 +24,15 @@ type Provider struct {
 	ipVersion  ipversion.IPVersion
 	ipv6Suffix netip.Prefix
 	secretKey  string
-	ttl        uint16
+	ttl        uint32
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, ttl uint32) (
 	provider *Provider, err error,
 ) {
 	extraSettings := struct {
 		SecretKey string `json:"secret_key"`
-		TTL       uint16 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -51,7 +50,7 @@ func New(data json.RawMessage, domain, owner string,
 		ipVersion:  ipVersion,
 		ipv6Suffix: ipv6Suffix,
 		secretKey:  extraSettings.SecretKey,
-		ttl:        extraSettings.TTL,
+		ttl:        ttl,
 	}, nil
 }
 
@@ -117,7 +116,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 	type recordJSON struct {
 		Data string `json:"data"`
 		Name string `json:"name"`
-		TTL  uint16 `json:"ttl,omitempty"`
+		TTL  uint32 `json:"ttl,omitempty"`
 	}
 	type changeJSON struct {
 		Set struct {
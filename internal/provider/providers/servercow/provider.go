@@ -28,7 +28,7 @@ type Provider struct {
 	ttl        uint32
 }
 
-func New(data json.RawMessage, domain, owner string, ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+func New(data json.RawMessage, domain, owner string, ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, ttl uint32) (
 	p *Provider, err error,
 ) {
 	// retro compatibility
@@ -39,7 +39,6 @@ func New(data json.RawMessage, domain, owner string, ipVersion ipversion.IPVersi
 	extraSettings := struct {
 		Username string `json:"username"`
 		Password string `json:"password"`
-		TTL      uint32 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
@@ -58,7 +57,7 @@ func New(data json.RawMessage, domain, owner string, ipVersion ipversion.IPVersi
 		ipv6Suffix: ipv6Suffix,
 		username:   extraSettings.Username,
 		password:   extraSettings.Password,
-		ttl:        extraSettings.TTL,
+		ttl:        ttl,
 	}, nil
 }
 
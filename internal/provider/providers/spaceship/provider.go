@@ -16,42 +16,43 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	apiKey     string
-	apiSecret  string
-	ttl        uint32
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	apiKey          string
+	apiSecret       string
+	ttl             uint32
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	p *Provider, err error,
 ) {
 	extraSettings := struct {
 		APIKey    string `json:"api_key"`
 		APISecret string `json:"api_secret"`
-		TTL       uint32 `json:"ttl"`
 	}{}
 	err = json.Unmarshal(data, &extraSettings)
 	if err != nil {
 		return nil, err
 	}
 
-	err = validateSettings(domain, extraSettings.APIKey, extraSettings.APISecret, extraSettings.TTL)
+	err = validateSettings(domain, extraSettings.APIKey, extraSettings.APISecret, ttl)
 	if err != nil {
 		return nil, fmt.Errorf("validating provider specific settings: %w", err)
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		apiKey:     extraSettings.APIKey,
-		apiSecret:  extraSettings.APISecret,
-		ttl:        extraSettings.TTL,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		apiKey:          extraSettings.APIKey,
+		apiSecret:       extraSettings.APISecret,
+		ttl:             ttl,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
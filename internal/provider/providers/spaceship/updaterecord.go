@@ -7,6 +7,7 @@ import (
 	"net/netip"
 
 	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
 )
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
@@ -31,7 +32,9 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 		}
 	}
 
-	if found {
+	if !found && !p.createIfMissing {
+		return netip.Addr{}, fmt.Errorf("%w", errors.ErrRecordNotFound)
+	} else if found {
 		currentIP, err := netip.ParseAddr(existingRecord.Address)
 		if err != nil {
 			return netip.Addr{}, fmt.Errorf("parsing existing IP address: %w", err)
@@ -17,21 +17,21 @@ import (
 )
 
 type Provider struct {
-	domain     string
-	owner      string
-	ipVersion  ipversion.IPVersion
-	ipv6Suffix netip.Prefix
-	apiKey     string
-	ttl        uint32
+	domain          string
+	owner           string
+	ipVersion       ipversion.IPVersion
+	ipv6Suffix      netip.Prefix
+	apiKey          string
+	ttl             uint32
+	createIfMissing bool
 }
 
 func New(data json.RawMessage, domain, owner string,
-	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix, createIfMissing bool, ttl uint32) (
 	provider *Provider, err error,
 ) {
 	var providerSpecificSettings struct {
 		APIKey string `json:"apikey"`
-		TTL    uint32 `json:"ttl"`
 	}
 	err = json.Unmarshal(data, &providerSpecificSettings)
 	if err != nil {
@@ -44,12 +44,13 @@ func New(data json.RawMessage, domain, owner string,
 	}
 
 	return &Provider{
-		domain:     domain,
-		owner:      owner,
-		ipVersion:  ipVersion,
-		ipv6Suffix: ipv6Suffix,
-		apiKey:     providerSpecificSettings.APIKey,
-		ttl:        providerSpecificSettings.TTL,
+		domain:          domain,
+		owner:           owner,
+		ipVersion:       ipVersion,
+		ipv6Suffix:      ipv6Suffix,
+		apiKey:          providerSpecificSettings.APIKey,
+		ttl:             ttl,
+		createIfMissing: createIfMissing,
 	}, nil
 }
 
@@ -110,7 +111,7 @@ func (p *Provider) setHeaders(request *http.Request) {
 }
 
 // Update does the following:
-// 1. if there's no record, create it.
+// 1. if there's no record, create it if createIfMissing is set.
 // 2. if it exists and ip is different, update it.
 // 3. if it exists and ip is the same, do nothing.
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
@@ -121,7 +122,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Add
 
 	recordID, existingIP, err := p.getRecord(ctx, client, recordType)
 	if err != nil {
-		if !stderrors.Is(err, errors.ErrRecordNotFound) {
+		if !stderrors.Is(err, errors.ErrRecordNotFound) || !p.createIfMissing {
 			return netip.Addr{}, fmt.Errorf("error getting records for %s: %w", p.domain, err)
 		}
 		err = p.createRecord(ctx, client, ip)
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrJSONPathNotFound = errors.New("JSON path not found")
+
+// lookupJSONPath returns the value at the dot separated path given
+// in the decoded JSON data, where array elements are accessed with
+// their index, for example `result.records.0.content`.
+func lookupJSONPath(data any, path string) (value any, err error) {
+	value = data
+	for key := range strings.SplitSeq(path, ".") {
+		switch typed := value.(type) {
+		case map[string]any:
+			var ok bool
+			value, ok = typed[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: key %q in %s", ErrJSONPathNotFound, key, path)
+			}
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, fmt.Errorf("%w: index %q in %s", ErrJSONPathNotFound, key, path)
+			}
+			value = typed[index]
+		default:
+			return nil, fmt.Errorf("%w: %q is not an object or array in %s",
+				ErrJSONPathNotFound, key, path)
+		}
+	}
+	return value, nil
+}
+
+// jsonValueToString returns the string representation of a
+// decoded JSON value, as it would be written in JSON but
+// without quotes for strings.
+func jsonValueToString(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
@@ -0,0 +1,348 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/provider/headers"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain             string
+	owner              string
+	ipVersion          ipversion.IPVersion
+	ipv6Suffix         netip.Prefix
+	method             string
+	url                *template.Template
+	urlHostname        string
+	headers            map[string]*template.Template
+	body               *template.Template
+	contentType        string
+	username           string
+	password           string
+	token              string
+	successStatusCodes []int
+	successJSONPath    string
+	successJSONValue   string
+	ipJSONPath         string
+}
+
+func New(data json.RawMessage, domain, owner string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix) (
+	p *Provider, err error,
+) {
+	extraSettings := struct {
+		Method             string            `json:"method"`
+		URL                string            `json:"url"`
+		Headers            map[string]string `json:"headers"`
+		Body               string            `json:"body"`
+		ContentType        string            `json:"content_type"`
+		Username           string            `json:"username"`
+		Password           string            `json:"password"`
+		Token              string            `json:"token"`
+		SuccessStatusCodes []int             `json:"success_status_codes"`
+		SuccessJSONPath    string            `json:"success_json_path"`
+		SuccessJSONValue   *string           `json:"success_json_value"`
+		IPJSONPath         string            `json:"ip_json_path"`
+	}{}
+	err = json.Unmarshal(data, &extraSettings)
+	if err != nil {
+		return nil, fmt.Errorf("JSON decoding provider specific settings: %w", err)
+	}
+
+	if extraSettings.Method == "" {
+		extraSettings.Method = http.MethodPost
+	}
+	extraSettings.Method = strings.ToUpper(extraSettings.Method)
+	if extraSettings.ContentType == "" && extraSettings.Body != "" {
+		extraSettings.ContentType = "application/json"
+	}
+	successJSONValue := "true"
+	if extraSettings.SuccessJSONValue != nil {
+		successJSONValue = *extraSettings.SuccessJSONValue
+	}
+
+	err = validateSettings(domain, extraSettings.Method, extraSettings.URL,
+		extraSettings.Username, extraSettings.Token)
+	if err != nil {
+		return nil, fmt.Errorf("validating provider specific settings: %w", err)
+	}
+
+	urlTemplate, err := template.New("url").Parse(extraSettings.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL template: %w", err)
+	}
+	bodyTemplate, err := template.New("body").Parse(extraSettings.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing body template: %w", err)
+	}
+	headerTemplates := make(map[string]*template.Template, len(extraSettings.Headers))
+	for key, value := range extraSettings.Headers {
+		headerTemplates[key], err = template.New(key).Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing header %s template: %w", key, err)
+		}
+	}
+
+	parsedURL, _ := url.Parse(extraSettings.URL) // already validated
+
+	return &Provider{
+		domain:             domain,
+		owner:              owner,
+		ipVersion:          ipVersion,
+		ipv6Suffix:         ipv6Suffix,
+		method:             extraSettings.Method,
+		url:                urlTemplate,
+		urlHostname:        parsedURL.Hostname(),
+		headers:            headerTemplates,
+		body:               bodyTemplate,
+		contentType:        extraSettings.ContentType,
+		username:           extraSettings.Username,
+		password:           extraSettings.Password,
+		token:              extraSettings.Token,
+		successStatusCodes: extraSettings.SuccessStatusCodes,
+		successJSONPath:    extraSettings.SuccessJSONPath,
+		successJSONValue:   successJSONValue,
+		ipJSONPath:         extraSettings.IPJSONPath,
+	}, nil
+}
+
+func validateSettings(domain, method, rawURL, username, token string) (err error) {
+	err = utils.CheckDomain(domain)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errors.ErrDomainNotValid, err)
+	}
+
+	if rawURL == "" {
+		return fmt.Errorf("%w", errors.ErrURLNotSet)
+	}
+	// The URL may contain template actions, which are
+	// left as is when parsing the URL.
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parsing URL: %w", err)
+	}
+
+	methods := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch}
+	switch {
+	case parsedURL.Scheme != "https":
+		return fmt.Errorf("%w: %s", errors.ErrURLNotHTTPS, parsedURL.Scheme)
+	case !slices.Contains(methods, method):
+		return fmt.Errorf("%w: %s must be one of %s",
+			errors.ErrMethodNotValid, method, strings.Join(methods, ", "))
+	case username != "" && token != "":
+		return fmt.Errorf("%w: username and token cannot be both set",
+			errors.ErrCredentialsNotValid)
+	default:
+		return nil
+	}
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.owner, constants.Webhook, p.ipVersion)
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Owner() string {
+	return p.owner
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) IPv6Suffix() netip.Prefix {
+	return p.ipv6Suffix
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.owner, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain: fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName()),
+		Owner:  p.Owner(),
+		Provider: fmt.Sprintf("<a href=\"https://%s/\">%s: %s</a>",
+			p.urlHostname, constants.Webhook, p.urlHostname),
+		IPVersion: p.ipVersion.String(),
+	}
+}
+
+// templateData is the data available to the URL, headers and body templates.
+type templateData struct {
+	IP         string
+	IPVersion  string
+	RecordType string
+	Domain     string
+	Owner      string
+	FQDN       string
+}
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip netip.Addr) (newIP netip.Addr, err error) {
+	data := templateData{
+		IP:         ip.String(),
+		IPVersion:  ipversion.IP4.String(),
+		RecordType: constants.A,
+		Domain:     p.domain,
+		Owner:      p.owner,
+		FQDN:       p.BuildDomainName(),
+	}
+	if ip.Is6() {
+		data.IPVersion = ipversion.IP6.String()
+		data.RecordType = constants.AAAA
+	}
+
+	request, err := p.makeRequest(ctx, data)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("reading response body: %w", err)
+	}
+
+	err = p.checkStatusCode(response.StatusCode, b)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	if p.successJSONPath == "" && p.ipJSONPath == "" {
+		return ip, nil
+	}
+
+	var decoded any
+	err = json.Unmarshal(b, &decoded)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: json decoding response body: %w: %s",
+			errors.ErrUnknownResponse, err, utils.ToSingleLine(string(b)))
+	}
+
+	if p.successJSONPath != "" {
+		value, err := lookupJSONPath(decoded, p.successJSONPath)
+		if err != nil {
+			return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrUnknownResponse, err)
+		} else if jsonValueToString(value) != p.successJSONValue {
+			return netip.Addr{}, fmt.Errorf("%w: %s is %s instead of %s: %s",
+				errors.ErrUnsuccessful, p.successJSONPath, jsonValueToString(value),
+				p.successJSONValue, utils.ToSingleLine(string(b)))
+		}
+	}
+
+	if p.ipJSONPath == "" {
+		return ip, nil
+	}
+
+	value, err := lookupJSONPath(decoded, p.ipJSONPath)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrReceivedNoIP, err)
+	}
+	newIP, err = netip.ParseAddr(jsonValueToString(value))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", errors.ErrIPReceivedMalformed, err)
+	} else if newIP.Compare(ip) != 0 {
+		return netip.Addr{}, fmt.Errorf("%w: sent ip %s to update but received %s",
+			errors.ErrIPReceivedMismatch, ip, newIP)
+	}
+	return newIP, nil
+}
+
+func (p *Provider) makeRequest(ctx context.Context, data templateData) (
+	request *http.Request, err error,
+) {
+	urlString, err := renderTemplate(p.url, data)
+	if err != nil {
+		return nil, fmt.Errorf("rendering URL: %w", err)
+	}
+
+	body, err := renderTemplate(p.body, data)
+	if err != nil {
+		return nil, fmt.Errorf("rendering body: %w", err)
+	}
+	var bodyReader io.Reader
+	if body != "" {
+		bodyReader = strings.NewReader(body)
+	}
+
+	request, err = http.NewRequestWithContext(ctx, p.method, urlString, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	headers.SetUserAgent(request)
+	if p.contentType != "" {
+		headers.SetContentType(request, p.contentType)
+	}
+	switch {
+	case p.token != "":
+		headers.SetAuthBearer(request, p.token)
+	case p.username != "":
+		request.SetBasicAuth(p.username, p.password)
+	}
+
+	for key, headerTemplate := range p.headers {
+		value, err := renderTemplate(headerTemplate, data)
+		if err != nil {
+			return nil, fmt.Errorf("rendering header %s: %w", key, err)
+		}
+		request.Header.Set(key, value)
+	}
+
+	return request, nil
+}
+
+func renderTemplate(t *template.Template, data templateData) (s string, err error) {
+	buffer := bytes.NewBuffer(nil)
+	err = t.Execute(buffer, data)
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func (p *Provider) checkStatusCode(statusCode int, body []byte) (err error) {
+	success := statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+	if len(p.successStatusCodes) > 0 {
+		success = slices.Contains(p.successStatusCodes, statusCode)
+	}
+	if success {
+		return nil
+	}
+
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		err = errors.ErrAuth
+	case http.StatusTooManyRequests:
+		err = errors.ErrRateLimit
+	default:
+		err = errors.ErrHTTPStatusNotValid
+	}
+	return fmt.Errorf("%w: %d: %s", err, statusCode, utils.ToSingleLine(string(body)))
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings     string
+		statusCode   int
+		responseBody string
+		newIP        netip.Addr
+		errWrapped   error
+		errMessage   string
+	}{
+		"status_code_only": {
+			settings:   `{}`,
+			statusCode: http.StatusNoContent,
+			newIP:      netip.MustParseAddr("1.2.3.4"),
+		},
+		"json_success_and_ip": {
+			settings: `{"success_json_path": "success",
+				"ip_json_path": "result.records.0.content"}`,
+			statusCode:   http.StatusOK,
+			responseBody: `{"success": true, "result": {"records": [{"content": "1.2.3.4"}]}}`,
+			newIP:        netip.MustParseAddr("1.2.3.4"),
+		},
+		"json_unsuccessful": {
+			settings:     `{"success_json_path": "status", "success_json_value": "ok"}`,
+			statusCode:   http.StatusOK,
+			responseBody: `{"status": "error"}`,
+			errWrapped:   errors.ErrUnsuccessful,
+			errMessage:   `unsuccessful result: status is error instead of ok: {"status": "error"}`,
+		},
+		"ip_mismatch": {
+			settings:     `{"ip_json_path": "ip"}`,
+			statusCode:   http.StatusOK,
+			responseBody: `{"ip": "5.6.7.8"}`,
+			errWrapped:   errors.ErrIPReceivedMismatch,
+			errMessage: "mismatching IP address received: " +
+				"sent ip 1.2.3.4 to update but received 5.6.7.8",
+		},
+		"auth_error": {
+			settings:     `{}`,
+			statusCode:   http.StatusUnauthorized,
+			responseBody: "bad token",
+			errWrapped:   errors.ErrAuth,
+			errMessage:   "bad authentication: 401: bad token",
+		},
+		"custom_status_codes": {
+			settings:   `{"success_status_codes": [201]}`,
+			statusCode: http.StatusOK,
+			errWrapped: errors.ErrHTTPStatusNotValid,
+			errMessage: "HTTP status is not valid: 200: ",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/records/sub.example.com", r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				assert.Equal(t, "A", r.Header.Get("X-Record-Type"))
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, `{"name":"sub","content":"1.2.3.4"}`, string(body))
+				w.WriteHeader(testCase.statusCode)
+				_, _ = w.Write([]byte(testCase.responseBody))
+			}))
+			t.Cleanup(server.Close)
+
+			var settings map[string]any
+			err := json.Unmarshal([]byte(testCase.settings), &settings)
+			require.NoError(t, err)
+			settings["method"] = "put"
+			settings["url"] = server.URL + "/records/{{.FQDN}}"
+			settings["token"] = "secret"
+			settings["headers"] = map[string]string{"X-Record-Type": "{{.RecordType}}"}
+			settings["body"] = `{"name":"{{.Owner}}","content":"{{.IP}}"}`
+			data, err := json.Marshal(settings)
+			require.NoError(t, err)
+
+			provider, err := New(data, "example.com", "sub", ipversion.IP4, netip.Prefix{})
+			require.NoError(t, err)
+
+			newIP, err := provider.Update(context.Background(), server.Client(),
+				netip.MustParseAddr("1.2.3.4"))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.newIP, newIP)
+		})
+	}
+}
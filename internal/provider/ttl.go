@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+)
+
+// ttlSupporters are providers able to set the TTL of their record.
+var ttlSupporters = map[models.Provider]struct{}{ //nolint:gochecknoglobals
+	constants.Aliyun:       {},
+	constants.Bunny:        {},
+	constants.Cloudflare:   {},
+	constants.DigitalOcean: {},
+	constants.DNSPod:       {},
+	constants.Gandi:        {},
+	constants.GCP:          {},
+	constants.GoDaddy:      {},
+	constants.Hetzner:      {},
+	constants.HetznerCloud: {},
+	constants.Hostinger:    {},
+	constants.Ionos:        {},
+	constants.Linode:       {},
+	constants.LuaDNS:       {},
+	constants.NameCom:      {},
+	constants.NameSilo:     {},
+	constants.OVH:          {},
+	constants.Porkbun:      {},
+	constants.Route53:      {},
+	constants.Scaleway:     {},
+	constants.Servercow:    {},
+	constants.Spaceship:    {},
+	constants.Vercel:       {},
+	constants.Vultr:        {},
+}
+
+var ErrTTLNotSupported = errors.New("ttl is not supported")
+
+// resolveTTL returns the TTL to pass to the provider, which is 0 if
+// the optional ttl setting is unset or set to 0, in which case the
+// provider default is used. It returns an error if the provider
+// cannot set a TTL.
+func resolveTTL(providerName models.Provider, ttl *uint32) (resolved uint32, err error) {
+	if ttl == nil || *ttl == 0 {
+		return 0, nil
+	}
+
+	_, ok := ttlSupporters[providerName]
+	if !ok {
+		return 0, fmt.Errorf("%w: provider %s cannot set the record TTL",
+			ErrTTLNotSupported, providerName)
+	}
+	return *ttl, nil
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resolveTTL(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		providerName models.Provider
+		ttl          *uint32
+		resolved     uint32
+		errWrapped   error
+		errMessage   string
+	}{
+		"unset": {
+			providerName: constants.DuckDNS,
+		},
+		"supported": {
+			providerName: constants.DigitalOcean,
+			ttl:          ptrTo(uint32(60)),
+			resolved:     60,
+		},
+		"zero": {
+			providerName: constants.DuckDNS,
+			ttl:          ptrTo(uint32(0)),
+		},
+		"unsupported": {
+			providerName: constants.DuckDNS,
+			ttl:          ptrTo(uint32(60)),
+			errWrapped:   ErrTTLNotSupported,
+			errMessage:   "ttl is not supported: provider duckdns cannot set the record TTL",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resolved, err := resolveTTL(testCase.providerName, testCase.ttl)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.resolved, resolved)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+)
+
+// IsTXTType returns true if the record type given has text content,
+// which is the case for TXT and SPF records.
+func IsTXTType(recordType string) bool {
+	return recordType == constants.TXT || recordType == constants.SPF
+}
+
+// QuoteTXT returns the text given as a quoted character string
+// in the zone file presentation format, split in several character
+// strings if it is longer than 255 bytes.
+func QuoteTXT(text string) string {
+	const maxStringLength = 255
+	var quoted []string
+	for len(text) > maxStringLength {
+		quoted = append(quoted, strconv.Quote(text[:maxStringLength]))
+		text = text[maxStringLength:]
+	}
+	quoted = append(quoted, strconv.Quote(text))
+	return strings.Join(quoted, " ")
+}
+
+// UnquoteTXT returns the text of the quoted character strings given
+// in the zone file presentation format, or the content given as is
+// if it is not quoted.
+func UnquoteTXT(content string) string {
+	rest := strings.TrimSpace(content)
+	var text strings.Builder
+	for rest != "" {
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return content
+		}
+		unquoted, _ := strconv.Unquote(quoted)
+		text.WriteString(unquoted)
+		rest = strings.TrimSpace(rest[len(quoted):])
+	}
+	return text.String()
+}
+
+var ErrContentRecordAmbiguous = errors.New("record to update is ambiguous")
+
+// SelectContentRecord returns the index of the existing record content
+// to replace with the content given, which is the one starting with the
+// same first field as the content given, for example "v=spf1" for SPF
+// records or the priority for SVCB records. Other records of the same
+// type are left untouched. The index returned is -1 if no record
+// content matches.
+func SelectContentRecord(contents []string, content string) (index int, err error) {
+	firstField := getFirstField(content)
+	index = -1
+	for i, existing := range contents {
+		if getFirstField(existing) != firstField {
+			continue
+		} else if index != -1 {
+			return -1, fmt.Errorf("%w: several records start with %q",
+				ErrContentRecordAmbiguous, firstField)
+		}
+		index = i
+	}
+	return index, nil
+}
+
+func getFirstField(content string) string {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+var ErrSVCBContentMalformed = errors.New("SVCB content is malformed")
+
+// SplitSVCB splits the SVCB or HTTPS record content given in the zone
+// file presentation format into its priority, target name and
+// space separated parameters.
+func SplitSVCB(content string) (priority uint16, target, params string, err error) {
+	const minFields = 2
+	fields := strings.Fields(content)
+	if len(fields) < minFields {
+		return 0, "", "", fmt.Errorf("%w: %q must start with a priority and a target name",
+			ErrSVCBContentMalformed, content)
+	}
+	priority64, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("%w: priority: %w", ErrSVCBContentMalformed, err)
+	}
+	return uint16(priority64), fields[1], strings.Join(fields[minFields:], " "), nil
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_QuoteTXT(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		text   string
+		quoted string
+	}{
+		"empty": {
+			quoted: `""`,
+		},
+		"escaped": {
+			text:   `v=spf1 "x" -all`,
+			quoted: `"v=spf1 \"x\" -all"`,
+		},
+		"split": {
+			text:   strings.Repeat("a", 256),
+			quoted: `"` + strings.Repeat("a", 255) + `" "a"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			quoted := QuoteTXT(testCase.text)
+
+			assert.Equal(t, testCase.quoted, quoted)
+			assert.Equal(t, testCase.text, UnquoteTXT(quoted))
+		})
+	}
+}
+
+func Test_UnquoteTXT(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content string
+		text    string
+	}{
+		"not_quoted": {
+			content: "v=spf1 -all",
+			text:    "v=spf1 -all",
+		},
+		"quoted": {
+			content: `"v=spf1 -all"`,
+			text:    "v=spf1 -all",
+		},
+		"several_strings": {
+			content: `"v=spf1 " "-all"`,
+			text:    "v=spf1 -all",
+		},
+		"malformed": {
+			content: `"v=spf1 -all`,
+			text:    `"v=spf1 -all`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			text := UnquoteTXT(testCase.content)
+
+			assert.Equal(t, testCase.text, text)
+		})
+	}
+}
+
+func Test_SelectContentRecord(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents   []string
+		content    string
+		index      int
+		errWrapped error
+		errMessage string
+	}{
+		"no_record": {
+			content: "v=spf1 ip4:1.2.3.4 -all",
+			index:   -1,
+		},
+		"unrelated_record": {
+			contents: []string{"google-site-verification=abc"},
+			content:  "v=spf1 ip4:1.2.3.4 -all",
+			index:    -1,
+		},
+		"matching_record": {
+			contents: []string{"google-site-verification=abc", "v=spf1 ip4:5.6.7.8 -all"},
+			content:  "v=spf1 ip4:1.2.3.4 -all",
+			index:    1,
+		},
+		"ambiguous": {
+			contents:   []string{"1 . alpn=h2", "1 . alpn=h3"},
+			content:    "1 . ipv4hint=1.2.3.4",
+			index:      -1,
+			errWrapped: ErrContentRecordAmbiguous,
+			errMessage: `record to update is ambiguous: several records start with "1"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			index, err := SelectContentRecord(testCase.contents, testCase.content)
+
+			assert.Equal(t, testCase.index, index)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_SplitSVCB(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		priority   uint16
+		target     string
+		params     string
+		errWrapped error
+		errMessage string
+	}{
+		"params": {
+			content:  "1 . alpn=h2 ipv4hint=1.2.3.4",
+			priority: 1,
+			target:   ".",
+			params:   "alpn=h2 ipv4hint=1.2.3.4",
+		},
+		"no_params": {
+			content: "0 example.com.",
+			target:  "example.com.",
+		},
+		"missing_target": {
+			content:    "1",
+			errWrapped: ErrSVCBContentMalformed,
+			errMessage: `SVCB content is malformed: "1" must start with a priority and a target name`,
+		},
+		"priority_not_valid": {
+			content:    "x .",
+			errWrapped: ErrSVCBContentMalformed,
+			errMessage: `SVCB content is malformed: priority: strconv.ParseUint: parsing "x": invalid syntax`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			priority, target, params, err := SplitSVCB(testCase.content)
+
+			assert.Equal(t, testCase.priority, priority)
+			assert.Equal(t, testCase.target, target)
+			assert.Equal(t, testCase.params, params)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// RecordTypes returns the address record types matching
+// the IP version given, which is A and AAAA for IP4or6.
+func RecordTypes(ipVersion ipversion.IPVersion) (recordTypes []string) {
+	switch ipVersion {
+	case ipversion.IP4:
+		return []string{constants.A}
+	case ipversion.IP6:
+		return []string{constants.AAAA}
+	default:
+		return []string{constants.A, constants.AAAA}
+	}
+}
+
+// RecordSet is a set of IP addresses of the same address record type.
+type RecordSet struct {
+	Type string
+	IPs  []netip.Addr
+}
+
+// GroupRecordSets groups the IP addresses given into the A record set
+// and then the AAAA record set, omitting record sets without IP address.
+func GroupRecordSets(ips []netip.Addr) (recordSets []RecordSet) {
+	var ipv4s, ipv6s []netip.Addr
+	for _, ip := range ips {
+		if ip.Is4() {
+			ipv4s = append(ipv4s, ip)
+		} else {
+			ipv6s = append(ipv6s, ip)
+		}
+	}
+	if len(ipv4s) > 0 {
+		recordSets = append(recordSets, RecordSet{Type: constants.A, IPs: ipv4s})
+	}
+	if len(ipv6s) > 0 {
+		recordSets = append(recordSets, RecordSet{Type: constants.AAAA, IPs: ipv6s})
+	}
+	return recordSets
+}
+
+// GroupManagedRecordSets groups the IP addresses given into the record
+// sets of the address record types matching the IP version given.
+// A record set without IP address is kept, so that its records can be
+// deleted, and IP addresses of other record types are ignored.
+func GroupManagedRecordSets(ipVersion ipversion.IPVersion, ips []netip.Addr) (
+	recordSets []RecordSet,
+) {
+	recordTypes := RecordTypes(ipVersion)
+	recordSets = make([]RecordSet, len(recordTypes))
+	for i, recordType := range recordTypes {
+		recordSets[i].Type = recordType
+		for _, ip := range ips {
+			if ip.Is4() == (recordType == constants.A) {
+				recordSets[i].IPs = append(recordSets[i].IPs, ip)
+			}
+		}
+	}
+	return recordSets
+}
@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrLimited is returned when the request budget of a provider
+// account is exhausted.
+var ErrLimited = errors.New("provider account rate limited")
+
+// bucket is the token bucket of a provider account, which is also
+// blocked until the time indicated by the provider API responses
+// Retry-After and X-RateLimit headers.
+type bucket struct {
+	// limit is nil if requests are only limited
+	// by the provider API responses headers.
+	limit   *Limit
+	timeNow func() time.Time
+
+	mutex      sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	// blockedUntil is the time until which the provider asked
+	// not to send requests, and is zero if not blocked.
+	blockedUntil time.Time
+	// providerRemaining and providerLimit are the request budget
+	// reported by the X-RateLimit headers, and are -1 if unknown.
+	providerRemaining int
+	providerLimit     int
+}
+
+func newBucket(limit *Limit, timeNow func() time.Time) *bucket {
+	b := &bucket{
+		limit:             limit,
+		timeNow:           timeNow,
+		lastRefill:        timeNow(),
+		providerRemaining: -1,
+		providerLimit:     -1,
+	}
+	if limit != nil {
+		b.tokens = float64(limit.Requests)
+	}
+	return b
+}
+
+// refill adds the tokens accumulated since the last refill.
+// It must be called with the mutex locked.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastRefill)
+	if b.limit == nil || elapsed <= 0 {
+		return
+	}
+	b.lastRefill = now
+	refilled := float64(b.limit.Requests) * elapsed.Seconds() / b.limit.Period.Seconds()
+	b.tokens = math.Min(float64(b.limit.Requests), b.tokens+refilled)
+}
+
+// check returns an error wrapping ErrLimited if a request
+// cannot be sent now, without taking a token.
+func (b *bucket) check() (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.checkLocked(b.timeNow())
+}
+
+func (b *bucket) checkLocked(now time.Time) (err error) {
+	if now.Before(b.blockedUntil) {
+		return fmt.Errorf("%w: until %s as requested by the provider",
+			ErrLimited, b.blockedUntil.Format(time.DateTime))
+	}
+	if b.limit == nil {
+		return nil
+	}
+	b.refill(now)
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) * float64(b.limit.Period) / float64(b.limit.Requests))
+		return fmt.Errorf("%w: request budget of %s exhausted for %s",
+			ErrLimited, b.limit, wait.Round(time.Second))
+	}
+	return nil
+}
+
+// take takes a token for a request, or returns an
+// error wrapping ErrLimited if no request can be sent now.
+func (b *bucket) take() (err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	err = b.checkLocked(b.timeNow())
+	if err != nil {
+		return err
+	}
+	if b.limit != nil {
+		b.tokens--
+	}
+	return nil
+}
+
+// observe updates the bucket state from the rate limit
+// headers of the provider API response given.
+func (b *bucket) observe(response *http.Response) {
+	now := b.timeNow()
+	header := response.Header
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err == nil {
+		b.providerRemaining = remaining
+	}
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err == nil {
+		b.providerLimit = limit
+	}
+
+	var until time.Time
+	retryAfter, ok := parseRetryAfter(header.Get("Retry-After"), now)
+	switch {
+	case ok:
+		until = retryAfter
+	case b.providerRemaining == 0:
+		until, ok = parseRateLimitReset(header.Get("X-RateLimit-Reset"), now)
+	case response.StatusCode == http.StatusTooManyRequests:
+		// no indication of when to retry
+		until, ok = now.Add(time.Minute), true
+	}
+	if ok && until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// parseRetryAfter parses the Retry-After header value,
+// which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (until time.Time, ok bool) {
+	if value == "" {
+		return until, false
+	}
+	seconds, err := strconv.ParseUint(value, 10, 32)
+	if err == nil {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	until, err = http.ParseTime(value)
+	if err != nil {
+		return until, false
+	}
+	return until, true
+}
+
+// parseRateLimitReset parses the X-RateLimit-Reset header value, which
+// is either a Unix timestamp or a number of seconds, depending on the
+// provider.
+func parseRateLimitReset(value string, now time.Time) (until time.Time, ok bool) {
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset < 0 {
+		return until, false
+	}
+	// Values bigger than a year of seconds are Unix timestamps.
+	const maxSeconds = 365 * 24 * 60 * 60
+	if reset > maxSeconds {
+		return time.Unix(reset, 0), true
+	}
+	return now.Add(time.Duration(reset) * time.Second), true
+}
+
+// budget returns a human readable remaining request budget.
+func (b *bucket) budget() string {
+	now := b.timeNow()
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill(now)
+
+	s := "no request limit"
+	if b.limit != nil {
+		s = fmt.Sprintf("%d of %d requests per %s left",
+			int(b.tokens), b.limit.Requests, b.limit.Period)
+	}
+	if b.providerRemaining >= 0 {
+		s += fmt.Sprintf(", %d", b.providerRemaining)
+		if b.providerLimit >= 0 {
+			s += fmt.Sprintf(" of %d", b.providerLimit)
+		}
+		s += " left according to the provider"
+	}
+	if now.Before(b.blockedUntil) {
+		s += ", blocked until " + b.blockedUntil.Format(time.DateTime)
+	}
+	return s
+}
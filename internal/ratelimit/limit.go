@@ -0,0 +1,48 @@
+// Package ratelimit limits the requests sent to provider APIs
+// with token buckets, each shared by the records of a provider account.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a number of requests allowed per period,
+// which is also the maximum burst of requests.
+type Limit struct {
+	Requests uint
+	Period   time.Duration
+}
+
+var (
+	ErrLimitFormat      = errors.New("rate limit is not in the format <requests>/<period>")
+	ErrLimitNotPositive = errors.New("rate limit must be positive")
+)
+
+// ParseLimit parses a rate limit such as "30/1m".
+func ParseLimit(s string) (limit Limit, err error) {
+	requestsString, periodString, ok := strings.Cut(s, "/")
+	if !ok {
+		return limit, fmt.Errorf("%w: %q", ErrLimitFormat, s)
+	}
+	requests, err := strconv.ParseUint(strings.TrimSpace(requestsString), 10, 32)
+	if err != nil {
+		return limit, fmt.Errorf("%w: %q", ErrLimitFormat, s)
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(periodString))
+	if err != nil {
+		return limit, fmt.Errorf("%w: %q", ErrLimitFormat, s)
+	}
+	limit = Limit{Requests: uint(requests), Period: period}
+	if limit.Requests == 0 || limit.Period <= 0 {
+		return limit, fmt.Errorf("%w: %q", ErrLimitNotPositive, s)
+	}
+	return limit, nil
+}
+
+func (l Limit) String() string {
+	return strconv.FormatUint(uint64(l.Requests), 10) + "/" + l.Period.String()
+}
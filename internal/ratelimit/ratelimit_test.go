@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseLimit(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		limit      Limit
+		errWrapped error
+		errMessage string
+	}{
+		"valid": {
+			s:     "30/1m",
+			limit: Limit{Requests: 30, Period: time.Minute},
+		},
+		"no_separator": {
+			s:          "30",
+			errWrapped: ErrLimitFormat,
+			errMessage: `rate limit is not in the format <requests>/<period>: "30"`,
+		},
+		"bad_period": {
+			s:          "30/minute",
+			errWrapped: ErrLimitFormat,
+			errMessage: `rate limit is not in the format <requests>/<period>: "30/minute"`,
+		},
+		"zero_requests": {
+			s:          "0/1m",
+			limit:      Limit{Period: time.Minute},
+			errWrapped: ErrLimitNotPositive,
+			errMessage: `rate limit must be positive: "0/1m"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			limit, err := ParseLimit(testCase.s)
+
+			assert.Equal(t, testCase.limit, limit)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_bucket(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+	timeNow := func() time.Time { return now }
+	b := newBucket(&Limit{Requests: 2, Period: time.Minute}, timeNow)
+
+	require.NoError(t, b.take())
+	require.NoError(t, b.take())
+	err := b.take()
+	assert.ErrorIs(t, err, ErrLimited)
+	assert.EqualError(t, err, "provider account rate limited: "+
+		"request budget of 2/1m0s exhausted for 30s")
+	assert.Equal(t, "0 of 2 requests per 1m0s left", b.budget())
+
+	now = now.Add(30 * time.Second)
+	require.NoError(t, b.check())
+	require.NoError(t, b.take())
+
+	now = now.Add(time.Minute)
+	b.observe(&http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"X-Ratelimit-Remaining": []string{"0"},
+			"X-Ratelimit-Limit":     []string{"100"},
+			"X-Ratelimit-Reset":     []string{"120"},
+		},
+	})
+	err = b.check()
+	assert.EqualError(t, err, "provider account rate limited: "+
+		"until 2025-01-15 12:03:30 as requested by the provider")
+	assert.Equal(t, "2 of 2 requests per 1m0s left, 0 of 100 left according to the provider, "+
+		"blocked until 2025-01-15 12:03:30", b.budget())
+
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, b.check())
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		value string
+		until time.Time
+		ok    bool
+	}{
+		"empty": {},
+		"seconds": {
+			value: "90",
+			until: now.Add(90 * time.Second),
+			ok:    true,
+		},
+		"http_date": {
+			value: "Wed, 15 Jan 2025 12:05:00 GMT",
+			until: time.Date(2025, time.January, 15, 12, 5, 0, 0, time.UTC),
+			ok:    true,
+		},
+		"invalid": {
+			value: "soon",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			until, ok := parseRetryAfter(testCase.value, now)
+
+			assert.True(t, testCase.until.Equal(until))
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Registry holds the token buckets of provider accounts.
+type Registry struct {
+	defaultLimit *Limit
+	timeNow      func() time.Time
+
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewRegistry returns a registry creating buckets for accounts with
+// the limit given when used for the first time, unless another limit
+// is specified for the account. The default limit can be nil, in which
+// case accounts are only limited by the provider API responses headers.
+func NewRegistry(defaultLimit *Limit, timeNow func() time.Time) *Registry {
+	return &Registry{
+		defaultLimit: defaultLimit,
+		timeNow:      timeNow,
+		buckets:      make(map[string]*bucket),
+	}
+}
+
+func (r *Registry) getBucket(account string, limit *Limit) *bucket {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	accountBucket, ok := r.buckets[account]
+	if !ok {
+		bucketLimit := r.defaultLimit
+		if limit != nil {
+			bucketLimit = limit
+		}
+		accountBucket = newBucket(bucketLimit, r.timeNow)
+		r.buckets[account] = accountBucket
+	}
+	return accountBucket
+}
+
+// Check returns an error wrapping ErrLimited if a request
+// cannot be sent now for the account given.
+func (r *Registry) Check(account string, limit *Limit) (err error) {
+	return r.getBucket(account, limit).check()
+}
+
+// Client returns a copy of the client given, rate limited with the
+// bucket of the account given. Requests exceeding the budget of the
+// account fail with an error wrapping ErrLimited.
+func (r *Registry) Client(client *http.Client, account string, limit *Limit) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	limitedClient := *client
+	limitedClient.Transport = &roundTripper{
+		base:   base,
+		bucket: r.getBucket(account, limit),
+	}
+	return &limitedClient
+}
+
+// Budget returns the remaining request budget of the account given,
+// or an empty string if no request was made for this account yet.
+func (r *Registry) Budget(account string) string {
+	r.mutex.Lock()
+	accountBucket, ok := r.buckets[account]
+	r.mutex.Unlock()
+	if !ok {
+		return ""
+	}
+	return accountBucket.budget()
+}
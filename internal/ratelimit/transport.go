@@ -0,0 +1,25 @@
+package ratelimit
+
+import (
+	"net/http"
+)
+
+// roundTripper takes a token from the bucket before each request,
+// and updates the bucket from the rate limit headers of each response.
+type roundTripper struct {
+	base   http.RoundTripper
+	bucket *bucket
+}
+
+func (r *roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	err := r.bucket.take()
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.base.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	r.bucket.observe(response)
+	return response, nil
+}
@@ -0,0 +1,51 @@
+package records
+
+import (
+	"net/netip"
+	"strings"
+	"text/template"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+)
+
+// ContentData is the data the content template of a record is executed
+// with. IP is the first IP address given, and IPv4 and IPv6 are empty
+// if no address of their family is given.
+type ContentData struct {
+	IP     string
+	IPv4   string
+	IPv6   string
+	Domain string
+	Owner  string
+	FQDN   string
+}
+
+// RenderContent returns the content of the record of the provider
+// given, executing the content template with the IP addresses given.
+func RenderContent(content *template.Template, p provider.Provider,
+	ips []netip.Addr,
+) (rendered string, err error) {
+	data := ContentData{
+		Domain: p.Domain(),
+		Owner:  p.Owner(),
+		FQDN:   p.BuildDomainName(),
+	}
+	for _, ip := range ips {
+		if data.IP == "" {
+			data.IP = ip.String()
+		}
+		switch {
+		case ip.Is4() && data.IPv4 == "":
+			data.IPv4 = ip.String()
+		case ip.Is6() && data.IPv6 == "":
+			data.IPv6 = ip.String()
+		}
+	}
+
+	var builder strings.Builder
+	err = content.Execute(&builder, data)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(builder.String()), nil
+}
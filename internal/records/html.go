@@ -2,6 +2,8 @@ package records
 
 import (
 	"fmt"
+	"net/netip"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 func (r *Record) HTML(now time.Time) models.HTMLRow {
 	const NotAvailable = "N/A"
 	row := r.Provider.HTML()
+	row.IPVersion = r.IPVersionString()
 	message := r.Message
 	if r.Status == constants.UPTODATE {
 		message = "no IP change for " + r.History.GetDurationSinceSuccess(now)
@@ -27,13 +30,22 @@ func (r *Record) HTML(now time.Time) models.HTMLRow {
 			message,
 			time.Since(r.Time).Round(time.Second).String()+" ago")
 	}
-	currentIP := r.History.GetCurrentIP()
-	if currentIP.IsValid() {
-		row.CurrentIP = `<a href="https://ipinfo.io/` + currentIP.String() + `">` + currentIP.String() + "</a>"
+	currentIPs := r.CurrentIPs()
+	if len(currentIPs) > 0 {
+		currentIPLinks := make([]string, len(currentIPs))
+		for i, currentIP := range currentIPs {
+			currentIPLinks[i] = `<a href="https://ipinfo.io/` + currentIP.String() + `">` + currentIP.String() + "</a>"
+		}
+		row.CurrentIP = strings.Join(currentIPLinks, ", ")
 	} else {
 		row.CurrentIP = NotAvailable
 	}
 	previousIPs := r.History.GetPreviousIPs()
+	if r.Settings.DualStack {
+		previousIPs = slices.DeleteFunc(previousIPs, func(ip netip.Addr) bool {
+			return slices.Contains(currentIPs, ip)
+		})
+	}
 	row.PreviousIPs = NotAvailable
 	if len(previousIPs) > 0 {
 		var previousIPsStr []string
@@ -47,6 +59,15 @@ func (r *Record) HTML(now time.Time) models.HTMLRow {
 		}
 		row.PreviousIPs = strings.Join(previousIPsStr, ", ")
 	}
+	if r.Pin != nil {
+		row.PinnedUntil = r.Pin.Until.Format(time.DateTime)
+	}
+	for i := len(r.History) - 1; i >= 0; i-- {
+		ip := r.History[i].IP.String()
+		if !slices.Contains(row.HistoryIPs, ip) {
+			row.HistoryIPs = append(row.HistoryIPs, ip)
+		}
+	}
 	return row
 }
 
@@ -62,6 +83,12 @@ func convertStatus(status models.Status) string {
 		return `<span class="updating">Updating</span>`
 	case constants.UNSET:
 		return `<span class="unset">Unset</span>`
+	case constants.DRIFTED:
+		return `<span class="drifted">Externally modified</span>`
+	case constants.FAILEDOVER:
+		return `<span class="failedover">Failed over</span>`
+	case constants.PINNED:
+		return `<span class="pinned">Pinned</span>`
 	default:
 		return "Unknown status"
 	}
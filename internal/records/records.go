@@ -2,6 +2,7 @@ package records
 
 import (
 	"fmt"
+	"net/netip"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
@@ -12,22 +13,64 @@ import (
 // Record contains all the information to update and display a DNS record.
 type Record struct { // internal
 	Provider provider.Provider // fixed
+	Settings Settings          // fixed
 	History  models.History    // past information
 	Status   models.Status
 	Message  string
 	Time     time.Time
 	LastBan  *time.Time // nil means no last ban
+	// StaleRecordTypes are the A and/or AAAA record types which
+	// were deleted or set to a fallback IP address by the stale policy.
+	StaleRecordTypes []string
+	// FailedOver is true if the record points to its failover backup
+	// IP addresses, and is persisted to survive restarts.
+	FailedOver bool
+	// Pin is the manual override of the record IP address,
+	// and is nil if the record is not pinned.
+	Pin *models.Pin
 }
 
-// New returns a new Record with provider and some history.
-func New(provider provider.Provider, events []models.HistoryEvent) Record {
+// New returns a new Record with provider, settings and some history.
+func New(provider provider.Provider, settings Settings,
+	events []models.HistoryEvent,
+) Record {
 	return Record{
 		Provider: provider,
+		Settings: settings,
 		History:  events,
 		Status:   constants.UNSET,
 	}
 }
 
+// IPVersionString returns the IP version of the record,
+// which is "ipv4 and ipv6" for dual-stack records.
+func (r *Record) IPVersionString() string {
+	if r.Settings.DualStack {
+		return DualStackIPVersion
+	}
+	return r.Provider.IPVersion().String()
+}
+
+// CurrentIPs returns the current IP addresses of the record, which are
+// the latest IPv4 and IPv6 addresses for dual-stack records, the latest
+// set of IP addresses for multi-value records, and the latest IP
+// address otherwise.
+func (r *Record) CurrentIPs() (ips []netip.Addr) {
+	currentIPs := []netip.Addr{r.History.GetCurrentIP()}
+	switch {
+	case r.Settings.DualStack:
+		currentIPs = []netip.Addr{r.History.GetCurrentIP4(), r.History.GetCurrentIP6()}
+	case r.Settings.MultiIP != nil:
+		currentIPs = r.History.GetCurrentIPs()
+	}
+	for _, ip := range currentIPs {
+		if ip.IsValid() {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
 func (r *Record) String() string {
 	status := string(r.Status)
 	if r.Message != "" {
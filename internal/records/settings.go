@@ -0,0 +1,96 @@
+package records
+
+import (
+	"net/netip"
+	"text/template"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/ipexpr"
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
+	"github.com/qdm12/ddns-updater/internal/schedule"
+)
+
+// StalePolicy is the policy applied to the A or AAAA record of an IP family
+// whose public IP address can no longer be obtained.
+type StalePolicy string
+
+const (
+	// StalePolicyKeep leaves the stale record untouched.
+	StalePolicyKeep StalePolicy = "keep"
+	// StalePolicyDelete deletes the stale record.
+	StalePolicyDelete StalePolicy = "delete"
+	// StalePolicyFallback sets the stale record to a fallback IP address.
+	StalePolicyFallback StalePolicy = "fallback"
+)
+
+// DualStackIPVersion is the IP version of dual-stack records,
+// which manage both their A and AAAA records as a unit.
+const DualStackIPVersion = "ipv4 and ipv6"
+
+// Settings contains record settings handled by the program
+// and not by the provider of the record.
+type Settings struct {
+	// DualStack is true if the record manages both its A and AAAA
+	// records, in which case its provider has the IP version ipv4 or ipv6.
+	DualStack bool
+	// Uplink is the name of the uplink used to fetch the public IP
+	// addresses and update the record, and is empty for the default one.
+	Uplink string
+	// AutoIP is true if the record IP addresses are detected by its
+	// provider from the source address of the update requests, instead
+	// of being fetched by the program.
+	AutoIP      bool
+	StalePolicy StalePolicy
+	// FallbackIPv4 and FallbackIPv6 are the IP addresses to set
+	// stale A and AAAA records to, if StalePolicy is StalePolicyFallback.
+	FallbackIPv4 netip.Addr
+	FallbackIPv6 netip.Addr
+	// Failover is nil if the record has no failover.
+	Failover *failover.Settings
+	// Schedule is nil to update the record with the global period.
+	Schedule *schedule.Schedule
+	// Cooldown is nil to use the global cooldown period.
+	Cooldown *time.Duration
+	// Critical is true if the record is updated during maintenance windows.
+	Critical bool
+	// Account identifies the provider account of the record,
+	// whose requests are rate limited together.
+	Account string
+	// RateLimit is nil to use the default rate limit.
+	RateLimit *ratelimit.Limit
+	// MultiIP is nil if the record is set to a single IP address.
+	MultiIP *MultiIP
+	// IPExpressions compute the IP addresses of the record from the
+	// public IP addresses, with at most one expression per IP family.
+	IPExpressions []ipexpr.Expression
+	// IPv6Prefix defines how the length of the delegated IPv6 prefix is
+	// obtained for host identifiers, and is nil to use a /64 prefix.
+	IPv6Prefix *ipexpr.Prefix
+	// Follow is the fully qualified domain name whose IP addresses the
+	// record is set to, instead of the public IP addresses, and is empty
+	// if the record does not follow another domain name.
+	Follow string
+	// RecordType is the type of the record, such as TXT, whose content
+	// is rendered from the Content template with the public IP addresses.
+	// It is empty for A and AAAA records.
+	RecordType string
+	Content    *template.Template
+}
+
+// MultiIP contains the settings of a multi-value record,
+// which is set to a set of IP addresses.
+type MultiIP struct {
+	// Uplinks are the names of the uplinks whose public IP
+	// addresses are set on the record.
+	Uplinks []string
+	// Reported is true if the IP addresses set on the record are
+	// reported by hosts through the report HTTP endpoint.
+	Reported bool
+	// ReportExpiry is the duration after which a reported
+	// IP address is removed from the record if not reported again.
+	ReportExpiry time.Duration
+	// ReportToken is the token hosts must send
+	// to report their IP address for the record.
+	ReportToken string
+}
@@ -0,0 +1,166 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronExpression is a parsed standard 5 fields cron expression, where
+// each field is a bit set of the values matching the field.
+type cronExpression struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// As for the standard cron, if both the days of month and days
+	// of week fields are restricted, a day matches if it matches
+	// either of them.
+	domRestricted bool
+	dowRestricted bool
+}
+
+var (
+	ErrCronFieldsCount = errors.New("cron expression must have 5 fields")
+	ErrCronFieldValue  = errors.New("cron field value is not valid")
+	ErrCronFieldRange  = errors.New("cron field value is out of range")
+	ErrCronFieldStep   = errors.New("cron field step is not valid")
+)
+
+func parseCron(expression string) (cron *cronExpression, err error) {
+	fields := strings.Fields(expression)
+	const fieldsCount = 5
+	if len(fields) != fieldsCount {
+		return nil, fmt.Errorf("%w: %q has %d fields", ErrCronFieldsCount, expression, len(fields))
+	}
+
+	type fieldSpec struct {
+		name     string
+		min, max uint
+		bits     *uint64
+	}
+	cron = &cronExpression{}
+	specs := []fieldSpec{
+		{name: "minute", min: 0, max: 59, bits: &cron.minutes},           //nolint:mnd
+		{name: "hour", min: 0, max: 23, bits: &cron.hours},               //nolint:mnd
+		{name: "day of month", min: 1, max: 31, bits: &cron.daysOfMonth}, //nolint:mnd
+		{name: "month", min: 1, max: 12, bits: &cron.months},             //nolint:mnd
+		{name: "day of week", min: 0, max: 7, bits: &cron.daysOfWeek},    //nolint:mnd
+	}
+	for i, spec := range specs {
+		*spec.bits, err = parseCronField(fields[i], spec.min, spec.max)
+		if err != nil {
+			return nil, fmt.Errorf("%s field: %w", spec.name, err)
+		}
+	}
+
+	// Sunday can be 0 or 7
+	const sunday = 7
+	if cron.daysOfWeek&(1<<sunday) != 0 {
+		cron.daysOfWeek |= 1
+		cron.daysOfWeek &^= 1 << sunday
+	}
+	cron.domRestricted = fields[2] != "*"
+	cron.dowRestricted = fields[4] != "*"
+	return cron, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges
+// and steps, such as "*", "5", "1-5", "*/15" or "0-30/10,45".
+func parseCronField(field string, minValue, maxValue uint) (bits uint64, err error) {
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := uint(1)
+		if hasStep {
+			step, err = parseCronValue(stepPart)
+			if err != nil || step == 0 {
+				return 0, fmt.Errorf("%w: %q", ErrCronFieldStep, part)
+			}
+		}
+
+		start, end := minValue, maxValue
+		if rangePart != "*" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+			start, err = parseCronValue(startPart)
+			if err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				end, err = parseCronValue(endPart)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = maxValue
+			}
+		}
+
+		if start < minValue || end > maxValue || start > end {
+			return 0, fmt.Errorf("%w: %q must be within %d-%d",
+				ErrCronFieldRange, part, minValue, maxValue)
+		}
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string) (value uint, err error) {
+	parsed, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrCronFieldValue, s)
+	}
+	return uint(parsed), nil
+}
+
+// next returns the first time strictly after t matching the expression,
+// in the location of t.
+func (c *cronExpression) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// A matching time is always found within 5 years, for example
+	// for a 29th of February which is also a certain day of week.
+	const maxYears = 5
+	limit := t.AddDate(maxYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !hasBit(c.months, uint(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !hasBit(c.hours, uint(t.Hour())):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !hasBit(c.minutes, uint(t.Minute())):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return t
+}
+
+// matches returns true if the minute of the time given
+// matches the expression.
+func (c *cronExpression) matches(t time.Time) bool {
+	return hasBit(c.months, uint(t.Month())) &&
+		c.dayMatches(t) &&
+		hasBit(c.hours, uint(t.Hour())) &&
+		hasBit(c.minutes, uint(t.Minute()))
+}
+
+func (c *cronExpression) dayMatches(t time.Time) bool {
+	domMatch := hasBit(c.daysOfMonth, uint(t.Day()))
+	dowMatch := hasBit(c.daysOfWeek, uint(t.Weekday()))
+	if c.domRestricted && c.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+func hasBit(bits uint64, value uint) bool {
+	return bits&(1<<value) != 0
+}
@@ -0,0 +1,54 @@
+// Package schedule defines record update schedules, which are either
+// a fixed period or a cron expression, as well as time windows.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Schedule determines when a record is next updated.
+type Schedule struct {
+	period time.Duration
+	cron   *cronExpression
+	source string
+}
+
+var ErrPeriodNotPositive = errors.New("period is not positive")
+
+// NewPeriod returns a schedule running every period given.
+func NewPeriod(period time.Duration) (schedule *Schedule, err error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPeriodNotPositive, period)
+	}
+	return &Schedule{
+		period: period,
+		source: "every " + period.String(),
+	}, nil
+}
+
+// NewCron returns a schedule running at the times matching the
+// standard 5 fields cron expression given, for example "*/5 * * * *".
+func NewCron(expression string) (schedule *Schedule, err error) {
+	cron, err := parseCron(expression)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cron expression: %w", err)
+	}
+	return &Schedule{
+		cron:   cron,
+		source: "cron " + expression,
+	}, nil
+}
+
+// Next returns the next time to run after the time given.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.cron != nil {
+		return s.cron.next(t)
+	}
+	return t.Add(s.period)
+}
+
+func (s *Schedule) String() string {
+	return s.source
+}
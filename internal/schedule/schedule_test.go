@@ -0,0 +1,143 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewCron(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		errWrapped error
+		errMessage string
+	}{
+		"valid": {
+			expression: "*/5 8-18 * * 1-5",
+		},
+		"too_few_fields": {
+			expression: "* * * *",
+			errWrapped: ErrCronFieldsCount,
+			errMessage: `parsing cron expression: cron expression must have 5 fields: "* * * *" has 4 fields`,
+		},
+		"minute_out_of_range": {
+			expression: "60 * * * *",
+			errWrapped: ErrCronFieldRange,
+			errMessage: `parsing cron expression: minute field: ` +
+				`cron field value is out of range: "60" must be within 0-59`,
+		},
+		"zero_step": {
+			expression: "*/0 * * * *",
+			errWrapped: ErrCronFieldStep,
+			errMessage: `parsing cron expression: minute field: cron field step is not valid: "*/0"`,
+		},
+		"bad_value": {
+			expression: "* * x * *",
+			errWrapped: ErrCronFieldValue,
+			errMessage: `parsing cron expression: day of month field: cron field value is not valid: "x"`,
+		},
+		"reversed_range": {
+			expression: "* 5-1 * * *",
+			errWrapped: ErrCronFieldRange,
+			errMessage: `parsing cron expression: hour field: ` +
+				`cron field value is out of range: "5-1" must be within 0-23`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := NewCron(testCase.expression)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				assert.Nil(t, schedule)
+			} else {
+				assert.Equal(t, "cron "+testCase.expression, schedule.String())
+			}
+		})
+	}
+}
+
+func Test_Schedule_Next(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday 15th of January 2025
+	now := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	testCases := map[string]struct {
+		expression string
+		period     time.Duration
+		next       time.Time
+	}{
+		"period": {
+			period: 30 * time.Second,
+			next:   now.Add(30 * time.Second),
+		},
+		"every_minute": {
+			expression: "* * * * *",
+			next:       time.Date(2025, time.January, 15, 10, 8, 0, 0, time.UTC),
+		},
+		"every_15_minutes": {
+			expression: "*/15 * * * *",
+			next:       time.Date(2025, time.January, 15, 10, 15, 0, 0, time.UTC),
+		},
+		"minute_list": {
+			expression: "5,50 * * * *",
+			next:       time.Date(2025, time.January, 15, 10, 50, 0, 0, time.UTC),
+		},
+		"next_hour": {
+			expression: "0 * * * *",
+			next:       time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC),
+		},
+		"next_day": {
+			expression: "30 3 * * *",
+			next:       time.Date(2025, time.January, 16, 3, 30, 0, 0, time.UTC),
+		},
+		"weekdays_only": {
+			expression: "0 9 * * 1-5",
+			next:       time.Date(2025, time.January, 16, 9, 0, 0, 0, time.UTC),
+		},
+		"sunday_as_7": {
+			expression: "0 0 * * 7",
+			next:       time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC),
+		},
+		"day_of_month_or_week": {
+			expression: "0 0 20 * 5",
+			next:       time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC),
+		},
+		"next_month": {
+			expression: "0 0 1 * *",
+			next:       time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"leap_day": {
+			expression: "0 0 29 2 *",
+			next:       time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var schedule *Schedule
+			var err error
+			if testCase.expression != "" {
+				schedule, err = NewCron(testCase.expression)
+			} else {
+				schedule, err = NewPeriod(testCase.period)
+			}
+			require.NoError(t, err)
+
+			next := schedule.Next(now)
+
+			assert.Equal(t, testCase.next, next)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time window, which can span midnight.
+type Window struct {
+	// Start and End are the durations since midnight
+	// at which the window starts and ends.
+	Start time.Duration
+	End   time.Duration
+}
+
+var (
+	ErrWindowFormat = errors.New("window is not in the format hh:mm-hh:mm")
+	ErrWindowEmpty  = errors.New("window start and end are equal")
+)
+
+// ParseWindow parses a daily window in the format hh:mm-hh:mm,
+// for example "23:45-00:15".
+func ParseWindow(s string) (window Window, err error) {
+	startString, endString, ok := strings.Cut(s, "-")
+	if !ok {
+		return window, fmt.Errorf("%w: %q", ErrWindowFormat, s)
+	}
+
+	const layout = "15:04"
+	start, err := time.Parse(layout, strings.TrimSpace(startString))
+	if err != nil {
+		return window, fmt.Errorf("%w: %q", ErrWindowFormat, s)
+	}
+	end, err := time.Parse(layout, strings.TrimSpace(endString))
+	if err != nil {
+		return window, fmt.Errorf("%w: %q", ErrWindowFormat, s)
+	}
+
+	window = Window{
+		Start: timeOfDay(start),
+		End:   timeOfDay(end),
+	}
+	if window.Start == window.End {
+		return window, fmt.Errorf("%w: %q", ErrWindowEmpty, s)
+	}
+	return window, nil
+}
+
+func (w Window) String() string {
+	return formatTimeOfDay(w.Start) + "-" + formatTimeOfDay(w.End)
+}
+
+// Contains returns true if the time given is within the window,
+// in the location of the time given.
+func (w Window) Contains(t time.Time) bool {
+	now := timeOfDay(t)
+	if w.Start < w.End {
+		return now >= w.Start && now < w.End
+	}
+	return now >= w.Start || now < w.End
+}
+
+// NextEnd returns the first end time of the window strictly
+// after the time given, in the location of the time given.
+func (w Window) NextEnd(t time.Time) time.Time {
+	end := atTimeOfDay(t, w.End)
+	if !end.After(t) {
+		end = atTimeOfDay(t.AddDate(0, 0, 1), w.End)
+	}
+	return end
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+}
+
+func atTimeOfDay(t time.Time, timeOfDay time.Duration) time.Time {
+	hours := int(timeOfDay / time.Hour)
+	minutes := int(timeOfDay % time.Hour / time.Minute)
+	return time.Date(t.Year(), t.Month(), t.Day(), hours, minutes, 0, 0, t.Location())
+}
+
+func formatTimeOfDay(timeOfDay time.Duration) string {
+	hours := int(timeOfDay / time.Hour)
+	minutes := int(timeOfDay % time.Hour / time.Minute)
+	return fmt.Sprintf("%02d:%02d", hours, minutes)
+}
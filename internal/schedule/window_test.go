@@ -0,0 +1,80 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseWindow(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		window     Window
+		errWrapped error
+		errMessage string
+	}{
+		"valid": {
+			s:      "03:00-03:30",
+			window: Window{Start: 3 * time.Hour, End: 3*time.Hour + 30*time.Minute},
+		},
+		"spanning_midnight": {
+			s:      "23:45 - 00:15",
+			window: Window{Start: 23*time.Hour + 45*time.Minute, End: 15 * time.Minute},
+		},
+		"no_separator": {
+			s:          "03:00",
+			errWrapped: ErrWindowFormat,
+			errMessage: `window is not in the format hh:mm-hh:mm: "03:00"`,
+		},
+		"bad_time": {
+			s:          "3h-4h",
+			errWrapped: ErrWindowFormat,
+			errMessage: `window is not in the format hh:mm-hh:mm: "3h-4h"`,
+		},
+		"empty": {
+			s:          "03:00-03:00",
+			window:     Window{Start: 3 * time.Hour, End: 3 * time.Hour},
+			errWrapped: ErrWindowEmpty,
+			errMessage: `window start and end are equal: "03:00-03:00"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			window, err := ParseWindow(testCase.s)
+
+			assert.Equal(t, testCase.window, window)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Window(t *testing.T) {
+	t.Parallel()
+
+	window, err := ParseWindow("23:45-00:15")
+	require.NoError(t, err)
+	assert.Equal(t, "23:45-00:15", window.String())
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	assert.False(t, window.Contains(at(15, 23, 44)))
+	assert.True(t, window.Contains(at(15, 23, 45)))
+	assert.True(t, window.Contains(at(16, 0, 14)))
+	assert.False(t, window.Contains(at(16, 0, 15)))
+
+	assert.Equal(t, at(16, 0, 15), window.NextEnd(at(15, 12, 0)))
+	assert.Equal(t, at(16, 0, 15), window.NextEnd(at(16, 0, 0)))
+	assert.Equal(t, at(17, 0, 15), window.NextEnd(at(16, 0, 15)))
+}
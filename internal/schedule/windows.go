@@ -0,0 +1,105 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CronWindows are time windows defined by cron expressions, where
+// a time is within the windows if its minute matches one of the
+// expressions. Each expression can be prefixed with CRON_TZ=<timezone>
+// to be evaluated in this timezone instead of the local timezone.
+type CronWindows struct {
+	windows []cronWindow
+}
+
+type cronWindow struct {
+	cron     *cronExpression
+	location *time.Location
+	source   string
+}
+
+var (
+	ErrCronWindowsEmpty   = errors.New("no cron window expression")
+	ErrTimezoneNotValid   = errors.New("timezone is not valid")
+	ErrCronWindowNotValid = errors.New("cron window is not valid")
+)
+
+// ParseCronWindows parses semicolon separated cron expressions,
+// for example "CRON_TZ=Europe/Berlin * 9-17 * * 1-5; * 0-5 * * *".
+func ParseCronWindows(s string) (windows *CronWindows, err error) {
+	windows = &CronWindows{}
+	for expression := range strings.SplitSeq(s, ";") {
+		expression = strings.TrimSpace(expression)
+		if expression == "" {
+			continue
+		}
+		window, err := parseCronWindow(expression)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrCronWindowNotValid, expression, err)
+		}
+		windows.windows = append(windows.windows, window)
+	}
+	if len(windows.windows) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrCronWindowsEmpty, s)
+	}
+	return windows, nil
+}
+
+func parseCronWindow(expression string) (window cronWindow, err error) {
+	window.source = expression
+	window.location = time.Local
+	const timezonePrefix = "CRON_TZ="
+	if strings.HasPrefix(expression, timezonePrefix) {
+		timezone, rest, _ := strings.Cut(strings.TrimPrefix(expression, timezonePrefix), " ")
+		window.location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return window, fmt.Errorf("%w: %w", ErrTimezoneNotValid, err)
+		}
+		expression = rest
+	}
+	window.cron, err = parseCron(expression)
+	if err != nil {
+		return window, err
+	}
+	return window, nil
+}
+
+func (c *CronWindows) String() string {
+	sources := make([]string, len(c.windows))
+	for i, window := range c.windows {
+		sources[i] = window.source
+	}
+	return strings.Join(sources, "; ")
+}
+
+// Contains returns true if the time given is within one of the
+// windows. It returns false if the receiver is nil.
+func (c *CronWindows) Contains(t time.Time) bool {
+	if c == nil {
+		return false
+	}
+	for _, window := range c.windows {
+		if window.cron.matches(t.In(window.location)) {
+			return true
+		}
+	}
+	return false
+}
+
+// End returns the first minute after the time given which is not
+// within the windows, or the time given if it is not within the windows.
+func (c *CronWindows) End(t time.Time) time.Time {
+	if !c.Contains(t) {
+		return t
+	}
+	end := t.Truncate(time.Minute).Add(time.Minute)
+	// Stop searching after a month, for windows always in effect.
+	limit := t.AddDate(0, 1, 0)
+	for c.Contains(end) && end.Before(limit) {
+		end = end.Add(time.Minute)
+	}
+	return end
+}
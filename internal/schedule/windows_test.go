@@ -0,0 +1,81 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseCronWindows(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		errWrapped error
+		errMessage string
+	}{
+		"valid": {
+			s: "CRON_TZ=Europe/Berlin * 9-17 * * 1-5; * 0-5 * * *",
+		},
+		"empty": {
+			s:          " ; ",
+			errWrapped: ErrCronWindowsEmpty,
+			errMessage: `no cron window expression: " ; "`,
+		},
+		"bad_timezone": {
+			s:          "CRON_TZ=Mars/Olympus * * * * *",
+			errWrapped: ErrTimezoneNotValid,
+			errMessage: `cron window is not valid: "CRON_TZ=Mars/Olympus * * * * *": ` +
+				`timezone is not valid: unknown time zone Mars/Olympus`,
+		},
+		"bad_expression": {
+			s:          "* 25 * * *",
+			errWrapped: ErrCronFieldRange,
+			errMessage: `cron window is not valid: "* 25 * * *": hour field: ` +
+				`cron field value is out of range: "25" must be within 0-23`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			windows, err := ParseCronWindows(testCase.s)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				assert.Nil(t, windows)
+			} else {
+				assert.NotNil(t, windows)
+			}
+		})
+	}
+}
+
+func Test_CronWindows(t *testing.T) {
+	t.Parallel()
+
+	windows, err := ParseCronWindows("CRON_TZ=Europe/Berlin * 9-16 * * 1-5")
+	require.NoError(t, err)
+	assert.Equal(t, "CRON_TZ=Europe/Berlin * 9-16 * * 1-5", windows.String())
+
+	// Wednesday 15th of January 2025, Berlin is UTC+1
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, time.January, 15, hour, minute, 30, 0, time.UTC)
+	}
+
+	assert.False(t, windows.Contains(at(7, 59)))
+	assert.True(t, windows.Contains(at(8, 0)))
+	assert.True(t, windows.Contains(at(15, 59)))
+	assert.False(t, windows.Contains(at(16, 0)))
+
+	assert.Equal(t, at(7, 0), windows.End(at(7, 0)))
+	assert.Equal(t, time.Date(2025, time.January, 15, 16, 0, 0, 0, time.UTC), windows.End(at(12, 0)))
+
+	var nilWindows *CronWindows
+	assert.False(t, nilWindows.Contains(at(12, 0)))
+	assert.Equal(t, at(12, 0), nilWindows.End(at(12, 0)))
+}
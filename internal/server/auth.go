@@ -0,0 +1,36 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// checkAPIToken rejects requests without the API token, given either
+// as a bearer token in the Authorization header or as the form value
+// token. All requests are let through if no API token is set.
+func (h *handlers) checkAPIToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.apiToken == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := requestToken(r)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.apiToken)) != 1 {
+			httpError(w, http.StatusUnauthorized, "API token is not valid")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestToken returns the bearer token of the Authorization header
+// if set, and the form value token otherwise.
+func requestToken(r *http.Request) (token string) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if ok {
+		return token
+	}
+	return r.FormValue("token")
+}
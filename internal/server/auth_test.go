@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePinner struct {
+	pinned bool
+}
+
+func (p *fakePinner) Pin(context.Context, string, netip.Addr, time.Duration) error {
+	p.pinned = true
+	return nil
+}
+
+func (p *fakePinner) Unpin(context.Context, string) error {
+	return nil
+}
+
+func Test_handlers_pinProtection(t *testing.T) {
+	t.Parallel()
+
+	const body = "record=a&ip=1.2.3.4&duration=1h"
+
+	testCases := map[string]struct {
+		apiToken   string
+		body       string
+		header     http.Header
+		status     int
+		wantPinned bool
+	}{
+		"no_token_set": {
+			body:       body,
+			status:     http.StatusOK,
+			wantPinned: true,
+		},
+		"cross_site": {
+			body:   body,
+			header: http.Header{"Sec-Fetch-Site": []string{"cross-site"}},
+			status: http.StatusForbidden,
+		},
+		"cross_origin": {
+			body:   body,
+			header: http.Header{"Origin": []string{"https://attacker.example.com"}},
+			status: http.StatusForbidden,
+		},
+		"same_origin": {
+			body:       body,
+			header:     http.Header{"Sec-Fetch-Site": []string{"same-origin"}},
+			status:     http.StatusOK,
+			wantPinned: true,
+		},
+		"token_missing": {
+			apiToken: "secret",
+			body:     body,
+			status:   http.StatusUnauthorized,
+		},
+		"token_wrong": {
+			apiToken: "secret",
+			body:     body + "&token=wrong",
+			status:   http.StatusUnauthorized,
+		},
+		"token_form_value": {
+			apiToken:   "secret",
+			body:       body + "&token=secret",
+			status:     http.StatusOK,
+			wantPinned: true,
+		},
+		"token_bearer": {
+			apiToken:   "secret",
+			body:       body,
+			header:     http.Header{"Authorization": []string{"Bearer secret"}},
+			status:     http.StatusOK,
+			wantPinned: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pinner := &fakePinner{}
+			handler := newHandler(context.Background(), "/", testCase.apiToken,
+				nil, nil, pinner, nil, nil)
+
+			request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/pin",
+				strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			for key, values := range testCase.header {
+				request.Header[key] = values
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.wantPinned, pinner.pinned)
+		})
+	}
+}
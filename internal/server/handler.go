@@ -18,6 +18,11 @@ type handlers struct {
 	// Objects
 	db            Database
 	runner        UpdateForcer
+	pinner        Pinner
+	ipReporter    IPReporter
+	rateLimits    RateLimitReporter
+	rootURL       string
+	apiToken      string
 	indexTemplate *template.Template
 	// Mockable functions
 	timeNow func() time.Time
@@ -26,8 +31,9 @@ type handlers struct {
 //go:embed ui/*
 var uiFS embed.FS
 
-func newHandler(ctx context.Context, rootURL string,
-	db Database, runner UpdateForcer,
+func newHandler(ctx context.Context, rootURL, apiToken string,
+	db Database, runner UpdateForcer, pinner Pinner, ipReporter IPReporter,
+	rateLimits RateLimitReporter,
 ) http.Handler {
 	indexTemplate := template.Must(template.ParseFS(uiFS, "ui/index.html"))
 
@@ -39,10 +45,14 @@ func newHandler(ctx context.Context, rootURL string,
 	handlers := &handlers{
 		ctx:           ctx,
 		db:            db,
+		apiToken:      apiToken,
 		indexTemplate: indexTemplate,
 		// TODO build information
-		timeNow: time.Now,
-		runner:  runner,
+		timeNow:    time.Now,
+		runner:     runner,
+		pinner:     pinner,
+		ipReporter: ipReporter,
+		rateLimits: rateLimits,
 	}
 
 	router := chi.NewRouter()
@@ -50,6 +60,7 @@ func newHandler(ctx context.Context, rootURL string,
 	router.Use(middleware.ClientIPFromRemoteAddr)
 	router.Use(middleware.Logger)
 	rootURL = strings.TrimSuffix(rootURL, "/")
+	handlers.rootURL = rootURL
 
 	if rootURL != "" {
 		router.Handle(rootURL, http.RedirectHandler(rootURL+"/", http.StatusPermanentRedirect))
@@ -57,6 +68,13 @@ func newHandler(ctx context.Context, rootURL string,
 	router.Get(rootURL+"/", handlers.index)
 
 	router.Get(rootURL+"/update", handlers.update)
+	router.Group(func(router chi.Router) {
+		router.Use(http.NewCrossOriginProtection().Handler)
+		router.Use(handlers.checkAPIToken)
+		router.Post(rootURL+"/pin", handlers.pin)
+		router.Post(rootURL+"/unpin", handlers.unpin)
+	})
+	router.Post(rootURL+"/report", handlers.report)
 
 	router.Handle(rootURL+"/static/*", http.StripPrefix(rootURL+"/static/", http.FileServerFS(staticFolder)))
 
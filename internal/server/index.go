@@ -7,9 +7,14 @@ import (
 )
 
 func (h *handlers) index(w http.ResponseWriter, _ *http.Request) {
-	var htmlData models.HTMLData
-	for _, record := range h.db.SelectAll() {
+	htmlData := models.HTMLData{
+		APITokenRequired: h.apiToken != "",
+	}
+	for i, record := range h.db.SelectAll() {
 		row := record.HTML(h.timeNow())
+		row.ID = uint(i)
+		row.Key = record.Provider.String()
+		row.RateLimit = h.rateLimits.Budget(record.Settings.Account)
 		htmlData.Rows = append(htmlData.Rows, row)
 	}
 	err := h.indexTemplate.ExecuteTemplate(w, "index.html", htmlData)
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"net/netip"
+	"time"
 
 	"github.com/qdm12/ddns-updater/internal/records"
 )
@@ -14,6 +16,19 @@ type UpdateForcer interface {
 	ForceUpdate(ctx context.Context) (errors []error)
 }
 
+type Pinner interface {
+	Pin(ctx context.Context, record string, ip netip.Addr, duration time.Duration) (err error)
+	Unpin(ctx context.Context, record string) (err error)
+}
+
+type IPReporter interface {
+	Report(ctx context.Context, domain, token string, ip netip.Addr) (err error)
+}
+
+type RateLimitReporter interface {
+	Budget(account string) (budget string)
+}
+
 type Logger interface {
 	Info(s string)
 	Warn(s string)
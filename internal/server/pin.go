@@ -0,0 +1,75 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/update"
+)
+
+// pin pins the record with the form value record to the form value ip
+// for the form value duration, which can be for example 2h.
+// The record form value is the record provider string, for example
+// [domain: example.com | owner: @ | provider: cloudflare | ip: ipv4].
+func (h *handlers) pin(w http.ResponseWriter, r *http.Request) {
+	record := r.FormValue("record")
+	if record == "" {
+		httpError(w, http.StatusBadRequest, "record is not set")
+		return
+	}
+	ip, err := netip.ParseAddr(r.FormValue("ip"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "ip is not valid: "+err.Error())
+		return
+	}
+	duration, err := time.ParseDuration(r.FormValue("duration"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "duration is not valid: "+err.Error())
+		return
+	}
+
+	err = h.pinner.Pin(h.ctx, record, ip, duration) //nolint:contextcheck
+	switch {
+	case errors.Is(err, update.ErrPinRecordNotFound):
+		httpError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		httpError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.respondAction(w, r, "Record pinned to "+ip.String()+" for "+duration.String())
+}
+
+// unpin removes the pin of the record with the form value record.
+func (h *handlers) unpin(w http.ResponseWriter, r *http.Request) {
+	record := r.FormValue("record")
+	if record == "" {
+		httpError(w, http.StatusBadRequest, "record is not set")
+		return
+	}
+
+	err := h.pinner.Unpin(h.ctx, record) //nolint:contextcheck
+	switch {
+	case errors.Is(err, update.ErrPinRecordNotFound):
+		httpError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		httpError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.respondAction(w, r, "Record unpinned")
+}
+
+// respondAction redirects browsers submitting a form back to the
+// web UI, and writes the message given for other clients.
+func (h *handlers) respondAction(w http.ResponseWriter, r *http.Request, message string) {
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		http.Redirect(w, r, h.rootURL+"/", http.StatusSeeOther)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(message))
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/qdm12/ddns-updater/internal/update"
+)
+
+// report reports the form value ip for the multi-value records with
+// the form value domain, where the ip defaults to the client IP address.
+// The request must carry the report token of the records, either as a
+// bearer token or as the form value token.
+func (h *handlers) report(w http.ResponseWriter, r *http.Request) {
+	domain := r.FormValue("domain")
+	if domain == "" {
+		httpError(w, http.StatusBadRequest, "domain is not set")
+		return
+	}
+	ip := middleware.GetClientIPAddr(r.Context())
+	if ipString := r.FormValue("ip"); ipString != "" {
+		// The IP address given is only used if the
+		// report token is valid, which is checked below.
+		var err error
+		ip, err = netip.ParseAddr(ipString)
+		if err != nil {
+			httpError(w, http.StatusBadRequest, "ip is not valid: "+err.Error())
+			return
+		}
+	} else if !ip.IsValid() {
+		httpError(w, http.StatusBadRequest, "client IP address cannot be determined")
+		return
+	}
+
+	err := h.ipReporter.Report(h.ctx, domain, requestToken(r), ip) //nolint:contextcheck
+	switch {
+	case errors.Is(err, update.ErrReportNotAuthorized):
+		httpError(w, http.StatusUnauthorized, err.Error())
+		return
+	case err != nil:
+		httpError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.respondAction(w, r, "IP address "+ip.String()+" reported")
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/update"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeIPReporter struct {
+	ip netip.Addr
+}
+
+func (r *fakeIPReporter) Report(_ context.Context, domain, token string, ip netip.Addr) error {
+	if token != "secret" {
+		return fmt.Errorf("%w: %s", update.ErrReportNotAuthorized, domain)
+	}
+	r.ip = ip
+	return nil
+}
+
+func Test_handlers_report(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body   string
+		header http.Header
+		status int
+		ip     netip.Addr
+	}{
+		"domain_not_set": {
+			body:   "token=secret",
+			status: http.StatusBadRequest,
+		},
+		"token_wrong": {
+			body:   "domain=host.example.com&token=wrong",
+			status: http.StatusUnauthorized,
+		},
+		"client_ip": {
+			body:   "domain=host.example.com&token=secret",
+			status: http.StatusOK,
+			ip:     netip.MustParseAddr("192.0.2.1"),
+		},
+		"ip_given_with_bearer_token": {
+			body:   "domain=host.example.com&ip=1.2.3.4",
+			header: http.Header{"Authorization": []string{"Bearer secret"}},
+			status: http.StatusOK,
+			ip:     netip.MustParseAddr("1.2.3.4"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reporter := &fakeIPReporter{}
+			handler := newHandler(context.Background(), "/", "",
+				nil, nil, nil, reporter, nil)
+
+			request := httptest.NewRequest(http.MethodPost, "http://localhost:8000/report",
+				strings.NewReader(testCase.body))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			for key, values := range testCase.header {
+				request.Header[key] = values
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.ip, reporter.ip)
+		})
+	}
+}
@@ -6,11 +6,12 @@ import (
 	"github.com/qdm12/goservices/httpserver"
 )
 
-func New(ctx context.Context, address, rootURL string, db Database,
-	logger Logger, runner UpdateForcer,
+func New(ctx context.Context, address, rootURL, apiToken string, db Database,
+	logger Logger, runner UpdateForcer, pinner Pinner, ipReporter IPReporter,
+	rateLimits RateLimitReporter,
 ) (server *httpserver.Server, err error) {
 	return httpserver.New(httpserver.Settings{
-		Handler: newHandler(ctx, rootURL, db, runner),
+		Handler: newHandler(ctx, rootURL, apiToken, db, runner, pinner, ipReporter, rateLimits),
 		Address: &address,
 		Logger:  logger,
 	})
@@ -0,0 +1,104 @@
+package shoutrrr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+)
+
+// BeginCycle starts buffering record notifications, until
+// EndCycle is called to send them all as a single message.
+func (c *Client) BeginCycle() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.inCycle = true
+}
+
+// EndCycle sends the record notifications buffered since
+// BeginCycle was called as a single message, if any, together
+// with the notifications held during a quiet window which ended.
+func (c *Client) EndCycle() {
+	c.mutex.Lock()
+	lines := c.release(c.pending)
+	c.pending = nil
+	c.inCycle = false
+	c.mutex.Unlock()
+
+	if len(lines) == 0 {
+		return
+	}
+	c.send(makeDigest(lines))
+}
+
+// NotifyRecord notifies the record status and message given, where
+// the key identifies the record and the record is its display string.
+// Failures identical to the previous notified failure are suppressed
+// until the record status changes, and a recovery message is sent
+// when a failing record succeeds again. If called between BeginCycle
+// and EndCycle, the notification is buffered instead of being sent.
+func (c *Client) NotifyRecord(key, record string, status models.Status, message string) {
+	c.mutex.Lock()
+	line, notify := c.states.update(key, record, status, message)
+	if !notify {
+		c.mutex.Unlock()
+		return
+	} else if c.inCycle {
+		c.pending = append(c.pending, line)
+		c.mutex.Unlock()
+		return
+	}
+	c.mutex.Unlock()
+	c.Notify(line)
+}
+
+type recordState struct {
+	status  models.Status
+	message string
+}
+
+// recordStates maps a record key to its last notified state.
+type recordStates map[string]recordState
+
+func (r recordStates) update(key, record string, status models.Status,
+	message string,
+) (line string, notify bool) {
+	previous, exists := r[key]
+	r[key] = recordState{status: status, message: message}
+	wasFailing := exists && previous.status == constants.FAIL
+
+	switch status {
+	case constants.FAIL:
+		if wasFailing && previous.message == message {
+			return "", false
+		}
+		return record + ": " + message, true
+	case constants.SUCCESS:
+		if wasFailing {
+			return record + " recovered: " + message, true
+		}
+		return record + " " + message, true
+	case constants.DRIFTED:
+		return record + " externally modified: " + message, true
+	case constants.FAILEDOVER:
+		return record + " failed over: " + message, true
+	case constants.PINNED:
+		return record + " pinned: " + message, true
+	case constants.UNSET:
+		if exists && previous.status == constants.PINNED {
+			return record + " unpinned: " + message, true
+		}
+		return "", false
+	default:
+		return "", false
+	}
+}
+
+func makeDigest(lines []string) (message string) {
+	if len(lines) == 1 {
+		return lines[0]
+	}
+	return strconv.Itoa(len(lines)) + " record notifications:\n- " +
+		strings.Join(lines, "\n- ")
+}
@@ -0,0 +1,152 @@
+package shoutrrr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_recordStates_update(t *testing.T) {
+	t.Parallel()
+
+	type call struct {
+		key     string // defaults to "a.com on cloudflare"
+		status  models.Status
+		message string
+		line    string
+		notify  bool
+	}
+
+	testCases := map[string]struct {
+		calls []call
+	}{
+		"success": {
+			calls: []call{
+				{status: constants.SUCCESS, message: "changed to 1.2.3.4", line: "a.com changed to 1.2.3.4", notify: true},
+			},
+		},
+		"repeated_identical_failures": {
+			calls: []call{
+				{status: constants.FAIL, message: "bad auth", line: "a.com: bad auth", notify: true},
+				{status: constants.FAIL, message: "bad auth"},
+				{status: constants.FAIL, message: "timeout", line: "a.com: timeout", notify: true},
+			},
+		},
+		"recovery": {
+			calls: []call{
+				{status: constants.FAIL, message: "bad auth", line: "a.com: bad auth", notify: true},
+				{status: constants.SUCCESS, message: "changed to 1.2.3.4", line: "a.com recovered: changed to 1.2.3.4", notify: true},
+				{status: constants.FAIL, message: "bad auth", line: "a.com: bad auth", notify: true},
+			},
+		},
+		"same_domain_other_provider": {
+			calls: []call{
+				{status: constants.FAIL, message: "bad auth", line: "a.com: bad auth", notify: true},
+				{key: "a.com on gcp", status: constants.FAIL, message: "bad auth", line: "a.com: bad auth", notify: true},
+				{status: constants.FAIL, message: "bad auth"},
+			},
+		},
+		"externally_modified": {
+			calls: []call{
+				{
+					status:  constants.DRIFTED,
+					message: "was 5.6.7.8, restored to 1.2.3.4",
+					line:    "a.com externally modified: was 5.6.7.8, restored to 1.2.3.4",
+					notify:  true,
+				},
+			},
+		},
+		"pinned_and_unpinned": {
+			calls: []call{
+				{
+					status:  constants.PINNED,
+					message: "pinned to 1.2.3.4 until 2026-01-01 00:00:00",
+					line:    "a.com pinned: pinned to 1.2.3.4 until 2026-01-01 00:00:00",
+					notify:  true,
+				},
+				{status: constants.UNSET, message: "pin expired", line: "a.com unpinned: pin expired", notify: true},
+				{status: constants.UNSET, message: "pin expired"},
+			},
+		},
+		"ignored_status": {
+			calls: []call{
+				{status: constants.UPTODATE},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			states := make(recordStates)
+			for i, call := range testCase.calls {
+				key := call.key
+				if key == "" {
+					key = "a.com on cloudflare"
+				}
+				line, notify := states.update(key, "a.com", call.status, call.message)
+				assert.Equal(t, call.line, line, "call %d", i)
+				assert.Equal(t, call.notify, notify, "call %d", i)
+			}
+		})
+	}
+}
+
+func Test_makeDigest(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		lines   []string
+		message string
+	}{
+		"single_line": {
+			lines:   []string{"a.com changed to 1.2.3.4"},
+			message: "a.com changed to 1.2.3.4",
+		},
+		"multiple_lines": {
+			lines:   []string{"a.com changed to 1.2.3.4", "b.com: bad auth"},
+			message: "2 record notifications:\n- a.com changed to 1.2.3.4\n- b.com: bad auth",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			message := makeDigest(testCase.lines)
+
+			assert.Equal(t, testCase.message, message)
+		})
+	}
+}
+
+func Test_Client_release(t *testing.T) {
+	t.Parallel()
+
+	quietWindows, err := schedule.ParseCronWindows("CRON_TZ=UTC * 9-16 * * *")
+	require.NoError(t, err)
+	now := time.Date(2025, time.January, 15, 9, 0, 0, 0, time.UTC)
+	client := &Client{
+		quietWindows: quietWindows,
+		timeNow:      func() time.Time { return now },
+	}
+
+	assert.Empty(t, client.release([]string{"a.com changed to 1.2.3.4"}))
+	assert.Empty(t, client.release(nil))
+	assert.Empty(t, client.release([]string{"b.com: bad auth"}))
+
+	now = now.Add(8 * time.Hour)
+	lines := client.release([]string{"c.com changed to 5.6.7.8"})
+	assert.Equal(t, []string{
+		"a.com changed to 1.2.3.4",
+		"b.com: bad auth",
+		"c.com changed to 5.6.7.8",
+	}, lines)
+	assert.Empty(t, client.release(nil))
+}
@@ -1,5 +1,15 @@
 package shoutrrr
 
+import "github.com/qdm12/ddns-updater/internal/records"
+
 type Erroer interface {
 	Error(s string)
 }
+
+type Database interface {
+	SelectAll() (records []records.Record)
+}
+
+type Notifier interface {
+	Notify(message string)
+}
@@ -2,8 +2,10 @@ package shoutrrr
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/containrrr/shoutrrr"
+	"github.com/qdm12/ddns-updater/internal/schedule"
 	"github.com/qdm12/gosettings"
 )
 
@@ -11,12 +13,20 @@ type Settings struct {
 	Addresses    []string
 	DefaultTitle string
 	Logger       Erroer
+	// QuietWindows are the windows during which notifications
+	// are held, and is nil to never hold notifications.
+	QuietWindows *schedule.CronWindows
+	// TimeNow defaults to time.Now if left unset.
+	TimeNow func() time.Time
 }
 
 func (s *Settings) setDefaults() {
 	s.Addresses = gosettings.DefaultSlice(s.Addresses, []string{})
 	s.DefaultTitle = gosettings.DefaultComparable(s.DefaultTitle, "DDNS Updater")
 	s.Logger = gosettings.DefaultComparable[Erroer](s.Logger, &noopLogger{})
+	if s.TimeNow == nil {
+		s.TimeNow = time.Now
+	}
 }
 
 func (s Settings) validate() (err error) {
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/containrrr/shoutrrr"
 	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/qdm12/ddns-updater/internal/schedule"
 )
 
 type Client struct {
@@ -14,6 +17,16 @@ type Client struct {
 	serviceNames  []string
 	defaultTitle  string
 	logger        Erroer
+	quietWindows  *schedule.CronWindows
+	timeNow       func() time.Time
+
+	// Record notifications state
+	mutex   sync.Mutex
+	states  recordStates
+	inCycle bool
+	pending []string
+	// held are the notifications held during quiet windows.
+	held []string
 }
 
 func New(settings Settings) (client *Client, err error) {
@@ -42,10 +55,40 @@ func New(settings Settings) (client *Client, err error) {
 		serviceNames:  serviceNames,
 		defaultTitle:  settings.DefaultTitle,
 		logger:        settings.Logger,
+		quietWindows:  settings.QuietWindows,
+		timeNow:       settings.TimeNow,
+		states:        make(recordStates),
 	}, nil
 }
 
+// Notify sends the message given, or holds it if the current time is
+// within a quiet window, to send it with the other notifications held
+// as a single message after the quiet window.
 func (c *Client) Notify(message string) {
+	c.mutex.Lock()
+	lines := c.release([]string{message})
+	c.mutex.Unlock()
+	if len(lines) == 0 {
+		return
+	}
+	c.send(makeDigest(lines))
+}
+
+// release returns the notifications held followed by the lines given,
+// or holds them all and returns nil if the current time is within
+// a quiet window. It must be called with the mutex locked.
+func (c *Client) release(lines []string) (toSend []string) {
+	if c.quietWindows.Contains(c.timeNow()) {
+		c.held = append(c.held, lines...)
+		return nil
+	}
+	toSend = append(toSend, c.held...)
+	toSend = append(toSend, lines...)
+	c.held = nil
+	return toSend
+}
+
+func (c *Client) send(message string) {
 	errs := c.serviceRouter.Send(message, nil)
 	for i, err := range errs {
 		if err != nil {
@@ -0,0 +1,113 @@
+package shoutrrr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// Summary periodically sends a summary notification of all records,
+// built from their status and history.
+type Summary struct {
+	// Injected fields
+	period  time.Duration
+	db      Database
+	client  Notifier
+	timeNow func() time.Time
+
+	// Internal fields
+	stopCh chan<- struct{}
+	done   <-chan struct{}
+}
+
+func NewSummary(period time.Duration, db Database, client Notifier,
+	timeNow func() time.Time,
+) *Summary {
+	return &Summary{
+		period:  period,
+		db:      db,
+		client:  client,
+		timeNow: timeNow,
+	}
+}
+
+func (s *Summary) String() string {
+	return "notifications summary"
+}
+
+func (s *Summary) Start(ctx context.Context) (runError <-chan error, startErr error) {
+	ready := make(chan struct{})
+	stopCh := make(chan struct{})
+	s.stopCh = stopCh
+	done := make(chan struct{})
+	s.done = done
+	go s.run(ready, stopCh, done)
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return nil, s.Stop()
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (s *Summary) run(ready chan<- struct{}, stopCh <-chan struct{},
+	done chan<- struct{},
+) {
+	defer close(done)
+	ticker := time.NewTicker(s.period)
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stopCh:
+			ticker.Stop()
+			return
+		}
+		now := s.timeNow()
+		message := buildSummary(s.db.SelectAll(), s.period, now)
+		s.client.Notify(message)
+	}
+}
+
+func (s *Summary) Stop() (err error) {
+	close(s.stopCh)
+	<-s.done
+	return nil
+}
+
+func buildSummary(records []records.Record, period time.Duration,
+	now time.Time,
+) (message string) {
+	since := now.Add(-period)
+	lines := make([]string, len(records))
+	for i, record := range records {
+		changes := 0
+		for _, event := range record.History {
+			if event.Time.After(since) {
+				changes++
+			}
+		}
+
+		ipAndTime := "no IP set yet"
+		if ips := record.CurrentIPs(); len(ips) > 0 {
+			ipStrings := make([]string, len(ips))
+			for i, ip := range ips {
+				ipStrings[i] = ip.String()
+			}
+			ipAndTime = strings.Join(ipStrings, ", ") + " set " +
+				record.History.GetDurationSinceSuccess(now) + " ago"
+		}
+
+		lines[i] = fmt.Sprintf("- %s (%s): %s, %s, %d IP change(s)",
+			record.Provider.BuildDomainName(), record.IPVersionString(),
+			record.Status, ipAndTime, changes)
+	}
+
+	return fmt.Sprintf("Summary since %s for %d record(s):\n%s",
+		since.Format("2006-01-02 15:04 MST"), len(records),
+		strings.Join(lines, "\n"))
+}
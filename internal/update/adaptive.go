@@ -0,0 +1,91 @@
+package update
+
+import (
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/schedule"
+)
+
+// getPeriodNext returns the next update time of a record following
+// the global period, which is adapted to the record recent changes
+// and failures if adaptive scheduling is enabled, and moved right
+// after the ISP reconnect window if there is one.
+func (s *Service) getPeriodNext(record librecords.Record, now time.Time) time.Time {
+	next := now.Add(s.getAdaptivePeriod(record, now))
+
+	window := s.getReconnectWindow(record, now.Location())
+	if window == nil {
+		return next
+	}
+	// The new IP address may take a little while to be assigned
+	// once the reconnection is done.
+	const reconnectMargin = time.Minute
+	windowEnd := window.NextEnd(now).Add(reconnectMargin)
+	switch {
+	case windowEnd.Before(next):
+		return windowEnd
+	case window.Contains(next):
+		return window.NextEnd(next).Add(reconnectMargin)
+	default:
+		return next
+	}
+}
+
+// getAdaptivePeriod returns a shorter period for a while after the
+// record IP address changed or the record started failing, and a
+// longer period if the record IP address is stable for days.
+func (s *Service) getAdaptivePeriod(record librecords.Record, now time.Time) time.Duration {
+	if !s.adaptive {
+		return s.period
+	}
+
+	key := recordKey(record)
+	if record.Status != constants.FAIL {
+		delete(s.failingSince, key)
+	} else if _, ok := s.failingSince[key]; !ok {
+		s.failingSince[key] = now
+	}
+	failingSince, failing := s.failingSince[key]
+
+	const (
+		fastDuration   = time.Hour
+		stableDuration = 72 * time.Hour
+		minFastPeriod  = 30 * time.Second
+		fastDivisor    = 4
+		slowFactor     = 4
+	)
+	sinceChange := now.Sub(record.History.GetSuccessTime())
+	switch {
+	case failing && now.Sub(failingSince) < fastDuration,
+		len(record.History) > 1 && sinceChange < fastDuration:
+		return min(s.period, max(s.period/fastDivisor, minFastPeriod))
+	case len(record.History) > 0 && sinceChange > stableDuration:
+		return slowFactor * s.period
+	default:
+		return s.period
+	}
+}
+
+// getReconnectWindow returns the reconnect window configured, or else
+// the reconnect window learned from the record history if adaptive
+// scheduling is enabled. It returns nil if there is none.
+func (s *Service) getReconnectWindow(record librecords.Record,
+	location *time.Location,
+) *schedule.Window {
+	if s.reconnectWindow != nil {
+		return s.reconnectWindow
+	} else if !s.adaptive {
+		return nil
+	}
+
+	changeTime, ok := record.History.GetTypicalChangeTime(location)
+	if !ok {
+		return nil
+	}
+	return &schedule.Window{
+		Start: changeTime.Truncate(time.Hour),
+		End:   changeTime + time.Minute,
+	}
+}
@@ -0,0 +1,123 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	providerconstants "github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/schedule"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Service_getPeriodNext(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+	makeRecord := func(status models.Status, changeTimes ...time.Time) records.Record {
+		recordProvider := makeTestProvider(t, providerconstants.Example, "sub", ipversion.IP4, netip.Prefix{})
+		events := make([]models.HistoryEvent, len(changeTimes))
+		for i, changeTime := range changeTimes {
+			events[i] = models.HistoryEvent{
+				IP:   netip.AddrFrom4([4]byte{1, 2, 3, byte(i)}),
+				Time: changeTime,
+			}
+		}
+		record := records.New(recordProvider, records.Settings{}, events)
+		record.Status = status
+		return record
+	}
+	daysAgo := func(days, hour, minute int) time.Time {
+		return time.Date(2025, time.January, 15-days, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := map[string]struct {
+		adaptive        bool
+		reconnectWindow *schedule.Window
+		record          records.Record
+		next            time.Time
+	}{
+		"not_adaptive": {
+			record: makeRecord(constants.FAIL),
+			next:   now.Add(10 * time.Minute),
+		},
+		"recent_change": {
+			adaptive: true,
+			record:   makeRecord(constants.SUCCESS, daysAgo(5, 8, 0), now.Add(-time.Minute)),
+			next:     now.Add(150 * time.Second),
+		},
+		"failing": {
+			adaptive: true,
+			record:   makeRecord(constants.FAIL, daysAgo(1, 8, 0)),
+			next:     now.Add(150 * time.Second),
+		},
+		"stable": {
+			adaptive: true,
+			record:   makeRecord(constants.UPTODATE, daysAgo(5, 8, 0)),
+			next:     now.Add(40 * time.Minute),
+		},
+		"configured_reconnect_window": {
+			reconnectWindow: &schedule.Window{Start: 12 * time.Hour, End: 12*time.Hour + 5*time.Minute},
+			record:          makeRecord(constants.UPTODATE),
+			next:            now.Add(6 * time.Minute),
+		},
+		"next_within_reconnect_window": {
+			reconnectWindow: &schedule.Window{Start: 12*time.Hour + 5*time.Minute, End: 12*time.Hour + 20*time.Minute},
+			record:          makeRecord(constants.UPTODATE),
+			next:            now.Add(21 * time.Minute),
+		},
+		"learned_reconnect_window": {
+			adaptive: true,
+			record: makeRecord(constants.UPTODATE, daysAgo(9, 8, 0),
+				daysAgo(7, 12, 2), daysAgo(6, 12, 4), daysAgo(5, 12, 3)),
+			next: now.Add(6 * time.Minute),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			service := &Service{
+				period:          10 * time.Minute,
+				adaptive:        testCase.adaptive,
+				reconnectWindow: testCase.reconnectWindow,
+				failingSince:    make(map[string]time.Time),
+			}
+
+			next := service.getPeriodNext(testCase.record, now)
+
+			assert.Equal(t, testCase.next, next)
+		})
+	}
+}
+
+func Test_Service_getAdaptivePeriod_sameDomainOtherProvider(t *testing.T) {
+	t.Parallel()
+
+	makeFailingRecord := func(providerName models.Provider) records.Record {
+		recordProvider := makeTestProvider(t, providerName, "sub", ipversion.IP4, netip.Prefix{})
+		record := records.New(recordProvider, records.Settings{}, nil)
+		record.Status = constants.FAIL
+		return record
+	}
+	failingLong := makeFailingRecord(providerconstants.Example)
+	failingNow := makeFailingRecord(providerconstants.NoIP)
+
+	service := &Service{
+		period:       10 * time.Minute,
+		adaptive:     true,
+		failingSince: make(map[string]time.Time),
+	}
+
+	start := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+	service.getAdaptivePeriod(failingLong, start)
+
+	now := start.Add(2 * time.Hour)
+	assert.Equal(t, 10*time.Minute, service.getAdaptivePeriod(failingLong, now))
+	assert.Equal(t, 150*time.Second, service.getAdaptivePeriod(failingNow, now))
+}
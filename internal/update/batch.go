@@ -0,0 +1,145 @@
+package update
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/provider/utils"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+var ErrBatchUnsupported = errors.New("provider does not support batch updates")
+
+// UpdateBatch updates the records with the identifiers given to the IP
+// address given in a single API call. The records must be of the same
+// provider account and batch key, and their provider must implement
+// provider.BatchUpdater. The errors of the records which failed to
+// update are returned joined together.
+func (u *Updater) UpdateBatch(ctx context.Context, ids []uint, ip netip.Addr) (err error) {
+	var recordErrs []error
+	batch, err := u.applyBatch(ctx, ids, func(ctx context.Context, batch []librecords.Record) (err error) {
+		batchUpdater, ok := batch[0].Provider.(provider.BatchUpdater)
+		if !ok {
+			return fmt.Errorf("%w", ErrBatchUnsupported)
+		}
+		hostnames := make([]string, len(batch))
+		for i, record := range batch {
+			hostnames[i] = utils.BuildURLQueryHostname(record.Provider.Owner(), record.Provider.Domain())
+		}
+		network := ipVersionToNetwork(batch[0].Provider.IPVersion())
+		client := u.clientFor(batch[0].Settings, network)
+		recordErrs, err = batchUpdater.UpdateBatch(ctx, client, hostnames, ip)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	now := u.timeNow()
+	errs := make([]error, 0, len(ids))
+	for i, id := range ids {
+		record := batch[i]
+		if recordErrs[i] != nil {
+			errs = append(errs, fmt.Errorf("record %s: %w", recordToLogString(record), recordErrs[i]))
+			_, err = u.fail(id, record, recordErrs[i])
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		record.Status = constants.SUCCESS
+		record.Message = "changed to " + ip.String()
+		record.StaleRecordTypes = removeRecordTypeOf(record.StaleRecordTypes, ip)
+		record.History = append(record.History, models.HistoryEvent{
+			IP:   ip,
+			Time: now,
+		})
+		u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+		err = u.db.Update(id, record)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+const (
+	// minBatchSize is the minimum number of records updated in a single
+	// API call, since a single record is updated as usual.
+	minBatchSize = 2
+	// maxBatchSize is the maximum number of records updated in a single
+	// API call, since DynDNS2 style APIs accept up to 20 hostnames.
+	maxBatchSize = 20
+)
+
+type batchKey struct {
+	account   string
+	batchKey  string
+	ipVersion ipversion.IPVersion
+	ip        netip.Addr
+}
+
+// makeBatches groups the records with the identifiers given which can be
+// updated together in a single API call, being of the same provider account,
+// batch key and update IP address. It returns the batches of at least two
+// records, sorted by their first identifier, and the identifiers of the
+// records left to update one by one.
+func makeBatches(records []librecords.Record, recordIDs map[uint]struct{},
+	ip, ipv4, ipv6 netip.Addr,
+) (batches [][]uint, remainingIDs map[uint]struct{}) {
+	keyToIDs := make(map[batchKey][]uint)
+	for id := range recordIDs {
+		record := records[id]
+		batchUpdater, ok := record.Provider.(provider.BatchUpdater)
+		if !ok || record.Settings.AutoIP || record.Settings.DualStack ||
+			record.Settings.RecordType != "" {
+			continue
+		}
+		updateIPs := getUpdateIPs(record, ip, ipv4, ipv6)
+		if len(updateIPs) == 0 {
+			continue
+		}
+		key := batchKey{
+			account:   record.Settings.Account,
+			batchKey:  batchUpdater.BatchKey(),
+			ipVersion: record.Provider.IPVersion(),
+			ip:        updateIPs[0],
+		}
+		keyToIDs[key] = append(keyToIDs[key], id)
+	}
+
+	remainingIDs = maps.Clone(recordIDs)
+	for _, ids := range keyToIDs {
+		slices.Sort(ids)
+		for chunk := range slices.Chunk(ids, maxBatchSize) {
+			if len(chunk) < minBatchSize {
+				continue
+			}
+			batches = append(batches, chunk)
+			for _, id := range chunk {
+				delete(remainingIDs, id)
+			}
+		}
+	}
+	slices.SortFunc(batches, func(a, b []uint) int {
+		return cmp.Compare(a[0], b[0])
+	})
+	return batches, remainingIDs
+}
+
+func selectBatchRecords(records []librecords.Record, batch []uint) (batchRecords []librecords.Record) {
+	batchRecords = make([]librecords.Record, len(batch))
+	for i, id := range batch {
+		batchRecords[i] = records[id]
+	}
+	return batchRecords
+}
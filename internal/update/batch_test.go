@@ -0,0 +1,123 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_makeBatches(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(providerName models.Provider, owner string,
+		ipVersion ipversion.IPVersion, account string,
+	) records.Record {
+		recordProvider := makeTestProvider(t, providerName, owner, ipVersion, netip.Prefix{})
+		return records.New(recordProvider, records.Settings{Account: account}, nil)
+	}
+
+	ipv4 := netip.MustParseAddr("1.2.3.4")
+	ipv6 := netip.MustParseAddr("::1")
+
+	testCases := map[string]struct {
+		records      []records.Record
+		recordIDs    map[uint]struct{}
+		batches      [][]uint
+		remainingIDs map[uint]struct{}
+	}{
+		"no_batch_updater": {
+			records: []records.Record{
+				makeRecord(constants.Example, "a", ipversion.IP4, "example:1"),
+				makeRecord(constants.Example, "b", ipversion.IP4, "example:1"),
+			},
+			recordIDs:    map[uint]struct{}{0: {}, 1: {}},
+			remainingIDs: map[uint]struct{}{0: {}, 1: {}},
+		},
+		"single_record": {
+			records: []records.Record{
+				makeRecord(constants.NoIP, "a", ipversion.IP4, "noip:1"),
+			},
+			recordIDs:    map[uint]struct{}{0: {}},
+			remainingIDs: map[uint]struct{}{0: {}},
+		},
+		"same_account": {
+			records: []records.Record{
+				makeRecord(constants.NoIP, "a", ipversion.IP4, "noip:1"),
+				makeRecord(constants.NoIP, "b", ipversion.IP4, "noip:1"),
+				makeRecord(constants.NoIP, "c", ipversion.IP4, "noip:1"),
+			},
+			recordIDs:    map[uint]struct{}{0: {}, 1: {}, 2: {}},
+			batches:      [][]uint{{0, 1, 2}},
+			remainingIDs: map[uint]struct{}{},
+		},
+		"not_selected": {
+			records: []records.Record{
+				makeRecord(constants.NoIP, "a", ipversion.IP4, "noip:1"),
+				makeRecord(constants.NoIP, "b", ipversion.IP4, "noip:1"),
+				makeRecord(constants.NoIP, "c", ipversion.IP4, "noip:1"),
+			},
+			recordIDs:    map[uint]struct{}{0: {}, 2: {}},
+			batches:      [][]uint{{0, 2}},
+			remainingIDs: map[uint]struct{}{},
+		},
+		"different_accounts": {
+			records: []records.Record{
+				makeRecord(constants.NoIP, "a", ipversion.IP4, "noip:1"),
+				makeRecord(constants.NoIP, "b", ipversion.IP4, "noip:2"),
+				makeRecord(constants.NoIP, "c", ipversion.IP4, "noip:1"),
+				makeRecord(constants.NoIP, "d", ipversion.IP4, "noip:2"),
+			},
+			recordIDs:    map[uint]struct{}{0: {}, 1: {}, 2: {}, 3: {}},
+			batches:      [][]uint{{0, 2}, {1, 3}},
+			remainingIDs: map[uint]struct{}{},
+		},
+		"different_ip_versions": {
+			records: []records.Record{
+				makeRecord(constants.NoIP, "a", ipversion.IP4, "noip:1"),
+				makeRecord(constants.NoIP, "b", ipversion.IP6, "noip:1"),
+				makeRecord(constants.NoIP, "c", ipversion.IP6, "noip:1"),
+			},
+			recordIDs:    map[uint]struct{}{0: {}, 1: {}, 2: {}},
+			batches:      [][]uint{{1, 2}},
+			remainingIDs: map[uint]struct{}{0: {}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			batches, remainingIDs := makeBatches(testCase.records, testCase.recordIDs,
+				netip.Addr{}, ipv4, ipv6)
+
+			assert.Equal(t, testCase.batches, batches)
+			assert.Equal(t, testCase.remainingIDs, remainingIDs)
+		})
+	}
+}
+
+func Test_makeBatches_maxBatchSize(t *testing.T) {
+	t.Parallel()
+
+	const recordsCount = maxBatchSize + 1
+	recs := make([]records.Record, recordsCount)
+	recordIDs := make(map[uint]struct{}, recordsCount)
+	for i := range recs {
+		recordProvider := makeTestProvider(t, constants.NoIP, "@", ipversion.IP4, netip.Prefix{})
+		recs[i] = records.New(recordProvider, records.Settings{Account: "noip:1"}, nil)
+		recordIDs[uint(i)] = struct{}{}
+	}
+
+	batches, remainingIDs := makeBatches(recs, recordIDs, netip.Addr{},
+		netip.MustParseAddr("1.2.3.4"), netip.Addr{})
+
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], maxBatchSize)
+	assert.Equal(t, map[uint]struct{}{maxBatchSize: {}}, remainingIDs)
+}
@@ -0,0 +1,58 @@
+package update
+
+import (
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/internal/ipexpr"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// computeIP returns the IP address to set the record to for the public
+// IP address given, which is invalid if not found. The IP version given
+// is the one the public IP address was fetched for, and is used to pick
+// the IP expression of the record if the public IP address is invalid,
+// since static expressions do not need it.
+// The public IP address is returned with the IPv6 suffix of the record
+// applied if no IP expression of the record matches its IP family, where
+// the IPv6 suffix is the host identifier following the delegated IPv6
+// prefix if the record defines how to obtain this prefix.
+// The IP address returned is invalid if the delegated IPv6 prefix
+// length cannot be learned.
+func computeIP(record librecords.Record, publicIP netip.Addr,
+	ipVersion ipversion.IPVersion,
+) (ip netip.Addr) {
+	for _, expression := range record.Settings.IPExpressions {
+		var matches bool
+		switch {
+		case publicIP.IsValid():
+			matches = publicIP.Is4() == expression.Is4()
+		case ipVersion == ipversion.IP4or6:
+			matches = expression.IsStatic()
+		default:
+			matches = (ipVersion == ipversion.IP4) == expression.Is4()
+		}
+		if !matches {
+			continue
+		}
+		prefixBits := ipexpr.DefaultPrefixBits
+		if expression.UsesPrefix() && publicIP.IsValid() && record.Settings.IPv6Prefix != nil {
+			var err error
+			prefixBits, err = record.Settings.IPv6Prefix.Bits(publicIP)
+			if err != nil {
+				return netip.Addr{}
+			}
+		}
+		return expression.Compute(publicIP, prefixBits)
+	}
+
+	ipv6Suffix := record.Provider.IPv6Suffix()
+	if record.Settings.IPv6Prefix != nil && publicIP.Is6() && ipv6Suffix.IsValid() {
+		prefixBits, err := record.Settings.IPv6Prefix.Bits(publicIP)
+		if err != nil {
+			return netip.Addr{}
+		}
+		ipv6Suffix = netip.PrefixFrom(ipv6Suffix.Addr(), publicIP.BitLen()-prefixBits)
+	}
+	return ipv6WithSuffix(publicIP, ipv6Suffix)
+}
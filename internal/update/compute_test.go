@@ -0,0 +1,110 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/ipexpr"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_computeIP(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(ipv6Suffix netip.Prefix, ipv6Prefix string,
+		expressionStrings ...string,
+	) records.Record {
+		recordProvider := makeTestProvider(t, constants.NoIP, "@", ipversion.IP4or6, ipv6Suffix)
+		expressions := make([]ipexpr.Expression, len(expressionStrings))
+		for i, expressionString := range expressionStrings {
+			var err error
+			expressions[i], err = ipexpr.Parse(expressionString)
+			require.NoError(t, err)
+		}
+		settings := records.Settings{IPExpressions: expressions}
+		if ipv6Prefix != "" {
+			prefix, err := ipexpr.ParsePrefix(ipv6Prefix)
+			require.NoError(t, err)
+			settings.IPv6Prefix = &prefix
+		}
+		return records.New(recordProvider, settings, nil)
+	}
+
+	testCases := map[string]struct {
+		record    records.Record
+		publicIP  netip.Addr
+		ipVersion ipversion.IPVersion
+		ip        netip.Addr
+	}{
+		"no_expression": {
+			record:    makeRecord(netip.Prefix{}, ""),
+			publicIP:  netip.MustParseAddr("198.51.100.1"),
+			ipVersion: ipversion.IP4,
+			ip:        netip.MustParseAddr("198.51.100.1"),
+		},
+		"ipv6_suffix": {
+			record:    makeRecord(netip.MustParsePrefix("::10/64"), ""),
+			publicIP:  netip.MustParseAddr("2001:db8::1"),
+			ipVersion: ipversion.IP6,
+			ip:        netip.MustParseAddr("2001:db8::10"),
+		},
+		"ipv6_suffix_with_prefix": {
+			record:    makeRecord(netip.MustParsePrefix("::10/64"), "/56"),
+			publicIP:  netip.MustParseAddr("2001:db8:0:aa01::1"),
+			ipVersion: ipversion.IP6,
+			ip:        netip.MustParseAddr("2001:db8:0:aa00::10"),
+		},
+		"host_expression_default_prefix": {
+			record:    makeRecord(netip.Prefix{}, "", "host(::10)"),
+			publicIP:  netip.MustParseAddr("2001:db8:0:aa01::1"),
+			ipVersion: ipversion.IP6,
+			ip:        netip.MustParseAddr("2001:db8:0:aa01::10"),
+		},
+		"host_expression_with_prefix": {
+			record:    makeRecord(netip.Prefix{}, "/56", "host(0:0:0:2::10)"),
+			publicIP:  netip.MustParseAddr("2001:db8:0:aa01::1"),
+			ipVersion: ipversion.IP6,
+			ip:        netip.MustParseAddr("2001:db8:0:aa02::10"),
+		},
+		"expression_of_other_family": {
+			record:    makeRecord(netip.Prefix{}, "", "static(203.0.113.10)"),
+			publicIP:  netip.MustParseAddr("2001:db8::1"),
+			ipVersion: ipversion.IP4or6,
+			ip:        netip.MustParseAddr("2001:db8::1"),
+		},
+		"expression_of_family": {
+			record:    makeRecord(netip.Prefix{}, "", "suffix(::10/64)", "map(198.51.100.1=203.0.113.5)"),
+			publicIP:  netip.MustParseAddr("198.51.100.1"),
+			ipVersion: ipversion.IP4,
+			ip:        netip.MustParseAddr("203.0.113.5"),
+		},
+		"static_without_public_ip": {
+			record:    makeRecord(netip.Prefix{}, "", "static(2001:db8::5)"),
+			ipVersion: ipversion.IP6,
+			ip:        netip.MustParseAddr("2001:db8::5"),
+		},
+		"static_of_other_family_without_public_ip": {
+			record:    makeRecord(netip.Prefix{}, "", "static(2001:db8::5)"),
+			ipVersion: ipversion.IP4,
+		},
+		"static_without_public_ip_of_any_family": {
+			record:    makeRecord(netip.Prefix{}, "", "static(203.0.113.10)"),
+			ipVersion: ipversion.IP4or6,
+			ip:        netip.MustParseAddr("203.0.113.10"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ip := computeIP(testCase.record, testCase.publicIP, testCase.ipVersion)
+
+			assert.Equal(t, testCase.ip, ip)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+var ErrContentUnsupported = errors.New("provider does not support content records")
+
+// UpdateContent sets the content of the record to its content template
+// executed with the IP addresses given.
+func (u *Updater) UpdateContent(ctx context.Context, id uint, ips []netip.Addr) (err error) {
+	var content string
+	record, err := u.apply(ctx, id, func(ctx context.Context, record librecords.Record) (err error) {
+		contentUpdater, ok := record.Provider.(provider.ContentUpdater)
+		if !ok {
+			return fmt.Errorf("%w", ErrContentUnsupported)
+		}
+		content, err = librecords.RenderContent(record.Settings.Content, record.Provider, ips)
+		if err != nil {
+			return fmt.Errorf("rendering content: %w", err)
+		}
+		return contentUpdater.UpdateContent(ctx, u.clientFor(record.Settings, ""),
+			record.Settings.RecordType, content)
+	})
+	if err != nil {
+		return err
+	}
+	record.Status = constants.SUCCESS
+	record.Message = "set " + record.Settings.RecordType + " record to " + content
+	appendHistory(&record, ips, u.timeNow())
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+// shouldUpdateContentRecord returns true if the record content has to be
+// set with the IP addresses given. The content is set once after the
+// program starts, since the history of content records is not persisted,
+// and then each time the IP addresses change.
+func (s *Service) shouldUpdateContentRecord(record librecords.Record,
+	updateIPs []netip.Addr,
+) (update bool) {
+	hostname := record.Provider.BuildDomainName()
+	if len(updateIPs) == 0 {
+		s.logger.Warn(fmt.Sprintf("Skipping update for %s %s record because no IP address was found",
+			hostname, record.Settings.RecordType))
+		return false
+	} else if record.Status == constants.UNSET {
+		return true
+	}
+
+	currentIPs := record.CurrentIPs()
+	if slices.Equal(currentIPs, updateIPs) {
+		s.logger.Debug(fmt.Sprintf("%s record of %s was set with %s, skipping update",
+			record.Settings.RecordType, hostname, ipsToString(updateIPs)))
+		return false
+	}
+	s.logger.Info(fmt.Sprintf("%s record of %s was set with %s, trying to update with %s",
+		record.Settings.RecordType, hostname, ipsToString(currentIPs), ipsToString(updateIPs)))
+	return true
+}
+
+// getContentUpdateIPs returns the IP addresses to execute the content
+// template of the record with. For dual-stack records, the current IP
+// address of an IP family is used if no public IP address of this
+// family is found, so that the content keeps it.
+func getContentUpdateIPs(record librecords.Record, ip, ipv4, ipv6 netip.Addr) (
+	updateIPs []netip.Addr,
+) {
+	updateIPs = getUpdateIPs(record, ip, ipv4, ipv6)
+	if !record.Settings.DualStack || len(updateIPs) != 1 {
+		return updateIPs
+	}
+	if updateIPs[0].Is4() {
+		currentIPv6 := record.History.GetCurrentIP6()
+		if currentIPv6.IsValid() {
+			updateIPs = append(updateIPs, currentIPv6)
+		}
+		return updateIPs
+	}
+	currentIPv4 := record.History.GetCurrentIP4()
+	if currentIPv4.IsValid() {
+		updateIPs = []netip.Addr{currentIPv4, updateIPs[0]}
+	}
+	return updateIPs
+}
@@ -0,0 +1,94 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/provider"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// getDualStackIPs returns the IPv4 and IPv6 addresses to set for the
+// dual-stack record given, where either can be invalid if not found.
+func getDualStackIPs(record librecords.Record, ipv4, ipv6 netip.Addr) (
+	recordIPv4, recordIPv6 netip.Addr,
+) {
+	return computeIP(record, ipv4, ipversion.IP4), computeIP(record, ipv6, ipversion.IP6)
+}
+
+func (s *Service) shouldUpdateDualStackRecord(ctx context.Context,
+	record librecords.Record, ipv4, ipv6 netip.Addr,
+) (update bool) {
+	hostname := record.Provider.BuildDomainName()
+	ipv4, ipv6 = getDualStackIPs(record, ipv4, ipv6)
+	var publicIPs []netip.Addr
+	for _, ip := range []netip.Addr{ipv4, ipv6} {
+		if ip.IsValid() {
+			publicIPs = append(publicIPs, ip)
+		}
+	}
+
+	if len(publicIPs) == 0 {
+		s.logger.Warn("Skipping update for " + hostname +
+			" because neither IPv4 nor IPv6 address was found")
+		return false
+	}
+
+	for _, publicIP := range publicIPs {
+		recordType := ipToRecordType(publicIP)
+		if slices.Contains(record.StaleRecordTypes, recordType) {
+			s.logger.Info(fmt.Sprintf("%s address is available again for stale %s record %s",
+				ipToIPKind(publicIP), recordType, hostname))
+			return true
+		}
+	}
+
+	var recordIPs []netip.Addr
+	reader, ok := record.Provider.(provider.RecordReader)
+	if ok {
+		var err error
+		recordIPs, err = s.updater.ReadRecord(ctx, reader, record.Settings)
+		if err != nil {
+			s.logger.Warn("reading record " + hostname + " from provider API: " + err.Error())
+			ok = false // fall back on the stored IP addresses or a DNS lookup
+		}
+	}
+
+	switch {
+	case ok:
+	case record.Provider.Proxied():
+		recordIPs = record.CurrentIPs()
+	default:
+		const tries = 5
+		recordIPv4s, recordIPv6s, err := s.lookupIPsResilient(ctx, hostname, tries)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				s.logger.Warn("DNS resolution of " + hostname + ": " + ctxErr.Error())
+				return false
+			}
+			s.logger.Warn(fmt.Sprintf("cannot DNS resolve %s after %d tries: %s",
+				hostname, tries, err)) // update anyway
+		}
+		recordIPs = append(recordIPv4s, recordIPv6s...) //nolint:gocritic
+	}
+
+	for _, publicIP := range publicIPs {
+		if !ipsContainsIP(recordIPs, publicIP) {
+			s.logInfoLookupUpdate(hostname, ipToIPKind(publicIP), recordIPs, publicIP)
+			return true
+		}
+	}
+	s.logger.Debug(fmt.Sprintf("IP addresses of %s are %s and your IP addresses"+
+		" are %s, skipping update", hostname, ipsToString(recordIPs), ipsToString(publicIPs)))
+	return false
+}
+
+func ipToIPKind(ip netip.Addr) (kind string) {
+	if ip.Is6() {
+		return ipVersionToIPKind(ipversion.IP6)
+	}
+	return ipVersionToIPKind(ipversion.IP4)
+}
@@ -0,0 +1,190 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/failover"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+var ErrBackupIPsNotFound = errors.New("no failover backup IP address found")
+
+// failoverCheckPeriod is the period at which records are checked
+// for a health check due, given their own failover interval.
+const failoverCheckPeriod = time.Second
+
+// failoverState is the health check state of a record primary target.
+type failoverState struct {
+	nextCheck time.Time
+	// failures and successes are the numbers of
+	// consecutive failed and successful health checks.
+	failures  uint
+	successes uint
+	lastErr   error
+}
+
+func (s *failoverState) update(err error) {
+	s.lastErr = err
+	if err != nil {
+		s.failures++
+		s.successes = 0
+		return
+	}
+	s.successes++
+	s.failures = 0
+}
+
+// checkFailovers runs the health checks due of records with failover
+// settings, and points records at their backup IP addresses, or back
+// at the current public IP addresses, depending on the health check results.
+func (s *Service) checkFailovers(ctx context.Context) (errors []error) {
+	now := s.timeNow()
+	records := s.db.SelectAll()
+
+	type check struct {
+		id       uint
+		settings failover.Settings
+		state    *failoverState
+	}
+	var checks []check
+	for i, record := range records {
+		settings := record.Settings.Failover
+		if settings == nil || record.Pin != nil {
+			continue
+		}
+		key := recordKey(record)
+		state, ok := s.failoverStates[key]
+		if !ok {
+			state = &failoverState{}
+			s.failoverStates[key] = state
+		}
+		if now.Before(state.nextCheck) {
+			continue
+		}
+		state.nextCheck = now.Add(settings.Interval)
+		checks = append(checks, check{id: uint(i), settings: *settings, state: state})
+	}
+
+	if len(checks) == 0 {
+		return nil
+	}
+
+	// Health checks run in parallel so the duration is bounded
+	// by the maximum health check timeout of all records.
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Go(func() {
+			err := s.healthChecker.Check(ctx, check.settings)
+			check.state.update(err)
+		})
+	}
+	wg.Wait()
+
+	recoveredIDs := make(map[uint]struct{})
+	for _, check := range checks {
+		record := records[check.id]
+		threshold := check.settings.Threshold
+		switch {
+		case ((!record.FailedOver && check.state.failures >= threshold) ||
+			(record.FailedOver && check.state.successes >= threshold)) &&
+			s.inMaintenance(record, now):
+			s.logger.Debug(fmt.Sprintf("maintenance window in effect, deferring switch of record %s",
+				recordToLogString(record)))
+		case !record.FailedOver && check.state.failures >= threshold:
+			backupIPs := getBackupIPs(records, record)
+			if len(backupIPs) == 0 {
+				err := fmt.Errorf("%w: for record %s", ErrBackupIPsNotFound, recordToLogString(record))
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+				continue
+			}
+			s.logger.Warn(fmt.Sprintf("primary target of record %s is unhealthy (%s), failing over to %s",
+				recordToLogString(record), check.state.lastErr, ipsToString(backupIPs)))
+			err := s.updater.Failover(ctx, check.id, backupIPs, check.state.lastErr)
+			if err != nil && !s.isRateLimited(err) {
+				err = fmt.Errorf("failing over record %s: %w", recordToLogString(record), err)
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+			}
+		case record.FailedOver && check.state.successes >= threshold:
+			s.logger.Info(fmt.Sprintf("primary target of record %s is healthy again, switching back",
+				recordToLogString(record)))
+			err := s.updater.Recover(check.id)
+			if err != nil {
+				err = fmt.Errorf("recovering record %s: %w", recordToLogString(record), err)
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+				continue
+			}
+			recoveredIDs[check.id] = struct{}{}
+		case check.state.lastErr != nil:
+			s.logger.Debug(fmt.Sprintf("health check %d of primary target of record %s failed: %s",
+				check.state.failures, recordToLogString(record), check.state.lastErr))
+		}
+	}
+
+	if len(recoveredIDs) > 0 {
+		// Recovered records are updated to the current public IP addresses,
+		// as any other record, since these may have changed while failed over.
+		updateErrors := s.updateNecessary(ctx, recoveredIDs)
+		errors = append(errors, updateErrors...)
+	}
+	return errors
+}
+
+// getBackupIPs returns the failover backup IP addresses of the record,
+// which are either its backup IP address or the current IP addresses of
+// its backup record, filtered to match the IP version of the record.
+func getBackupIPs(records []librecords.Record, record librecords.Record) (
+	backupIPs []netip.Addr,
+) {
+	settings := record.Settings.Failover
+	candidates := []netip.Addr{settings.BackupIP}
+	if settings.BackupRecord != "" {
+		candidates = nil
+		for _, other := range records {
+			if other.Provider.BuildDomainName() == settings.BackupRecord {
+				candidates = append(candidates, other.CurrentIPs()...)
+			}
+		}
+	}
+
+	ipVersion := record.Provider.IPVersion()
+	for _, ip := range candidates {
+		switch {
+		case !ip.IsValid(),
+			ipVersion == ipversion.IP4 && !ip.Is4(),
+			ipVersion == ipversion.IP6 && !ip.Is6():
+			continue
+		case record.Settings.DualStack:
+			ipv4, ipv6 := splitIPFamilies(backupIPs)
+			if (ip.Is4() && ipv4.IsValid()) || (ip.Is6() && ipv6.IsValid()) {
+				continue
+			}
+		case len(backupIPs) > 0: // single IP address for non dual-stack records
+			continue
+		}
+		backupIPs = append(backupIPs, ip)
+	}
+	return backupIPs
+}
+
+// splitIPFamilies returns the first IPv4 and first IPv6 addresses of the
+// IP addresses given, where either can be invalid if not found.
+func splitIPFamilies(ips []netip.Addr) (ipv4, ipv6 netip.Addr) {
+	for _, ip := range ips {
+		switch {
+		case ip.Is4() && !ipv4.IsValid():
+			ipv4 = ip
+		case ip.Is6() && !ipv6.IsValid():
+			ipv6 = ip
+		}
+	}
+	return ipv4, ipv6
+}
@@ -0,0 +1,92 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/failover"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getBackupIPs(t *testing.T) {
+	t.Parallel()
+
+	backupIPSettings := records.Settings{
+		Failover: &failover.Settings{BackupIP: netip.MustParseAddr("5.6.7.8")},
+	}
+	backupRecordSettings := records.Settings{
+		Failover: &failover.Settings{BackupRecord: "backup.example.com"},
+	}
+	dualStackBackupRecordSettings := backupRecordSettings
+	dualStackBackupRecordSettings.DualStack = true
+	backupRecord := makeTestRecord(t, "backup", ipversion.IP4or6,
+		records.Settings{DualStack: true}, "5.6.7.8", "::2")
+
+	testCases := map[string]struct {
+		record    records.Record
+		backupIPs []netip.Addr
+	}{
+		"backup_ip": {
+			record:    makeTestRecord(t, "sub", ipversion.IP4, backupIPSettings, "1.2.3.4"),
+			backupIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+		},
+		"backup_ip_wrong_version": {
+			record: makeTestRecord(t, "sub", ipversion.IP6, backupIPSettings, "::1"),
+		},
+		"backup_record_ipv6": {
+			record:    makeTestRecord(t, "sub", ipversion.IP6, backupRecordSettings, "::1"),
+			backupIPs: []netip.Addr{netip.MustParseAddr("::2")},
+		},
+		"backup_record_ipv4_or_ipv6": {
+			record:    makeTestRecord(t, "sub", ipversion.IP4or6, backupRecordSettings, "1.2.3.4"),
+			backupIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+		},
+		"backup_record_dual_stack": {
+			record: makeTestRecord(t, "sub", ipversion.IP4or6, dualStackBackupRecordSettings,
+				"1.2.3.4", "::1"),
+			backupIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8"), netip.MustParseAddr("::2")},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			records := []records.Record{testCase.record, backupRecord}
+
+			backupIPs := getBackupIPs(records, testCase.record)
+
+			assert.Equal(t, testCase.backupIPs, backupIPs)
+		})
+	}
+}
+
+func Test_Updater_Recover(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	settings := records.Settings{Failover: &failover.Settings{
+		BackupIP: netip.MustParseAddr("9.9.9.9"),
+	}}
+	record := makeTestRecord(t, "@", ipversion.IP4, settings, "1.2.3.4", "9.9.9.9")
+	record.Status = constants.FAILEDOVER
+	record.FailedOver = true
+	db := &fakeDatabase{records: []records.Record{record}}
+	updater := makeTestUpdater(db, now)
+
+	err := updater.Recover(0)
+
+	require.NoError(t, err)
+	recovered := db.records[0]
+	assert.False(t, recovered.FailedOver)
+	// The record is updated to the current public IP address
+	// by the next update, and not to its IP address before failing over.
+	assert.Equal(t, constants.UNSET, recovered.Status)
+	assert.Equal(t, record.History, recovered.History)
+	assert.Equal(t, now, recovered.Time)
+}
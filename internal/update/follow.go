@@ -0,0 +1,120 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// updateFollowRecords updates the records amongst the record identifiers
+// given which follow another domain name, using the IP addresses of this
+// domain name. Records following the same domain name are updated together.
+func (s *Service) updateFollowRecords(ctx context.Context, records []librecords.Record,
+	recordIDs map[uint]struct{},
+) (errors []error) {
+	followedToIDs := make(map[string]map[uint]struct{})
+	for id := range recordIDs {
+		followed := records[id].Settings.Follow
+		if followed == "" {
+			continue
+		}
+		ids, ok := followedToIDs[followed]
+		if !ok {
+			ids = make(map[uint]struct{})
+			followedToIDs[followed] = ids
+		}
+		ids[id] = struct{}{}
+	}
+
+	for _, followed := range slices.Sorted(maps.Keys(followedToIDs)) {
+		ip, ipv4, ipv6, err := s.getFollowedIPs(ctx, records, followed)
+		if err != nil {
+			err = fmt.Errorf("getting IP addresses of followed %s: %w", followed, err)
+			errors = append(errors, err)
+			s.logger.Error(err.Error())
+			continue
+		}
+		s.logger.Debug(fmt.Sprintf("followed %s has IP addresses: v4 or v6: %s, v4: %s, v6: %s",
+			followed, ip, ipv4, ipv6))
+		followErrors := s.updateRecords(ctx, records, followedToIDs[followed], ip, ipv4, ipv6)
+		errors = append(errors, followErrors...)
+	}
+	return errors
+}
+
+// withFollowerIDs returns the record identifiers given together with the
+// identifiers of the records following the domain name of these records,
+// so that follow records are updated as soon as the records they follow.
+func withFollowerIDs(records []librecords.Record, recordIDs map[uint]struct{}) (
+	withFollowers map[uint]struct{},
+) {
+	domainNames := make(map[string]struct{}, len(recordIDs))
+	for id := range recordIDs {
+		domainNames[records[id].Provider.BuildDomainName()] = struct{}{}
+	}
+	withFollowers = maps.Clone(recordIDs)
+	for i, record := range records {
+		_, followed := domainNames[record.Settings.Follow]
+		if followed {
+			withFollowers[uint(i)] = struct{}{}
+		}
+	}
+	return withFollowers
+}
+
+// getFollowedIPs returns the IP addresses of the domain name given, which
+// are the IP addresses last set on the records configured for this domain
+// name, or its DNS resolved IP addresses if no such record has an IP address.
+// The ip returned is the IPv4 address if it is valid, and the IPv6 address
+// otherwise.
+func (s *Service) getFollowedIPs(ctx context.Context, records []librecords.Record,
+	followed string,
+) (ip, ipv4, ipv6 netip.Addr, err error) {
+	ipv4, ipv6 = getRecordsCurrentIPs(records, followed)
+	if !ipv4.IsValid() && !ipv6.IsValid() {
+		const tries = 5
+		ipv4s, ipv6s, err := s.lookupIPsResilient(ctx, followed, tries)
+		if err != nil {
+			return netip.Addr{}, netip.Addr{}, netip.Addr{}, fmt.Errorf("resolving: %w", err)
+		}
+		if len(ipv4s) > 0 {
+			ipv4 = ipv4s[0]
+		}
+		if len(ipv6s) > 0 {
+			ipv6 = ipv6s[0]
+		}
+	}
+
+	ip = ipv4
+	if !ip.IsValid() {
+		ip = ipv6
+	}
+	return ip, ipv4, ipv6, nil
+}
+
+// getRecordsCurrentIPs returns the IPv4 and IPv6 addresses last set on
+// the address records with the domain name given, which are invalid if
+// not found. Content records are ignored.
+func getRecordsCurrentIPs(records []librecords.Record, domainName string) (
+	ipv4, ipv6 netip.Addr,
+) {
+	for _, record := range records {
+		if record.Settings.RecordType != "" ||
+			record.Provider.BuildDomainName() != domainName {
+			continue
+		}
+		for _, currentIP := range record.CurrentIPs() {
+			switch {
+			case currentIP.Is4() && !ipv4.IsValid():
+				ipv4 = currentIP
+			case currentIP.Is6() && !ipv6.IsValid():
+				ipv6 = currentIP
+			}
+		}
+	}
+	return ipv4, ipv6
+}
@@ -0,0 +1,81 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_withFollowerIDs(t *testing.T) {
+	t.Parallel()
+
+	recs := []records.Record{
+		makeTestRecord(t, "home", ipversion.IP4, records.Settings{}),
+		makeTestRecord(t, "alias", ipversion.IP4,
+			records.Settings{Follow: "home.example.com"}),
+		makeTestRecord(t, "other", ipversion.IP4, records.Settings{}),
+		makeTestRecord(t, "alias2", ipversion.IP4,
+			records.Settings{Follow: "other.example.com"}),
+	}
+	recordIDs := map[uint]struct{}{0: {}}
+
+	withFollowers := withFollowerIDs(recs, recordIDs)
+
+	assert.Equal(t, map[uint]struct{}{0: {}, 1: {}}, withFollowers)
+	assert.Equal(t, map[uint]struct{}{0: {}}, recordIDs)
+}
+
+func Test_getRecordsCurrentIPs(t *testing.T) {
+	t.Parallel()
+
+	const ipv4, ipv6 = "1.2.3.4", "2001:db8::1"
+
+	testCases := map[string]struct {
+		records []records.Record
+		ipv4    netip.Addr
+		ipv6    netip.Addr
+	}{
+		"no_record": {},
+		"other_domain_name": {
+			records: []records.Record{
+				makeTestRecord(t, "other", ipversion.IP4, records.Settings{}, ipv4),
+			},
+		},
+		"ipv4_and_ipv6_records": {
+			records: []records.Record{
+				makeTestRecord(t, "home", ipversion.IP4, records.Settings{}, "5.6.7.8", ipv4),
+				makeTestRecord(t, "home", ipversion.IP6, records.Settings{}, ipv6),
+			},
+			ipv4: netip.MustParseAddr(ipv4),
+			ipv6: netip.MustParseAddr(ipv6),
+		},
+		"dual_stack_record": {
+			records: []records.Record{
+				makeTestRecord(t, "home", ipversion.IP4or6,
+					records.Settings{DualStack: true}, ipv4, ipv6),
+			},
+			ipv4: netip.MustParseAddr(ipv4),
+			ipv6: netip.MustParseAddr(ipv6),
+		},
+		"content_record": {
+			records: []records.Record{
+				makeTestRecord(t, "home", ipversion.IP4,
+					records.Settings{RecordType: "TXT"}, ipv4),
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ipv4, ipv6 := getRecordsCurrentIPs(testCase.records, "home.example.com")
+
+			assert.Equal(t, testCase.ipv4, ipv4)
+			assert.Equal(t, testCase.ipv6, ipv6)
+		})
+	}
+}
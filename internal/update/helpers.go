@@ -16,10 +16,31 @@ func ipVersionToIPKind(version ipversion.IPVersion) (kind string) {
 	return version.String()
 }
 
+// recordKey returns a key identifying the record in the state of the
+// service. Unlike the record log string, it includes the provider name,
+// since records of different providers can have the same domain name.
+func recordKey(record records.Record) string {
+	return record.Provider.String()
+}
+
 func recordToLogString(record records.Record) string {
 	return fmt.Sprintf("%s (%s)",
 		record.Provider.BuildDomainName(),
-		record.Provider.IPVersion())
+		record.IPVersionString())
+}
+
+// recordsToLogString returns "record " followed by the record log
+// string for a single record, and "records " followed by the comma
+// separated record log strings otherwise.
+func recordsToLogString(batch []records.Record) string {
+	if len(batch) == 1 {
+		return "record " + recordToLogString(batch[0])
+	}
+	logStrings := make([]string, len(batch))
+	for i, record := range batch {
+		logStrings[i] = recordToLogString(record)
+	}
+	return "records " + strings.Join(logStrings, ", ")
 }
 
 func (s *Service) logDebugNoLookupSkip(hostname, ipKind string, lastIP, ip netip.Addr) {
@@ -0,0 +1,45 @@
+package update
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/require"
+)
+
+// makeTestProvider returns a provider of the given name for the
+// owner given on the domain example.com.
+func makeTestProvider(t *testing.T, providerName models.Provider, owner string,
+	ipVersion ipversion.IPVersion, ipv6Suffix netip.Prefix,
+) provider.Provider {
+	t.Helper()
+	data := json.RawMessage(`{"username":"user","password":"pass"}`)
+	recordProvider, err := provider.New(providerName, data, "example.com",
+		owner, ipVersion, ipv6Suffix, nil, nil)
+	require.NoError(t, err)
+	return recordProvider
+}
+
+// makeTestRecord returns a record of the example provider for the owner
+// given, with a history of the IP addresses given, from oldest to newest.
+func makeTestRecord(t *testing.T, owner string, ipVersion ipversion.IPVersion,
+	settings records.Settings, ips ...string,
+) records.Record {
+	t.Helper()
+	recordProvider := makeTestProvider(t, constants.Example, owner, ipVersion, netip.Prefix{})
+	events := make([]models.HistoryEvent, len(ips))
+	for i, ip := range ips {
+		events[i] = models.HistoryEvent{
+			IP:   netip.MustParseAddr(ip),
+			Time: time.Unix(int64(i), 0),
+		}
+	}
+	return records.New(recordProvider, settings, events)
+}
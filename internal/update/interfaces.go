@@ -2,9 +2,15 @@ package update
 
 import (
 	"context"
+	"net/http"
 	"net/netip"
+	"time"
 
+	"github.com/qdm12/ddns-updater/internal/failover"
 	"github.com/qdm12/ddns-updater/internal/healthchecksio"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
 	"github.com/qdm12/ddns-updater/internal/records"
 )
 
@@ -16,12 +22,35 @@ type PublicIPFetcher interface {
 
 type UpdaterInterface interface {
 	Update(ctx context.Context, recordID uint, ip netip.Addr) (err error)
+	UpdateDualStack(ctx context.Context, recordID uint, ipv4, ipv6 netip.Addr) (err error)
+	UpdateAuto(ctx context.Context, recordID uint) (err error)
+	UpdateBatch(ctx context.Context, recordIDs []uint, ip netip.Addr) (err error)
+	UpdateMulti(ctx context.Context, recordID uint, ips []netip.Addr) (err error)
+	UpdateContent(ctx context.Context, recordID uint, ips []netip.Addr) (err error)
+	Failover(ctx context.Context, recordID uint, backupIPs []netip.Addr, healthErr error) (err error)
+	Recover(recordID uint) (err error)
+	Pin(ctx context.Context, recordID uint, ip netip.Addr, until time.Time) (err error)
+	Unpin(recordID uint, reason string) (err error)
+	Repair(ctx context.Context, recordID uint, ip netip.Addr, recordIPs []netip.Addr) (err error)
+	ReadRecord(ctx context.Context, reader provider.RecordReader,
+		settings records.Settings) (ips []netip.Addr, err error)
+	ClearStale(ctx context.Context, recordID uint, recordType string) (err error)
+}
+
+type RateLimiter interface {
+	Check(account string, limit *ratelimit.Limit) (err error)
+	Client(client *http.Client, account string, limit *ratelimit.Limit) *http.Client
+}
+
+type HealthChecker interface {
+	Check(ctx context.Context, settings failover.Settings) (err error)
 }
 
 type Database interface {
 	Select(recordID uint) (record records.Record, err error)
 	SelectAll() (records []records.Record)
 	Update(recordID uint, record records.Record) (err error)
+	SetDynamic(dynamicRecords []records.Record)
 }
 
 type LookupIPer interface {
@@ -30,6 +59,9 @@ type LookupIPer interface {
 
 type ShoutrrrClient interface {
 	Notify(message string)
+	NotifyRecord(key, record string, status models.Status, message string)
+	BeginCycle()
+	EndCycle()
 }
 
 type Logger interface {
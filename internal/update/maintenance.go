@@ -0,0 +1,41 @@
+package update
+
+import (
+	"fmt"
+	"time"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// inMaintenance returns true if the record is not critical
+// and the time given is within a maintenance window.
+func (s *Service) inMaintenance(record librecords.Record, now time.Time) bool {
+	return !record.Settings.Critical && s.maintenanceWindows.Contains(now)
+}
+
+// deferMaintenanceRecords removes the identifiers of the records not
+// critical from the record identifiers given if the time given is within
+// a maintenance window, and schedules them right after the window.
+func (s *Service) deferMaintenanceRecords(records []librecords.Record,
+	recordIDs map[uint]struct{}, now time.Time,
+) {
+	if !s.maintenanceWindows.Contains(now) {
+		return
+	}
+
+	end := s.maintenanceWindows.End(now)
+	deferred := 0
+	for id := range recordIDs {
+		record := records[id]
+		if record.Settings.Critical {
+			continue
+		}
+		delete(recordIDs, id)
+		s.nextUpdates[recordKey(record)] = end
+		deferred++
+	}
+	if deferred > 0 {
+		s.logger.Info(fmt.Sprintf("maintenance window in effect, deferring %d record update(s) until %s",
+			deferred, end.Format(time.DateTime)))
+	}
+}
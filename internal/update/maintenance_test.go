@@ -0,0 +1,52 @@
+package update
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/schedule"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_Service_deferMaintenanceRecords(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(owner string, critical bool) records.Record {
+		return makeTestRecord(t, owner, ipversion.IP4, records.Settings{Critical: critical})
+	}
+	recs := []records.Record{
+		makeRecord("normal", false),
+		makeRecord("critical", true),
+	}
+
+	maintenanceWindows, err := schedule.ParseCronWindows("CRON_TZ=UTC * 9-16 * * 1-5")
+	require.NoError(t, err)
+	service := &Service{
+		maintenanceWindows: maintenanceWindows,
+		nextUpdates:        make(map[string]time.Time),
+		logger:             noopLogger{},
+	}
+
+	// Wednesday 15th of January 2025
+	outside := time.Date(2025, time.January, 15, 8, 0, 0, 0, time.UTC)
+	recordIDs := allRecordIDs(recs)
+	service.deferMaintenanceRecords(recs, recordIDs, outside)
+	assert.Equal(t, allRecordIDs(recs), recordIDs)
+	assert.Empty(t, service.nextUpdates)
+
+	within := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+	service.deferMaintenanceRecords(recs, recordIDs, within)
+	assert.Equal(t, map[uint]struct{}{1: {}}, recordIDs)
+	end := time.Date(2025, time.January, 15, 17, 0, 0, 0, time.UTC)
+	assert.Equal(t, map[string]time.Time{recordKey(recs[0]): end}, service.nextUpdates)
+}
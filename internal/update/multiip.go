@@ -0,0 +1,367 @@
+package update
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+var ErrMultiIPUnsupported = errors.New("provider does not support multi-value records")
+
+// UpdateMulti sets the record to the IP addresses given, replacing
+// the record set of each IP family of the record IP version, and
+// deleting it if no IP address of this family is given.
+func (u *Updater) UpdateMulti(ctx context.Context, id uint, ips []netip.Addr) (err error) {
+	var newIPs []netip.Addr
+	record, err := u.apply(ctx, id, func(ctx context.Context, record librecords.Record) (err error) {
+		multiUpdater, ok := record.Provider.(provider.MultiUpdater)
+		if !ok {
+			return fmt.Errorf("%w", ErrMultiIPUnsupported)
+		}
+		newIPs, err = multiUpdater.UpdateMulti(ctx, u.clientFor(record.Settings, ""), ips)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	record.Status = constants.SUCCESS
+	record.Message = "changed to " + ipsToString(newIPs)
+	record.History = append(record.History, makeMultiIPEvent(newIPs, u.timeNow()))
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+// uplinkIPs are the public IPv4 and IPv6 addresses of an uplink,
+// which are invalid if not fetched or not found.
+type uplinkIPs struct {
+	ipv4 netip.Addr
+	ipv6 netip.Addr
+}
+
+// updateMultiIPRecords updates the multi-value records amongst the
+// record identifiers given if their set of IP addresses changed.
+func (s *Service) updateMultiIPRecords(ctx context.Context, records []librecords.Record,
+	recordIDs map[uint]struct{},
+) (errors []error) {
+	var multiIPRecordIDs []uint
+	for _, id := range slices.Sorted(maps.Keys(recordIDs)) {
+		if records[id].Settings.MultiIP != nil {
+			multiIPRecordIDs = append(multiIPRecordIDs, id)
+		}
+	}
+	if len(multiIPRecordIDs) == 0 {
+		return nil
+	}
+
+	uplinksIPs, errors := s.fetchUplinksIPs(ctx, records, multiIPRecordIDs)
+
+	now := s.timeNow()
+	for _, id := range multiIPRecordIDs {
+		record := records[id]
+		if record.Pin != nil && record.Status == constants.UNSET { // pin restored from the database
+			err := setInitialPinnedStatus(s.db, id, now)
+			if err != nil {
+				err = fmt.Errorf("setting initial pinned status: %w", err)
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+			}
+			continue
+		} else if s.isUpdateHeld(record) {
+			continue
+		}
+
+		ips := s.getMultiIPs(record, uplinksIPs, now)
+		// Reported IP addresses are not persisted, so the record is only
+		// set to no IP address once reported IP addresses expired, and not
+		// right after the program starts.
+		_, reportsReceived := s.reportedIPs[recordKey(record)]
+		if len(ips) == 0 && !reportsReceived {
+			s.logger.Warn(fmt.Sprintf("Skipping update for %s because no IP address is available",
+				recordToLogString(record)))
+			continue
+		}
+
+		if !s.shouldUpdateMultiIPRecord(ctx, record, ips) {
+			if record.Status != constants.UNSET {
+				continue
+			}
+			err := setInitialMultiIPUpToDateStatus(s.db, id, ips, now)
+			if err != nil {
+				err = fmt.Errorf("setting initial up to date status: %w", err)
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+			}
+			continue
+		}
+
+		s.logger.Info("Updating record " + record.Provider.String() + " to use " + ipsToString(ips))
+		err := s.updater.UpdateMulti(ctx, id, ips)
+		if err != nil && !s.isRateLimited(err) {
+			errors = append(errors, err)
+			s.logger.Error(err.Error())
+		}
+	}
+	return errors
+}
+
+// fetchUplinksIPs fetches the public IP addresses of the uplinks used by
+// the multi-value records with the identifiers given, only for the IP
+// families needed by these records.
+func (s *Service) fetchUplinksIPs(ctx context.Context, records []librecords.Record,
+	recordIDs []uint,
+) (uplinksIPs map[string]uplinkIPs, errors []error) {
+	type ipFamilies struct {
+		doIPv4 bool
+		doIPv6 bool
+	}
+	uplinkToFamilies := make(map[string]ipFamilies)
+	for _, id := range recordIDs {
+		record := records[id]
+		ipVersion := record.Provider.IPVersion()
+		for _, uplinkName := range record.Settings.MultiIP.Uplinks {
+			families := uplinkToFamilies[uplinkName]
+			families.doIPv4 = families.doIPv4 || ipVersion != ipversion.IP6
+			families.doIPv6 = families.doIPv6 || ipVersion != ipversion.IP4
+			uplinkToFamilies[uplinkName] = families
+		}
+	}
+
+	uplinksIPs = make(map[string]uplinkIPs, len(uplinkToFamilies))
+	for _, uplinkName := range slices.Sorted(maps.Keys(uplinkToFamilies)) {
+		ipGetter, err := s.getIPGetter(uplinkName)
+		if err != nil {
+			s.logger.Error(err.Error())
+			errors = append(errors, err)
+			continue
+		}
+		families := uplinkToFamilies[uplinkName]
+		_, ipv4, ipv6, uplinkErrors := s.getNewIPs(ctx, ipGetter, false,
+			families.doIPv4, families.doIPv6)
+		for _, err := range uplinkErrors {
+			s.logger.Error("uplink " + uplinkName + ": " + err.Error())
+		}
+		errors = append(errors, uplinkErrors...)
+		s.logger.Debug(fmt.Sprintf("uplink %s: your public IP address are: v4: %s, v6: %s",
+			uplinkName, ipv4, ipv6))
+		uplinksIPs[uplinkName] = uplinkIPs{ipv4: ipv4, ipv6: ipv6}
+	}
+	return uplinksIPs, errors
+}
+
+// getMultiIPs returns the sorted set of IP addresses the multi-value
+// record should be set to, which are the public IP addresses of its
+// uplinks, or the IP addresses reported by hosts which did not expire.
+// Only IP addresses matching the record IP version are returned.
+func (s *Service) getMultiIPs(record librecords.Record, uplinksIPs map[string]uplinkIPs,
+	now time.Time,
+) (ips []netip.Addr) {
+	multiIP := record.Settings.MultiIP
+	for _, uplinkName := range multiIP.Uplinks {
+		publicIPs := uplinksIPs[uplinkName]
+		ips = append(ips, computeIP(record, publicIPs.ipv4, ipversion.IP4),
+			computeIP(record, publicIPs.ipv6, ipversion.IP6))
+	}
+	if multiIP.Reported {
+		ips = append(ips, s.getReportedIPs(record, now)...)
+	}
+	return makeIPSet(ips, record.Provider.IPVersion())
+}
+
+// makeIPSet returns the sorted valid IP addresses given without duplicates,
+// keeping only the ones matching the IP version given.
+func makeIPSet(ips []netip.Addr, ipVersion ipversion.IPVersion) (ipSet []netip.Addr) {
+	for _, ip := range ips {
+		switch {
+		case !ip.IsValid(),
+			ipVersion == ipversion.IP4 && !ip.Is4(),
+			ipVersion == ipversion.IP6 && !ip.Is6():
+			continue
+		}
+		ipSet = append(ipSet, ip)
+	}
+	slices.SortFunc(ipSet, netip.Addr.Compare)
+	return slices.Compact(ipSet)
+}
+
+// shouldUpdateMultiIPRecord returns true if the IP addresses of the
+// record differ from the IP addresses given, reading them from the
+// provider API if possible, or from the record history otherwise.
+func (s *Service) shouldUpdateMultiIPRecord(ctx context.Context, record librecords.Record,
+	ips []netip.Addr,
+) (update bool) {
+	hostname := record.Provider.BuildDomainName()
+	recordIPs := record.CurrentIPs()
+	reader, ok := record.Provider.(provider.RecordReader)
+	if ok {
+		readIPs, err := s.updater.ReadRecord(ctx, reader, record.Settings)
+		if err == nil {
+			recordIPs = readIPs
+		} else {
+			s.logger.Warn("reading record " + hostname + " from provider API: " + err.Error())
+		}
+	}
+	recordIPs = makeIPSet(recordIPs, record.Provider.IPVersion())
+
+	if slices.Equal(recordIPs, ips) {
+		s.logger.Debug(fmt.Sprintf("%s has IP addresses %s, skipping update",
+			hostname, ipsToString(ips)))
+		return false
+	}
+	s.logger.Info(fmt.Sprintf("%s has IP addresses %s, trying to update to %s",
+		hostname, ipsToString(recordIPs), ipsToString(ips)))
+	return true
+}
+
+func setInitialMultiIPUpToDateStatus(db Database, id uint, ips []netip.Addr, now time.Time) error {
+	record, err := db.Select(id)
+	if err != nil {
+		return err
+	}
+	record.Status = constants.UPTODATE
+	record.Time = now
+	if !slices.Equal(record.History.GetCurrentIPs(), ips) {
+		record.History = append(record.History, makeMultiIPEvent(ips, now))
+	}
+	return db.Update(id, record)
+}
+
+// makeMultiIPEvent returns the history event of a multi-value record
+// set to the IP addresses given, which has no IP address if ips is empty.
+func makeMultiIPEvent(ips []netip.Addr, now time.Time) (event models.HistoryEvent) {
+	event.Time = now
+	if len(ips) > 0 {
+		event.IP = ips[0]
+		event.IPs = ips
+	}
+	return event
+}
+
+type reportRequest struct {
+	domain string
+	token  string
+	ip     netip.Addr
+	result chan error
+}
+
+// Report reports the IP address given for the multi-value records with
+// the domain name and report token given, to which it is added if it
+// is new. The IP address is removed from a record once it has not been
+// reported again for the report expiry duration of the record.
+func (s *Service) Report(ctx context.Context, domain, token string, ip netip.Addr) (err error) {
+	request := reportRequest{domain: domain, token: token, ip: ip, result: make(chan error, 1)}
+	select {
+	case s.reports <- request:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err = <-request.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var (
+	ErrReportNotAuthorized      = errors.New("no reported record matches the domain name and report token")
+	ErrReportIPVersionMismatch  = errors.New("reported IP address does not match the record IP version")
+	ErrReportIPAddressNotPublic = errors.New("reported IP address is not public")
+)
+
+func (s *Service) report(ctx context.Context, request reportRequest) (err error) {
+	ip := request.ip.Unmap()
+	records := s.db.SelectAll()
+	recordIDs, err := matchReportedRecords(records, request.domain, request.token, ip)
+	if err != nil {
+		return err
+	} else if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return fmt.Errorf("%w: %s", ErrReportIPAddressNotPublic, ip)
+	}
+
+	newRecordIDs := make(map[uint]struct{}, len(recordIDs))
+	for _, id := range recordIDs {
+		key := recordKey(records[id])
+		reported, ok := s.reportedIPs[key]
+		if !ok {
+			reported = make(map[netip.Addr]time.Time)
+			s.reportedIPs[key] = reported
+		}
+		_, known := reported[ip]
+		reported[ip] = s.timeNow()
+		if known {
+			continue
+		}
+		s.logger.Info(fmt.Sprintf("IP address %s reported for record %s",
+			ip, recordToLogString(records[id])))
+		newRecordIDs[id] = struct{}{}
+	}
+
+	if len(newRecordIDs) > 0 {
+		// Update errors are logged and not returned to the reporting host.
+		s.updateNecessary(ctx, newRecordIDs)
+	}
+	return nil
+}
+
+// matchReportedRecords returns the identifiers of the records of the
+// "reports" IP source with the domain name and report token given,
+// which accept the IP version of the IP address given. Records are
+// matched by domain name since their identifiers change when dynamic
+// records are added or removed.
+func matchReportedRecords(records []librecords.Record, domain, token string,
+	ip netip.Addr,
+) (recordIDs []uint, err error) {
+	authorized := false
+	for i, record := range records {
+		multiIP := record.Settings.MultiIP
+		if multiIP == nil || !multiIP.Reported ||
+			!strings.EqualFold(record.Provider.BuildDomainName(), domain) ||
+			subtle.ConstantTimeCompare([]byte(multiIP.ReportToken), []byte(token)) != 1 {
+			continue
+		}
+		authorized = true
+
+		ipVersion := record.Provider.IPVersion()
+		if (ipVersion == ipversion.IP4 && !ip.Is4()) || (ipVersion == ipversion.IP6 && !ip.Is6()) {
+			continue
+		}
+		recordIDs = append(recordIDs, uint(i))
+	}
+
+	switch {
+	case !authorized:
+		return nil, fmt.Errorf("%w: %s", ErrReportNotAuthorized, domain)
+	case len(recordIDs) == 0:
+		return nil, fmt.Errorf("%w: %s for %s", ErrReportIPVersionMismatch, ip, domain)
+	}
+	return recordIDs, nil
+}
+
+// getReportedIPs returns the IP addresses reported for the record
+// which did not expire, and removes the expired ones.
+func (s *Service) getReportedIPs(record librecords.Record, now time.Time) (ips []netip.Addr) {
+	reported := s.reportedIPs[recordKey(record)]
+	expiry := record.Settings.MultiIP.ReportExpiry
+	for ip, reportTime := range reported {
+		if now.Sub(reportTime) >= expiry {
+			s.logger.Info(fmt.Sprintf("reported IP address %s expired for record %s",
+				ip, recordToLogString(record)))
+			delete(reported, ip)
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips
+}
@@ -0,0 +1,244 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_makeIPSet(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ips       []netip.Addr
+		ipVersion ipversion.IPVersion
+		ipSet     []netip.Addr
+	}{
+		"empty": {
+			ipVersion: ipversion.IP4or6,
+		},
+		"invalid_ips": {
+			ips:       []netip.Addr{{}, {}},
+			ipVersion: ipversion.IP4or6,
+		},
+		"sorted_without_duplicates": {
+			ips: []netip.Addr{
+				netip.MustParseAddr("2001:db8::1"),
+				netip.MustParseAddr("5.6.7.8"),
+				{},
+				netip.MustParseAddr("1.2.3.4"),
+				netip.MustParseAddr("5.6.7.8"),
+			},
+			ipVersion: ipversion.IP4or6,
+			ipSet: []netip.Addr{
+				netip.MustParseAddr("1.2.3.4"),
+				netip.MustParseAddr("5.6.7.8"),
+				netip.MustParseAddr("2001:db8::1"),
+			},
+		},
+		"ipv4_only": {
+			ips: []netip.Addr{
+				netip.MustParseAddr("2001:db8::1"),
+				netip.MustParseAddr("1.2.3.4"),
+			},
+			ipVersion: ipversion.IP4,
+			ipSet:     []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+		},
+		"ipv6_only": {
+			ips: []netip.Addr{
+				netip.MustParseAddr("2001:db8::1"),
+				netip.MustParseAddr("1.2.3.4"),
+			},
+			ipVersion: ipversion.IP6,
+			ipSet:     []netip.Addr{netip.MustParseAddr("2001:db8::1")},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ipSet := makeIPSet(testCase.ips, testCase.ipVersion)
+
+			assert.Equal(t, testCase.ipSet, ipSet)
+		})
+	}
+}
+
+func Test_matchReportedRecords(t *testing.T) {
+	t.Parallel()
+
+	reported := records.Settings{MultiIP: &records.MultiIP{
+		Reported:    true,
+		ReportToken: "token",
+	}}
+	recordsList := []records.Record{
+		makeTestRecord(t, "public", ipversion.IP4, records.Settings{}),
+		makeTestRecord(t, "host", ipversion.IP4, reported),
+		makeTestRecord(t, "host", ipversion.IP6, reported),
+		makeTestRecord(t, "any", ipversion.IP4or6, reported),
+		makeTestRecord(t, "ipv6", ipversion.IP6, reported),
+	}
+
+	testCases := map[string]struct {
+		domain     string
+		token      string
+		ip         netip.Addr
+		recordIDs  []uint
+		errWrapped error
+		errMessage string
+	}{
+		"ipv4": {
+			domain:    "host.example.com",
+			token:     "token",
+			ip:        netip.MustParseAddr("1.2.3.4"),
+			recordIDs: []uint{1},
+		},
+		"ipv6_case_insensitive": {
+			domain:    "HOST.example.com",
+			token:     "token",
+			ip:        netip.MustParseAddr("2001:db8::1"),
+			recordIDs: []uint{2},
+		},
+		"any_ip_version": {
+			domain:    "any.example.com",
+			token:     "token",
+			ip:        netip.MustParseAddr("2001:db8::1"),
+			recordIDs: []uint{3},
+		},
+		"token_wrong": {
+			domain:     "host.example.com",
+			token:      "wrong",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			errWrapped: ErrReportNotAuthorized,
+			errMessage: "no reported record matches the domain name and report token: host.example.com",
+		},
+		"record_not_reported": {
+			domain:     "public.example.com",
+			token:      "",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			errWrapped: ErrReportNotAuthorized,
+			errMessage: "no reported record matches the domain name and report token: public.example.com",
+		},
+		"ip_version_mismatch": {
+			domain:     "ipv6.example.com",
+			token:      "token",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			errWrapped: ErrReportIPVersionMismatch,
+			errMessage: "reported IP address does not match the record IP version: " +
+				"1.2.3.4 for ipv6.example.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recordIDs, err := matchReportedRecords(recordsList,
+				testCase.domain, testCase.token, testCase.ip)
+
+			assert.Equal(t, testCase.recordIDs, recordIDs)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+// fakeMultiUpdaterProvider is a provider which records the
+// sets of IP addresses it is updated with.
+type fakeMultiUpdaterProvider struct {
+	provider.Provider
+	updatedIPs [][]netip.Addr
+}
+
+func (p *fakeMultiUpdaterProvider) UpdateMulti(_ context.Context, _ *http.Client,
+	ips []netip.Addr,
+) (newIPs []netip.Addr, err error) {
+	p.updatedIPs = append(p.updatedIPs, ips)
+	return ips, nil
+}
+
+func Test_Service_updateMultiIPRecords_reported(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(100000, 0)
+	ipv4 := netip.MustParseAddr("1.2.3.4")
+	ipv6 := netip.MustParseAddr("2001:db8::1")
+
+	testCases := map[string]struct {
+		reportedIPs map[netip.Addr]time.Time
+		updatedIPs  [][]netip.Addr
+		currentIPs  []netip.Addr
+	}{
+		"no_report_since_start": {
+			currentIPs: []netip.Addr{ipv4, ipv6},
+		},
+		"last_ipv6_expired": {
+			reportedIPs: map[netip.Addr]time.Time{
+				ipv4: now.Add(-time.Minute),
+				ipv6: now.Add(-2 * time.Hour),
+			},
+			updatedIPs: [][]netip.Addr{{ipv4}},
+			currentIPs: []netip.Addr{ipv4},
+		},
+		"all_expired": {
+			reportedIPs: map[netip.Addr]time.Time{
+				ipv4: now.Add(-2 * time.Hour),
+				ipv6: now.Add(-2 * time.Hour),
+			},
+			updatedIPs: [][]netip.Addr{nil},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := records.Settings{MultiIP: &records.MultiIP{
+				Reported:     true,
+				ReportExpiry: time.Hour,
+			}}
+			record := makeTestRecord(t, "host", ipversion.IP4or6, settings)
+			multiUpdater := &fakeMultiUpdaterProvider{Provider: record.Provider}
+			record.Provider = multiUpdater
+			record.Status = constants.SUCCESS
+			record.History = models.History{{
+				IP:   ipv4,
+				IPs:  []netip.Addr{ipv4, ipv6},
+				Time: now.Add(-3 * time.Hour),
+			}}
+			db := &fakeDatabase{records: []records.Record{record}}
+			service := &Service{
+				db:             db,
+				updater:        makeTestUpdater(db, now),
+				logger:         noopLogger{},
+				shoutrrrClient: noopShoutrrrClient{},
+				timeNow:        func() time.Time { return now },
+				reportedIPs:    make(map[string]map[netip.Addr]time.Time),
+			}
+			if testCase.reportedIPs != nil {
+				service.reportedIPs[recordKey(record)] = testCase.reportedIPs
+			}
+
+			errs := service.updateMultiIPRecords(context.Background(),
+				db.SelectAll(), map[uint]struct{}{0: {}})
+
+			assert.Empty(t, errs)
+			assert.Equal(t, testCase.updatedIPs, multiUpdater.updatedIPs)
+			require.Len(t, db.records, 1)
+			assert.Equal(t, testCase.currentIPs, db.records[0].CurrentIPs())
+		})
+	}
+}
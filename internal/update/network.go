@@ -0,0 +1,68 @@
+package update
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// clientKey identifies an HTTP client by its uplink name, which is empty
+// for the default network path, and by the network it is pinned to,
+// which is tcp4, tcp6 or empty for both.
+type clientKey struct {
+	uplinkName string
+	network    string
+}
+
+// pinNetwork returns a copy of the client given, only dialing connections
+// over the network given, which is tcp4 or tcp6. The client given is
+// returned as is if the network is empty or if its transport cannot be
+// modified.
+func pinNetwork(client *http.Client, network string) *http.Client {
+	if network == "" {
+		return client
+	}
+
+	var transport *http.Transport
+	switch clientTransport := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	case *http.Transport:
+		transport = clientTransport.Clone()
+	default:
+		return client
+	}
+
+	dialContext := transport.DialContext
+	if dialContext == nil {
+		dialContext = (&net.Dialer{}).DialContext
+	}
+	transport.DialContext = func(ctx context.Context, _, address string) (net.Conn, error) {
+		return dialContext(ctx, network, address)
+	}
+
+	pinnedClient := *client
+	pinnedClient.Transport = transport
+	return &pinnedClient
+}
+
+func ipVersionToNetwork(version ipversion.IPVersion) (network string) {
+	switch version {
+	case ipversion.IP4:
+		return "tcp4"
+	case ipversion.IP6:
+		return "tcp6"
+	default:
+		return ""
+	}
+}
+
+func ipToNetwork(ip netip.Addr) (network string) {
+	if ip.Is4() {
+		return "tcp4"
+	}
+	return "tcp6"
+}
@@ -0,0 +1,65 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pinNetwork(t *testing.T) {
+	t.Parallel()
+
+	// httptest servers listen on the IPv4 address 127.0.0.1
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	testCases := map[string]struct {
+		network string
+		success bool
+	}{
+		"unpinned": {
+			success: true,
+		},
+		"tcp4": {
+			network: "tcp4",
+			success: true,
+		},
+		"tcp6": {
+			network: "tcp6",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := pinNetwork(&http.Client{}, testCase.network)
+			t.Cleanup(client.CloseIdleConnections)
+
+			request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+
+			response, err := client.Do(request)
+			if !testCase.success {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			_ = response.Body.Close()
+		})
+	}
+}
+
+func Test_pinNetwork_customTransport(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{Transport: &loggingRoundTripper{}}
+
+	pinnedClient := pinNetwork(client, "tcp4")
+
+	assert.Same(t, client, pinnedClient)
+}
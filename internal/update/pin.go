@@ -0,0 +1,139 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+type pinRequest struct {
+	// record is the key of the record, as returned by
+	// the String method of its provider.
+	record string
+	// ip is invalid to unpin the record.
+	ip       netip.Addr
+	duration time.Duration
+	result   chan error
+}
+
+// Pin sets the record with the given key to the IP address given,
+// and skips updating it for the given duration. The record key is
+// the string representation of the record provider.
+func (s *Service) Pin(ctx context.Context, record string, ip netip.Addr,
+	duration time.Duration,
+) (err error) {
+	return s.sendPinRequest(ctx, pinRequest{record: record, ip: ip, duration: duration})
+}
+
+// Unpin removes the pin of the record with the given key,
+// so that it gets updated as usual again.
+func (s *Service) Unpin(ctx context.Context, record string) (err error) {
+	return s.sendPinRequest(ctx, pinRequest{record: record})
+}
+
+// sendPinRequest sends the pin request to the run goroutine, so it
+// does not run concurrently with updates of the same record.
+func (s *Service) sendPinRequest(ctx context.Context, request pinRequest) (err error) {
+	request.result = make(chan error, 1)
+	select {
+	case s.pins <- request:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err = <-request.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var (
+	ErrPinDurationNotPositive = errors.New("pin duration is not positive")
+	ErrPinRecordNotFound      = errors.New("record to pin not found")
+)
+
+func (s *Service) pin(ctx context.Context, request pinRequest) (err error) {
+	id, ok := findRecordID(s.db.SelectAll(), request.record)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPinRecordNotFound, request.record)
+	}
+
+	if !request.ip.IsValid() {
+		return s.updater.Unpin(id, "pin removed")
+	}
+
+	if request.duration <= 0 {
+		return fmt.Errorf("%w: %s", ErrPinDurationNotPositive, request.duration)
+	}
+	until := s.timeNow().Add(request.duration)
+	return s.updater.Pin(ctx, id, request.ip, until)
+}
+
+// findRecordID returns the identifier of the record with the given key,
+// which stays valid until the records are changed.
+func findRecordID(records []librecords.Record, key string) (id uint, ok bool) {
+	for i, record := range records {
+		if recordKey(record) == key {
+			return uint(i), true
+		}
+	}
+	return 0, false
+}
+
+// expirePins removes the pins of records which expired,
+// and returns the identifiers of these records so they
+// can be updated as usual.
+func (s *Service) expirePins() (expiredIDs map[uint]struct{}, errors []error) {
+	now := s.timeNow()
+	expiredIDs = make(map[uint]struct{})
+	for i, record := range s.db.SelectAll() {
+		if record.Pin == nil || !record.Pin.Expired(now) {
+			continue
+		}
+		s.logger.Info(fmt.Sprintf("pin of record %s to %s expired",
+			recordToLogString(record), record.Pin.IP))
+		err := s.updater.Unpin(uint(i), "pin expired")
+		if err != nil {
+			err = fmt.Errorf("unpinning record %s: %w", recordToLogString(record), err)
+			errors = append(errors, err)
+			s.logger.Error(err.Error())
+			continue
+		}
+		expiredIDs[uint(i)] = struct{}{}
+	}
+	return expiredIDs, errors
+}
+
+// getNextPinExpiryDelay returns the delay until the earliest pin
+// of the records expires, and false if no record is pinned.
+func getNextPinExpiryDelay(records []librecords.Record, now time.Time) (
+	delay time.Duration, ok bool,
+) {
+	for _, record := range records {
+		if record.Pin == nil {
+			continue
+		}
+		recordDelay := record.Pin.Until.Sub(now)
+		if !ok || recordDelay < delay {
+			delay = recordDelay
+			ok = true
+		}
+	}
+	return max(delay, 0), ok
+}
+
+// resetPinTimer resets the timer to fire when the earliest
+// pin of the records expires, or stops it if no record is pinned.
+func (s *Service) resetPinTimer(timer *time.Timer) {
+	delay, ok := getNextPinExpiryDelay(s.db.SelectAll(), s.timeNow())
+	if !ok {
+		timer.Stop()
+		return
+	}
+	timer.Reset(delay)
+}
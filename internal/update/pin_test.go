@@ -0,0 +1,72 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findRecordID(t *testing.T) {
+	t.Parallel()
+
+	exampleRecord := makeTestRecord(t, "@", ipversion.IP4, records.Settings{})
+	noIPRecord := records.Record{
+		Provider: makeTestProvider(t, constants.NoIP, "@", ipversion.IP4, netip.Prefix{}),
+	}
+	recordList := []records.Record{exampleRecord, noIPRecord}
+
+	id, ok := findRecordID(recordList, recordKey(noIPRecord))
+	assert.True(t, ok)
+	assert.Equal(t, uint(1), id)
+
+	_, ok = findRecordID(recordList, "[domain: other.com | owner: @ | provider: noip | ip: ipv4]")
+	assert.False(t, ok)
+}
+
+func Test_getNextPinExpiryDelay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+
+	testCases := map[string]struct {
+		records []records.Record
+		delay   time.Duration
+		ok      bool
+	}{
+		"no_pin": {
+			records: []records.Record{{}},
+		},
+		"earliest_pin": {
+			records: []records.Record{
+				{Pin: &models.Pin{Until: now.Add(time.Hour)}},
+				{},
+				{Pin: &models.Pin{Until: now.Add(time.Minute)}},
+			},
+			delay: time.Minute,
+			ok:    true,
+		},
+		"expired_pin": {
+			records: []records.Record{
+				{Pin: &models.Pin{Until: now.Add(-time.Minute)}},
+			},
+			ok: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			delay, ok := getNextPinExpiryDelay(testCase.records, now)
+
+			assert.Equal(t, testCase.delay, delay)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package update
+
+import (
+	"errors"
+
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
+)
+
+// isRateLimited returns true and logs the error given if it is due to
+// the rate limit of a provider account, in which case the action is
+// not considered failed and is attempted again later.
+func (s *Service) isRateLimited(err error) bool {
+	if !errors.Is(err, ratelimit.ErrLimited) {
+		return false
+	}
+	s.logger.Info(err.Error() + ", deferring to the next update")
+	return true
+}
@@ -0,0 +1,75 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/provider"
+)
+
+// reconcile reads the records from their provider API, and repairs
+// records whose value no longer contains the IP address they were last
+// set to, which happens if they were modified outside of the program.
+// This is independent of public IP address changes, which are handled
+// by updateNecessary.
+func (s *Service) reconcile(ctx context.Context) (errors []error) {
+	s.shoutrrrClient.BeginCycle()
+	defer s.shoutrrrClient.EndCycle()
+
+	now := s.timeNow()
+	records := s.db.SelectAll()
+	for i, record := range records {
+		reader, ok := record.Provider.(provider.RecordReader)
+		if !ok {
+			continue
+		}
+
+		// Record types cleared by the stale policy are left as they are,
+		// since their IP address family is no longer available.
+		desiredIPs := slices.DeleteFunc(record.CurrentIPs(), func(ip netip.Addr) bool {
+			return slices.Contains(record.StaleRecordTypes, ipToRecordType(ip))
+		})
+		switch {
+		case len(desiredIPs) == 0,
+			record.Settings.MultiIP != nil,   // compared with the provider on update
+			record.Settings.RecordType != "", // content is not read from the provider
+			record.Status == constants.FAIL,
+			record.Status == constants.UPDATING,
+			record.LastBan != nil && now.Sub(*record.LastBan) < banPeriod,
+			s.inMaintenance(record, now):
+			continue
+		}
+
+		hostname := record.Provider.BuildDomainName()
+		recordIPs, err := s.updater.ReadRecord(ctx, reader, record.Settings)
+		if err != nil {
+			s.logger.Warn("reading record " + hostname + " from provider API: " + err.Error())
+			continue
+		}
+
+		for _, desiredIP := range desiredIPs {
+			if ipsContainsIP(recordIPs, desiredIP) {
+				s.logger.Debug(fmt.Sprintf("record %s has the expected IP address %s",
+					recordToLogString(record), desiredIP))
+				continue
+			}
+
+			s.logger.Warn(fmt.Sprintf("record %s was externally modified to %s, restoring it to %s",
+				recordToLogString(record), ipsToString(recordIPs), desiredIP))
+			id := uint(i)
+			err = s.updater.Repair(ctx, id, desiredIP, recordIPs)
+			if err != nil {
+				if !s.isRateLimited(err) {
+					err = fmt.Errorf("repairing record %s: %w", recordToLogString(record), err)
+					errors = append(errors, err)
+					s.logger.Error(err.Error())
+				}
+				break
+			}
+		}
+	}
+	return errors
+}
@@ -0,0 +1,335 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	providerconstants "github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReaderProvider is a provider whose record is read from
+// recordIPs, and which records the IP addresses it is updated with.
+type fakeReaderProvider struct {
+	provider.Provider
+	recordIPs  []netip.Addr
+	readErr    error
+	updateErr  error
+	updatedIPs []netip.Addr
+	proxied    bool
+}
+
+func (p *fakeReaderProvider) Proxied() bool {
+	return p.proxied
+}
+
+func (p *fakeReaderProvider) GetRecord(context.Context, *http.Client) (
+	ips []netip.Addr, err error,
+) {
+	return p.recordIPs, p.readErr
+}
+
+func (p *fakeReaderProvider) Update(_ context.Context, _ *http.Client, ip netip.Addr) (
+	newIP netip.Addr, err error,
+) {
+	if p.updateErr != nil {
+		return netip.Addr{}, p.updateErr
+	}
+	p.updatedIPs = append(p.updatedIPs, ip)
+	return ip, nil
+}
+
+type fakeDatabase struct {
+	records []records.Record
+}
+
+func (d *fakeDatabase) Select(id uint) (record records.Record, err error) {
+	return d.records[id], nil
+}
+
+func (d *fakeDatabase) SelectAll() (records []records.Record) {
+	return d.records
+}
+
+func (d *fakeDatabase) Update(id uint, record records.Record) (err error) {
+	d.records[id] = record
+	return nil
+}
+
+func (d *fakeDatabase) SetDynamic([]records.Record) {}
+
+type noopRateLimiter struct{}
+
+func (noopRateLimiter) Check(string, *ratelimit.Limit) (err error) { return nil }
+func (noopRateLimiter) Client(client *http.Client, _ string, _ *ratelimit.Limit) *http.Client {
+	return client
+}
+
+type noopShoutrrrClient struct{}
+
+func (noopShoutrrrClient) Notify(string)                                      {}
+func (noopShoutrrrClient) NotifyRecord(string, string, models.Status, string) {}
+func (noopShoutrrrClient) BeginCycle()                                        {}
+func (noopShoutrrrClient) EndCycle()                                          {}
+
+// makeReaderRecord returns a record of the fake reader provider given,
+// with a history of the IP addresses given and a success status.
+func makeReaderRecord(t *testing.T, readerProvider *fakeReaderProvider,
+	ipVersion ipversion.IPVersion, settings records.Settings, ips ...string,
+) records.Record {
+	t.Helper()
+	record := makeTestRecord(t, "sub", ipVersion, settings, ips...)
+	readerProvider.Provider = record.Provider
+	record.Provider = readerProvider
+	record.Status = constants.SUCCESS
+	return record
+}
+
+func makeTestUpdater(db Database, now time.Time) *Updater {
+	return &Updater{
+		db:             db,
+		rateLimiter:    noopRateLimiter{},
+		shoutrrrClient: noopShoutrrrClient{},
+		logger:         noopLogger{},
+		timeNow:        func() time.Time { return now },
+	}
+}
+
+func Test_Service_reconcile(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	recentBan := now.Add(-time.Minute)
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		ipVersion  ipversion.IPVersion
+		settings   records.Settings
+		history    []string
+		status     models.Status
+		lastBan    *time.Time
+		stale      []string
+		provider   *fakeReaderProvider
+		updatedIPs []netip.Addr
+		newStatus  models.Status
+		newMessage string
+		errs       []string
+	}{
+		"record_up_to_date": {
+			ipVersion: ipversion.IP4,
+			history:   []string{"1.2.3.4"},
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+			},
+			newStatus: constants.SUCCESS,
+		},
+		"record_modified": {
+			ipVersion: ipversion.IP4,
+			history:   []string{"1.2.3.4"},
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			},
+			updatedIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+			newStatus:  constants.DRIFTED,
+			newMessage: "was 5.6.7.8, restored to 1.2.3.4",
+		},
+		"record_deleted": {
+			ipVersion:  ipversion.IP4,
+			history:    []string{"1.2.3.4"},
+			provider:   &fakeReaderProvider{},
+			updatedIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+			newStatus:  constants.DRIFTED,
+			newMessage: "was <none>, restored to 1.2.3.4",
+		},
+		"dual_stack_ipv6_modified": {
+			ipVersion: ipversion.IP4or6,
+			settings:  records.Settings{DualStack: true},
+			history:   []string{"1.2.3.4", "::1"},
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("::2")},
+			},
+			updatedIPs: []netip.Addr{netip.MustParseAddr("::1")},
+			newStatus:  constants.DRIFTED,
+			newMessage: "was 1.2.3.4, ::2, restored to ::1",
+		},
+		"stale_record_deleted": {
+			ipVersion: ipversion.IP4,
+			settings:  records.Settings{StalePolicy: records.StalePolicyDelete},
+			history:   []string{"1.2.3.4"},
+			stale:     []string{providerconstants.A},
+			provider:  &fakeReaderProvider{},
+			newStatus: constants.SUCCESS,
+		},
+		"stale_record_set_to_fallback": {
+			ipVersion: ipversion.IP4or6,
+			settings: records.Settings{
+				DualStack:    true,
+				StalePolicy:  records.StalePolicyFallback,
+				FallbackIPv6: netip.MustParseAddr("::ffff"),
+			},
+			history: []string{"1.2.3.4", "::1"},
+			stale:   []string{providerconstants.AAAA},
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4"), netip.MustParseAddr("::ffff")},
+			},
+			newStatus: constants.SUCCESS,
+		},
+		"never_updated": {
+			ipVersion: ipversion.IP4,
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			},
+			newStatus: constants.SUCCESS,
+		},
+		"update_failed": {
+			ipVersion: ipversion.IP4,
+			history:   []string{"1.2.3.4"},
+			status:    constants.FAIL,
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			},
+			newStatus: constants.FAIL,
+		},
+		"banned": {
+			ipVersion: ipversion.IP4,
+			history:   []string{"1.2.3.4"},
+			lastBan:   &recentBan,
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			},
+			newStatus: constants.SUCCESS,
+		},
+		"read_error": {
+			ipVersion: ipversion.IP4,
+			history:   []string{"1.2.3.4"},
+			provider:  &fakeReaderProvider{readErr: errTest},
+			newStatus: constants.SUCCESS,
+		},
+		"repair_error": {
+			ipVersion: ipversion.IP4,
+			history:   []string{"1.2.3.4"},
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+				updateErr: errTest,
+			},
+			newStatus:  constants.FAIL,
+			newMessage: "test error",
+			errs:       []string{"repairing record sub.example.com (ipv4): test error"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			record := makeReaderRecord(t, testCase.provider, testCase.ipVersion,
+				testCase.settings, testCase.history...)
+			if testCase.status != "" {
+				record.Status = testCase.status
+			}
+			record.LastBan = testCase.lastBan
+			record.StaleRecordTypes = testCase.stale
+			db := &fakeDatabase{records: []records.Record{record}}
+			service := &Service{
+				db:             db,
+				updater:        makeTestUpdater(db, now),
+				logger:         noopLogger{},
+				shoutrrrClient: noopShoutrrrClient{},
+				timeNow:        func() time.Time { return now },
+			}
+
+			errs := service.reconcile(context.Background())
+
+			errMessages := make([]string, len(errs))
+			for i, err := range errs {
+				errMessages[i] = err.Error()
+			}
+			if len(testCase.errs) == 0 {
+				assert.Empty(t, errMessages)
+			} else {
+				assert.Equal(t, testCase.errs, errMessages)
+			}
+			assert.Equal(t, testCase.updatedIPs, testCase.provider.updatedIPs)
+			require.Len(t, db.records, 1)
+			assert.Equal(t, testCase.newStatus, db.records[0].Status)
+			assert.Equal(t, testCase.newMessage, db.records[0].Message)
+		})
+	}
+}
+
+func Test_Updater_Repair(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		updateErr  error
+		ip         netip.Addr
+		recordIPs  []netip.Addr
+		stale      []string
+		status     models.Status
+		message    string
+		newStale   []string
+		errWrapped error
+		errMessage string
+	}{
+		"success": {
+			ip:        netip.MustParseAddr("1.2.3.4"),
+			recordIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			status:    constants.DRIFTED,
+			message:   "was 5.6.7.8, restored to 1.2.3.4",
+		},
+		"stale_record_type_restored": {
+			ip:       netip.MustParseAddr("1.2.3.4"),
+			stale:    []string{providerconstants.A},
+			status:   constants.DRIFTED,
+			message:  "was <none>, restored to 1.2.3.4",
+			newStale: []string{},
+		},
+		"update_error": {
+			updateErr:  errTest,
+			ip:         netip.MustParseAddr("1.2.3.4"),
+			recordIPs:  []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			status:     constants.FAIL,
+			message:    "test error",
+			errWrapped: errTest,
+			errMessage: "test error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			readerProvider := &fakeReaderProvider{updateErr: testCase.updateErr}
+			record := makeReaderRecord(t, readerProvider, ipversion.IP4,
+				records.Settings{}, "1.2.3.4")
+			record.StaleRecordTypes = testCase.stale
+			db := &fakeDatabase{records: []records.Record{record}}
+			updater := makeTestUpdater(db, now)
+
+			err := updater.Repair(context.Background(), 0, testCase.ip, testCase.recordIPs)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.status, db.records[0].Status)
+			assert.Equal(t, testCase.message, db.records[0].Message)
+			assert.Equal(t, testCase.newStale, db.records[0].StaleRecordTypes)
+			assert.Equal(t, now, db.records[0].Time)
+		})
+	}
+}
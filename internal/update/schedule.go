@@ -0,0 +1,64 @@
+package update
+
+import (
+	"time"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// scheduleRecords sets the next update time of the records with the
+// identifiers given, using their schedule or else the global period.
+func (s *Service) scheduleRecords(records []librecords.Record,
+	recordIDs map[uint]struct{}, now time.Time,
+) {
+	for id := range recordIDs {
+		record := records[id]
+		var next time.Time
+		if record.Settings.Schedule != nil {
+			next = record.Settings.Schedule.Next(now)
+		} else {
+			next = s.getPeriodNext(record, now)
+		}
+		s.nextUpdates[recordKey(record)] = next
+	}
+}
+
+// getDueRecordIDs returns the identifiers of the records whose next
+// update time is reached, including records not scheduled yet.
+func (s *Service) getDueRecordIDs(records []librecords.Record, now time.Time) (
+	recordIDs map[uint]struct{},
+) {
+	recordIDs = make(map[uint]struct{})
+	for i, record := range records {
+		next, ok := s.nextUpdates[recordKey(record)]
+		if !ok || !now.Before(next) {
+			recordIDs[uint(i)] = struct{}{}
+		}
+	}
+	return recordIDs
+}
+
+// getNextUpdateDelay returns the duration until the earliest next
+// update time of the records, which is zero if a record is due.
+// It is at most the global period, so records added are picked up.
+func (s *Service) getNextUpdateDelay(records []librecords.Record, now time.Time) (
+	delay time.Duration,
+) {
+	delay = s.period
+	for _, record := range records {
+		next, ok := s.nextUpdates[recordKey(record)]
+		if !ok {
+			return 0
+		}
+		delay = min(delay, next.Sub(now))
+	}
+	return max(delay, 0)
+}
+
+func allRecordIDs(records []librecords.Record) (recordIDs map[uint]struct{}) {
+	recordIDs = make(map[uint]struct{}, len(records))
+	for i := range records {
+		recordIDs[uint(i)] = struct{}{}
+	}
+	return recordIDs
+}
@@ -0,0 +1,79 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/schedule"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Service_scheduling(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(owner string, recordSchedule *schedule.Schedule) records.Record {
+		return makeTestRecord(t, owner, ipversion.IP4, records.Settings{Schedule: recordSchedule})
+	}
+
+	fastSchedule, err := schedule.NewPeriod(30 * time.Second)
+	require.NoError(t, err)
+	cronSchedule, err := schedule.NewCron("0 * * * *")
+	require.NoError(t, err)
+
+	recs := []records.Record{
+		makeRecord("default", nil),
+		makeRecord("fast", fastSchedule),
+		makeRecord("hourly", cronSchedule),
+	}
+	service := &Service{
+		period:      10 * time.Minute,
+		nextUpdates: make(map[string]time.Time),
+	}
+
+	start := time.Date(2025, time.January, 15, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, allRecordIDs(recs), service.getDueRecordIDs(recs, start))
+	assert.Equal(t, time.Duration(0), service.getNextUpdateDelay(recs, start))
+
+	service.scheduleRecords(recs, allRecordIDs(recs), start)
+	assert.Empty(t, service.getDueRecordIDs(recs, start))
+	assert.Equal(t, 30*time.Second, service.getNextUpdateDelay(recs, start))
+
+	now := start.Add(30 * time.Second)
+	assert.Equal(t, map[uint]struct{}{1: {}}, service.getDueRecordIDs(recs, now))
+
+	service.scheduleRecords(recs, map[uint]struct{}{1: {}}, now)
+	now = start.Add(10 * time.Minute)
+	assert.Equal(t, map[uint]struct{}{0: {}, 1: {}}, service.getDueRecordIDs(recs, now))
+
+	service.scheduleRecords(recs, map[uint]struct{}{0: {}, 1: {}}, now)
+	now = start.Add(time.Hour)
+	assert.Equal(t, map[uint]struct{}{0: {}, 1: {}, 2: {}}, service.getDueRecordIDs(recs, now))
+}
+
+func Test_Service_scheduling_sameDomainOtherProvider(t *testing.T) {
+	t.Parallel()
+
+	fastSchedule, err := schedule.NewPeriod(30 * time.Second)
+	require.NoError(t, err)
+
+	fastProvider := makeTestProvider(t, constants.NoIP, "host", ipversion.IP4, netip.Prefix{})
+	recs := []records.Record{
+		makeTestRecord(t, "host", ipversion.IP4, records.Settings{}),
+		records.New(fastProvider, records.Settings{Schedule: fastSchedule}, nil),
+	}
+	service := &Service{
+		period:      10 * time.Minute,
+		nextUpdates: make(map[string]time.Time),
+	}
+
+	start := time.Date(2025, time.January, 15, 10, 0, 0, 0, time.UTC)
+	service.scheduleRecords(recs, allRecordIDs(recs), start)
+
+	now := start.Add(30 * time.Second)
+	assert.Equal(t, map[uint]struct{}{1: {}}, service.getDueRecordIDs(recs, now))
+}
@@ -5,51 +5,105 @@ import (
 	"errors"
 	"fmt"
 	"net/netip"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
 	"github.com/qdm12/ddns-updater/internal/healthchecksio"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
 	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/schedule"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
+// banPeriod is the duration during which a record is not changed
+// after its provider reported the update as abusive.
+const banPeriod = time.Hour
+
 type Service struct {
-	period    time.Duration
-	db        Database
-	updater   UpdaterInterface
-	cooldown  time.Duration
-	resolver  LookupIPer
-	ipGetter  PublicIPFetcher
-	logger    Logger
-	timeNow   func() time.Time
-	hioClient HealthchecksIOClient
+	period          time.Duration
+	reconcilePeriod time.Duration
+	adaptive        bool
+	reconnectWindow *schedule.Window
+	// maintenanceWindows is nil if there is no maintenance window.
+	maintenanceWindows *schedule.CronWindows
+	db                 Database
+	updater            UpdaterInterface
+	cooldown           time.Duration
+	resolver           LookupIPer
+	ipGetter           PublicIPFetcher
+	uplinkIPGetters    map[string]PublicIPFetcher
+	healthChecker      HealthChecker
+	logger             Logger
+	timeNow            func() time.Time
+	hioClient          HealthchecksIOClient
+	shoutrrrClient     ShoutrrrClient
+
+	// Failover health check states by record, only
+	// accessed in the run goroutine.
+	failoverStates map[string]*failoverState
+	// Next update times by record, only accessed in the run goroutine.
+	nextUpdates map[string]time.Time
+	// Times at which records started failing for adaptive
+	// scheduling, only accessed in the run goroutine.
+	failingSince map[string]time.Time
+	// Times at which IP addresses were last reported by record,
+	// only accessed in the run goroutine.
+	reportedIPs map[string]map[netip.Addr]time.Time
+
+	// Public IP addresses obtained during the last update
+	publicIPv4     netip.Addr
+	publicIPv6     netip.Addr
+	publicIPsMutex sync.RWMutex
 
 	// Service lifecycle
 	runCancel   context.CancelFunc
 	done        <-chan struct{}
 	force       chan struct{}
 	forceResult chan []error
+	pins        chan pinRequest
+	reports     chan reportRequest
+	dynamic     chan []librecords.Record
 }
 
 func NewService(db Database, updater UpdaterInterface, ipGetter PublicIPFetcher,
-	period time.Duration, cooldown time.Duration, logger Logger, resolver LookupIPer,
+	uplinkIPGetters map[string]PublicIPFetcher, healthChecker HealthChecker,
+	period, cooldown, reconcilePeriod time.Duration, adaptive bool,
+	reconnectWindow *schedule.Window, maintenanceWindows *schedule.CronWindows,
+	logger Logger, resolver LookupIPer,
 	timeNow func() time.Time, hioClient HealthchecksIOClient,
+	shoutrrrClient ShoutrrrClient,
 ) *Service {
 	return &Service{
-		period:      period,
-		db:          db,
-		updater:     updater,
-		force:       make(chan struct{}),
-		forceResult: make(chan []error),
-		cooldown:    cooldown,
-		resolver:    resolver,
-		ipGetter:    ipGetter,
-		logger:      logger,
-		timeNow:     timeNow,
-		hioClient:   hioClient,
+		period:             period,
+		reconcilePeriod:    reconcilePeriod,
+		adaptive:           adaptive,
+		reconnectWindow:    reconnectWindow,
+		maintenanceWindows: maintenanceWindows,
+		db:                 db,
+		updater:            updater,
+		force:              make(chan struct{}),
+		forceResult:        make(chan []error),
+		pins:               make(chan pinRequest),
+		reports:            make(chan reportRequest),
+		dynamic:            make(chan []librecords.Record),
+		cooldown:           cooldown,
+		resolver:           resolver,
+		ipGetter:           ipGetter,
+		uplinkIPGetters:    uplinkIPGetters,
+		healthChecker:      healthChecker,
+		failoverStates:     make(map[string]*failoverState),
+		nextUpdates:        make(map[string]time.Time),
+		failingSince:       make(map[string]time.Time),
+		reportedIPs:        make(map[string]map[netip.Addr]time.Time),
+		logger:             logger,
+		timeNow:            timeNow,
+		hioClient:          hioClient,
+		shoutrrrClient:     shoutrrrClient,
 	}
 }
 
@@ -110,12 +164,15 @@ func (s *Service) lookupIPsResilient(ctx context.Context, hostname string, tries
 
 func doIPVersion(records []librecords.Record) (doIP, doIPv4, doIPv6 bool) {
 	for _, record := range records {
-		switch record.Provider.IPVersion() {
-		case ipversion.IP4or6:
+		switch {
+		case record.Settings.AutoIP: // IP address detected by the provider
+		case record.Settings.DualStack:
+			doIPv4, doIPv6 = true, true
+		case record.Provider.IPVersion() == ipversion.IP4or6:
 			doIP = true
-		case ipversion.IP4:
+		case record.Provider.IPVersion() == ipversion.IP4:
 			doIPv4 = true
-		case ipversion.IP6:
+		case record.Provider.IPVersion() == ipversion.IP6:
 			doIPv6 = true
 		}
 		if doIP && doIPv4 && doIPv6 {
@@ -125,24 +182,25 @@ func doIPVersion(records []librecords.Record) (doIP, doIPv4, doIPv6 bool) {
 	return doIP, doIPv4, doIPv6
 }
 
-func (s *Service) getNewIPs(ctx context.Context, doIP, doIPv4, doIPv6 bool) (
+func (s *Service) getNewIPs(ctx context.Context, ipGetter PublicIPFetcher,
+	doIP, doIPv4, doIPv6 bool) (
 	ip, ipv4, ipv6 netip.Addr, errors []error,
 ) {
 	var err error
 	if doIP {
-		ip, err = tryAndRepeatGettingIP(ctx, s.ipGetter.IP, s.logger, ipversion.IP4or6)
+		ip, err = tryAndRepeatGettingIP(ctx, ipGetter.IP, s.logger, ipversion.IP4or6)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if doIPv4 {
-		ipv4, err = tryAndRepeatGettingIP(ctx, s.ipGetter.IP4, s.logger, ipversion.IP4)
+		ipv4, err = tryAndRepeatGettingIP(ctx, ipGetter.IP4, s.logger, ipversion.IP4)
 		if err != nil {
 			errors = append(errors, err)
 		}
 	}
 	if doIPv6 {
-		ipv6, err = tryAndRepeatGettingIP(ctx, s.ipGetter.IP6, s.logger, ipversion.IP6)
+		ipv6, err = tryAndRepeatGettingIP(ctx, ipGetter.IP6, s.logger, ipversion.IP6)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -151,10 +209,14 @@ func (s *Service) getNewIPs(ctx context.Context, doIP, doIPv4, doIPv6 bool) (
 }
 
 func (s *Service) getRecordIDsToUpdate(ctx context.Context, records []librecords.Record,
-	ip, ipv4, ipv6 netip.Addr,
+	selectedIDs map[uint]struct{}, ip, ipv4, ipv6 netip.Addr,
 ) (recordIDs map[uint]struct{}) {
 	recordIDs = make(map[uint]struct{})
 	for i, record := range records {
+		_, ok := selectedIDs[uint(i)]
+		if !ok {
+			continue
+		}
 		shouldUpdate := s.shouldUpdateRecord(ctx, record, ip, ipv4, ipv6)
 		if shouldUpdate {
 			id := uint(i)
@@ -167,35 +229,48 @@ func (s *Service) getRecordIDsToUpdate(ctx context.Context, records []librecords
 func (s *Service) shouldUpdateRecord(ctx context.Context, record librecords.Record,
 	ip, ipv4, ipv6 netip.Addr,
 ) (update bool) {
-	now := s.timeNow()
-
-	isWithinCooldown := now.Sub(record.History.GetSuccessTime()) < s.cooldown
-	if isWithinCooldown {
-		s.logger.Debug(fmt.Sprintf(
-			"record %s is within cooldown period of %s, skipping update",
-			recordToLogString(record), s.cooldown))
+	if s.isUpdateHeld(record) {
 		return false
 	}
 
-	const banPeriod = time.Hour
-	isWithinBanPeriod := record.LastBan != nil && now.Sub(*record.LastBan) < banPeriod
-	if isWithinBanPeriod {
-		s.logger.Info(fmt.Sprintf(
-			"record %s is within ban period of %s started at %s, skipping update",
-			recordToLogString(record), banPeriod, *record.LastBan))
-		return false
+	if record.Settings.AutoIP {
+		return s.shouldUpdateAutoRecord(record)
+	}
+
+	if record.Settings.RecordType != "" {
+		updateIPs := getContentUpdateIPs(record, ip, ipv4, ipv6)
+		return s.shouldUpdateContentRecord(record, updateIPs)
+	}
+
+	if record.Settings.DualStack {
+		return s.shouldUpdateDualStackRecord(ctx, record, ipv4, ipv6)
 	}
 
 	hostname := record.Provider.BuildDomainName()
 	ipVersion := record.Provider.IPVersion()
-	publicIP := getIPMatchingVersion(ip, ipv4, ipv6, ipVersion)
+	publicIP := computeIP(record, getIPMatchingVersion(ip, ipv4, ipv6, ipVersion), ipVersion)
 
 	if !publicIP.IsValid() {
 		s.logger.Warn(fmt.Sprintf("Skipping update for %s because %s address was not found",
 			hostname, ipVersionToIPKind(ipVersion)))
 		return false
-	} else if publicIP.Is6() {
-		publicIP = ipv6WithSuffix(publicIP, record.Provider.IPv6Suffix())
+	}
+
+	if slices.Contains(record.StaleRecordTypes, ipToRecordType(publicIP)) {
+		s.logger.Info(fmt.Sprintf("%s address is available again for stale %s record %s",
+			ipVersionToIPKind(ipVersion), ipToRecordType(publicIP), hostname))
+		return true
+	}
+
+	reader, ok := record.Provider.(provider.RecordReader)
+	if ok {
+		update, err := s.shouldUpdateRecordWithReader(ctx, reader,
+			record.Settings, hostname, ipVersion, publicIP)
+		if err == nil {
+			return update
+		}
+		s.logger.Warn("reading record " + hostname + " from provider API: " + err.Error())
+		// fall back on the stored IP address or a DNS lookup
 	}
 
 	if record.Provider.Proxied() {
@@ -205,6 +280,72 @@ func (s *Service) shouldUpdateRecord(ctx context.Context, record librecords.Reco
 	return s.shouldUpdateRecordWithLookup(ctx, hostname, ipVersion, publicIP)
 }
 
+// isUpdateHeld returns true if the record must not be updated, because
+// it is within its cooldown or ban period, pinned or failed over.
+func (s *Service) isUpdateHeld(record librecords.Record) (held bool) {
+	now := s.timeNow()
+
+	cooldown := s.cooldown
+	if record.Settings.Cooldown != nil {
+		cooldown = *record.Settings.Cooldown
+	}
+	isWithinCooldown := now.Sub(record.History.GetSuccessTime()) < cooldown
+	if isWithinCooldown {
+		s.logger.Debug(fmt.Sprintf(
+			"record %s is within cooldown period of %s, skipping update",
+			recordToLogString(record), cooldown))
+		return true
+	}
+
+	isWithinBanPeriod := record.LastBan != nil && now.Sub(*record.LastBan) < banPeriod
+	if isWithinBanPeriod {
+		s.logger.Info(fmt.Sprintf(
+			"record %s is within ban period of %s started at %s, skipping update",
+			recordToLogString(record), banPeriod, *record.LastBan))
+		return true
+	}
+
+	if record.Pin != nil {
+		s.logger.Debug(fmt.Sprintf("record %s is pinned to %s until %s, skipping update",
+			recordToLogString(record), record.Pin.IP, record.Pin.Until.Format(time.DateTime)))
+		return true
+	}
+
+	if record.FailedOver {
+		s.logger.Debug(fmt.Sprintf("record %s is failed over, skipping update",
+			recordToLogString(record)))
+		return true
+	}
+
+	return false
+}
+
+// autoIPUnchangedInterval is the minimum interval between two updates
+// of a record with its IP address detected by its provider, after its
+// last update did not change its IP address. Providers may ban accounts
+// repeatedly sending updates without an IP address change.
+const autoIPUnchangedInterval = time.Hour
+
+// shouldUpdateAutoRecord returns true if the record with its IP address
+// detected by its provider should be updated. Since the IP address is only
+// known to the provider, the record is updated every time it is scheduled,
+// unless its last update detected the IP address already in its history,
+// or no IP address was reported, less than autoIPUnchangedInterval ago.
+func (s *Service) shouldUpdateAutoRecord(record librecords.Record) (update bool) {
+	lastUpdate := record.Time
+	// The history is only appended to if the IP address detected changed,
+	// after the record time is set at the start of the update.
+	unchanged := record.Status == constants.SUCCESS &&
+		record.History.GetSuccessTime().Before(lastUpdate)
+	if unchanged && s.timeNow().Sub(lastUpdate) < autoIPUnchangedInterval {
+		s.logger.Debug(fmt.Sprintf("record %s IP address was unchanged at its last update, "+
+			"skipping update until %s", recordToLogString(record),
+			lastUpdate.Add(autoIPUnchangedInterval).Format(time.DateTime)))
+		return false
+	}
+	return true
+}
+
 func (s *Service) shouldUpdateRecordNoLookup(hostname string, ipVersion ipversion.IPVersion,
 	lastIP, publicIP netip.Addr,
 ) (update bool) {
@@ -217,6 +358,23 @@ func (s *Service) shouldUpdateRecordNoLookup(hostname string, ipVersion ipversio
 	return false
 }
 
+func (s *Service) shouldUpdateRecordWithReader(ctx context.Context, reader provider.RecordReader,
+	settings librecords.Settings, hostname string, ipVersion ipversion.IPVersion, publicIP netip.Addr,
+) (update bool, err error) {
+	recordIPs, err := s.updater.ReadRecord(ctx, reader, settings)
+	if err != nil {
+		return false, err
+	}
+
+	ipKind := ipVersionToIPKind(ipVersion)
+	if !ipsContainsIP(recordIPs, publicIP) {
+		s.logInfoLookupUpdate(hostname, ipKind, recordIPs, publicIP)
+		return true, nil
+	}
+	s.logDebugLookupSkip(hostname, ipKind, recordIPs, publicIP)
+	return false, nil
+}
+
 func (s *Service) shouldUpdateRecordWithLookup(ctx context.Context, hostname string,
 	ipVersion ipversion.IPVersion, publicIP netip.Addr,
 ) (update bool) {
@@ -283,18 +441,45 @@ func getIPsMatchingVersion(ip, ipv4, ipv6 []netip.Addr, ipVersion ipversion.IPVe
 	}
 }
 
-func setInitialUpToDateStatus(db Database, id uint, updateIP netip.Addr, now time.Time) error {
+// getUpdateIPs returns the valid IP addresses the record should be set to,
+// which are both the IPv4 and IPv6 addresses for dual-stack records.
+func getUpdateIPs(record librecords.Record, ip, ipv4, ipv6 netip.Addr) (updateIPs []netip.Addr) {
+	ipVersion := record.Provider.IPVersion()
+	ips := []netip.Addr{computeIP(record, getIPMatchingVersion(ip, ipv4, ipv6, ipVersion), ipVersion)}
+	if record.Settings.DualStack {
+		recordIPv4, recordIPv6 := getDualStackIPs(record, ipv4, ipv6)
+		ips = []netip.Addr{recordIPv4, recordIPv6}
+	}
+	for _, ip := range ips {
+		if ip.IsValid() {
+			updateIPs = append(updateIPs, ip)
+		}
+	}
+	return updateIPs
+}
+
+func setInitialUpToDateStatus(db Database, id uint, updateIPs []netip.Addr, now time.Time) error {
 	record, err := db.Select(id)
 	if err != nil {
 		return err
 	}
 	record.Status = constants.UPTODATE
 	record.Time = now
-	if !record.History.GetCurrentIP().IsValid() {
-		record.History = append(record.History, models.HistoryEvent{
-			IP:   updateIP,
-			Time: now,
-		})
+	for _, updateIP := range updateIPs {
+		currentIP := record.History.GetCurrentIP()
+		switch {
+		case !record.Settings.DualStack:
+		case updateIP.Is4():
+			currentIP = record.History.GetCurrentIP4()
+		default:
+			currentIP = record.History.GetCurrentIP6()
+		}
+		if !currentIP.IsValid() {
+			record.History = append(record.History, models.HistoryEvent{
+				IP:   updateIP,
+				Time: now,
+			})
+		}
 	}
 	return db.Update(id, record)
 }
@@ -310,17 +495,122 @@ func setInitialPublicIPFailStatus(db Database, id uint, now time.Time) error {
 	return db.Update(id, record)
 }
 
-func (s *Service) updateNecessary(ctx context.Context) (errors []error) {
+func setInitialPinnedStatus(db Database, id uint, now time.Time) error {
+	record, err := db.Select(id)
+	if err != nil {
+		return err
+	}
+	record.Status = constants.PINNED
+	record.Message = "pinned to " + record.Pin.IP.String() + " until " +
+		record.Pin.Until.Format(time.DateTime)
+	record.Time = now
+	return db.Update(id, record)
+}
+
+func setInitialFailedOverStatus(db Database, id uint, now time.Time) error {
+	record, err := db.Select(id)
+	if err != nil {
+		return err
+	}
+	record.Status = constants.FAILEDOVER
+	record.Message = "pointing to backup " + ipsToString(record.CurrentIPs()) +
+		" since primary target is unhealthy"
+	record.Time = now
+	return db.Update(id, record)
+}
+
+// updateNecessary updates the records with the identifiers given if
+// necessary, or all the records if recordIDs is nil, and schedules
+// their next update.
+func (s *Service) updateNecessary(ctx context.Context, recordIDs map[uint]struct{}) (
+	errors []error,
+) {
+	// Record notifications of this cycle are sent as a single message.
+	s.shoutrrrClient.BeginCycle()
+	defer s.shoutrrrClient.EndCycle()
+
+	now := s.timeNow()
+	_, errors = s.expirePins()
+
 	records := s.db.SelectAll()
-	doIP, doIPv4, doIPv6 := doIPVersion(records)
-	s.logger.Debug(fmt.Sprintf("configured to fetch IP: v4 or v6: %t, v4: %t, v6: %t", doIP, doIPv4, doIPv6))
-	ip, ipv4, ipv6, errors := s.getNewIPs(ctx, doIP, doIPv4, doIPv6)
-	s.logger.Debug(fmt.Sprintf("your public IP address are: v4 or v6: %s, v4: %s, v6: %s", ip, ipv4, ipv6))
-	for _, err := range errors {
+	if recordIDs == nil {
+		recordIDs = allRecordIDs(records)
+	}
+	recordIDs = withFollowerIDs(records, recordIDs)
+	s.deferMaintenanceRecords(records, recordIDs, now)
+	for _, uplinkName := range getUplinkNames(records) {
+		uplinkRecordIDs := filterUplinkRecordIDs(records, recordIDs, uplinkName)
+		if len(uplinkRecordIDs) == 0 {
+			continue
+		}
+		uplinkErrors := s.updateUplinkRecords(ctx, records, uplinkName, uplinkRecordIDs)
+		errors = append(errors, uplinkErrors...)
+	}
+	multiIPErrors := s.updateMultiIPRecords(ctx, records, recordIDs)
+	errors = append(errors, multiIPErrors...)
+	// Follow records are updated with the state of the records
+	// they follow after the update of these.
+	followErrors := s.updateFollowRecords(ctx, s.db.SelectAll(), recordIDs)
+	errors = append(errors, followErrors...)
+	// Records are scheduled with their state after the update.
+	s.scheduleRecords(s.db.SelectAll(), recordIDs, now)
+
+	healthchecksIOState := healthchecksio.Ok
+	if len(errors) > 0 {
+		healthchecksIOState = healthchecksio.Fail
+	}
+
+	err := s.hioClient.Ping(ctx, healthchecksIOState)
+	if err != nil {
+		s.logger.Error("pinging healthchecks.io failed: " + err.Error())
+	}
+
+	return errors
+}
+
+// updateUplinkRecords fetches the public IP addresses over the uplink
+// given and updates the records with the identifiers given if necessary,
+// which all use this uplink.
+// The uplink name is empty for the default network path.
+func (s *Service) updateUplinkRecords(ctx context.Context, records []librecords.Record,
+	uplinkName string, uplinkRecordIDs map[uint]struct{},
+) (errors []error) {
+	ipGetter, err := s.getIPGetter(uplinkName)
+	if err != nil {
 		s.logger.Error(err.Error())
+		return []error{err}
 	}
 
-	recordIDs := s.getRecordIDsToUpdate(ctx, records, ip, ipv4, ipv6)
+	logPrefix := ""
+	if uplinkName != "" {
+		logPrefix = "uplink " + uplinkName + ": "
+	}
+
+	doIP, doIPv4, doIPv6 := doIPVersion(selectRecords(records, uplinkRecordIDs))
+	s.logger.Debug(fmt.Sprintf("%sconfigured to fetch IP: v4 or v6: %t, v4: %t, v6: %t",
+		logPrefix, doIP, doIPv4, doIPv6))
+	ip, ipv4, ipv6, errors := s.getNewIPs(ctx, ipGetter, doIP, doIPv4, doIPv6)
+	s.logger.Debug(fmt.Sprintf("%syour public IP address are: v4 or v6: %s, v4: %s, v6: %s",
+		logPrefix, ip, ipv4, ipv6))
+	for _, err := range errors {
+		s.logger.Error(logPrefix + err.Error())
+	}
+	if uplinkName == "" {
+		s.setPublicIPs(ip, ipv4, ipv6, doIP || doIPv4, doIP || doIPv6)
+	}
+
+	updateErrors := s.updateRecords(ctx, records, uplinkRecordIDs, ip, ipv4, ipv6)
+	errors = append(errors, updateErrors...)
+	return errors
+}
+
+// updateRecords updates the records with the identifiers given if
+// necessary, using the IP addresses given, and applies their stale
+// policy for IP families without an IP address.
+func (s *Service) updateRecords(ctx context.Context, records []librecords.Record,
+	selectedIDs map[uint]struct{}, ip, ipv4, ipv6 netip.Addr,
+) (errors []error) {
+	recordIDs := s.getRecordIDsToUpdate(ctx, records, selectedIDs, ip, ipv4, ipv6)
 
 	// Current time is used to set initial states for records already
 	// up to date or in the fail state due to the public IP not found.
@@ -330,14 +620,40 @@ func (s *Service) updateNecessary(ctx context.Context) (errors []error) {
 
 	for i, record := range records {
 		id := uint(i)
+		_, selected := selectedIDs[id]
 		_, requireUpdate := recordIDs[id]
-		if requireUpdate || record.Status != constants.UNSET {
+		if !selected || requireUpdate || record.Status != constants.UNSET {
 			continue
 		}
 
-		ipVersion := record.Provider.IPVersion()
-		updateIP := getIPMatchingVersion(ip, ipv4, ipv6, ipVersion)
-		if !updateIP.IsValid() {
+		if record.Pin != nil { // pin restored from the database
+			err := setInitialPinnedStatus(s.db, id, now)
+			if err != nil {
+				err = fmt.Errorf("setting initial pinned status: %w", err)
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+			}
+			continue
+		}
+
+		if record.FailedOver { // failover state restored from the database
+			err := setInitialFailedOverStatus(s.db, id, now)
+			if err != nil {
+				err = fmt.Errorf("setting initial failed over status: %w", err)
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+			}
+			continue
+		}
+
+		updateIPs := getUpdateIPs(record, ip, ipv4, ipv6)
+		if record.Settings.AutoIP {
+			// auto record within its cooldown or ban period
+			updateIPs = record.CurrentIPs()
+			if len(updateIPs) == 0 {
+				continue
+			}
+		} else if len(updateIPs) == 0 {
 			// warning was already logged in getRecordIDsToUpdate
 			err := setInitialPublicIPFailStatus(s.db, id, now)
 			if err != nil {
@@ -346,43 +662,99 @@ func (s *Service) updateNecessary(ctx context.Context) (errors []error) {
 				s.logger.Error(err.Error())
 			}
 			continue
-		} else if updateIP.Is6() {
-			updateIP = ipv6WithSuffix(updateIP, record.Provider.IPv6Suffix())
 		}
 
-		err := setInitialUpToDateStatus(s.db, id, updateIP, now)
+		err := setInitialUpToDateStatus(s.db, id, updateIPs, now)
 		if err != nil {
 			err = fmt.Errorf("setting initial up to date status: %w", err)
 			errors = append(errors, err)
 			s.logger.Error(err.Error())
 		}
 	}
+	// Records of the same provider account are updated together
+	// if their provider accepts several records in a single API call.
+	batches, recordIDs := makeBatches(records, recordIDs, ip, ipv4, ipv6)
+	for _, batch := range batches {
+		batchRecords := selectBatchRecords(records, batch)
+		updateIP := getUpdateIPs(batchRecords[0], ip, ipv4, ipv6)[0]
+		s.logger.Info("Updating " + recordsToLogString(batchRecords) + " to use " +
+			updateIP.String() + " in a single request")
+		err := s.updater.UpdateBatch(ctx, batch, updateIP)
+		if err != nil && !s.isRateLimited(err) {
+			errors = append(errors, err)
+			s.logger.Error(err.Error())
+		}
+	}
 	for id := range recordIDs {
 		record := records[id]
-		updateIP := getIPMatchingVersion(ip, ipv4, ipv6, record.Provider.IPVersion())
-		// Note: each record id has a matching valid public IP address.
-		if updateIP.Is6() {
-			updateIP = ipv6WithSuffix(updateIP, record.Provider.IPv6Suffix())
+		if record.Settings.RecordType != "" {
+			updateIPs := getContentUpdateIPs(record, ip, ipv4, ipv6)
+			s.logger.Info("Updating " + record.Settings.RecordType + " record " +
+				record.Provider.String() + " with " + ipsToString(updateIPs))
+			err := s.updater.UpdateContent(ctx, id, updateIPs)
+			if err != nil && !s.isRateLimited(err) {
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+			}
+			continue
 		}
-		s.logger.Info("Updating record " + record.Provider.String() + " to use " + updateIP.String())
-		err := s.updater.Update(ctx, id, updateIP)
-		if err != nil {
+		if record.Settings.AutoIP {
+			s.logger.Info("Updating record " + record.Provider.String() + " with the IP detected by its provider")
+			err := s.updater.UpdateAuto(ctx, id)
+			if err != nil && !s.isRateLimited(err) {
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+			}
+			continue
+		}
+		// Note: each record id has at least one matching valid public IP address.
+		updateIPs := getUpdateIPs(record, ip, ipv4, ipv6)
+		s.logger.Info("Updating record " + record.Provider.String() + " to use " + ipsToString(updateIPs))
+		var err error
+		if record.Settings.DualStack {
+			recordIPv4, recordIPv6 := getDualStackIPs(record, ipv4, ipv6)
+			err = s.updater.UpdateDualStack(ctx, id, recordIPv4, recordIPv6)
+		} else {
+			err = s.updater.Update(ctx, id, updateIPs[0])
+		}
+		if err != nil && !s.isRateLimited(err) {
 			errors = append(errors, err)
 			s.logger.Error(err.Error())
 		}
 	}
 
-	healthchecksIOState := healthchecksio.Ok
-	if len(errors) > 0 {
-		healthchecksIOState = healthchecksio.Fail
-	}
+	staleErrors := s.clearStaleRecords(ctx, records, selectedIDs, ip, ipv4, ipv6)
+	errors = append(errors, staleErrors...)
 
-	err := s.hioClient.Ping(ctx, healthchecksIOState)
-	if err != nil {
-		s.logger.Error("pinging healthchecks.io failed: " + err.Error())
+	return errors
+}
+
+// setPublicIPs sets the public IP addresses obtained, where
+// fetchedIPv4 and fetchedIPv6 are false for IP families not fetched
+// during the update, to keep their previous public IP address.
+func (s *Service) setPublicIPs(ip, ipv4, ipv6 netip.Addr, fetchedIPv4, fetchedIPv6 bool) {
+	switch {
+	case ip.Is4() && !ipv4.IsValid():
+		ipv4 = ip
+	case ip.Is6() && !ipv6.IsValid():
+		ipv6 = ip
+	}
+	s.publicIPsMutex.Lock()
+	defer s.publicIPsMutex.Unlock()
+	if fetchedIPv4 {
+		s.publicIPv4 = ipv4
 	}
+	if fetchedIPv6 {
+		s.publicIPv6 = ipv6
+	}
+}
 
-	return errors
+// PublicIPs returns the public IPv4 and IPv6 addresses obtained
+// during the last update, which can be invalid if not found.
+func (s *Service) PublicIPs() (ipv4, ipv6 netip.Addr) {
+	s.publicIPsMutex.RLock()
+	defer s.publicIPsMutex.RUnlock()
+	return s.publicIPv4, s.publicIPv6
 }
 
 func (s *Service) String() string {
@@ -408,16 +780,62 @@ func (s *Service) run(ctx context.Context, ready chan<- struct{},
 	done chan<- struct{},
 ) {
 	defer close(done)
-	ticker := time.NewTicker(s.period)
+	records := s.db.SelectAll()
+	s.scheduleRecords(records, allRecordIDs(records), s.timeNow())
+	timer := time.NewTimer(s.getNextUpdateDelay(records, s.timeNow()))
+	defer timer.Stop()
+	var reconcileTick <-chan time.Time
+	if s.reconcilePeriod > 0 {
+		reconcileTicker := time.NewTicker(s.reconcilePeriod)
+		defer reconcileTicker.Stop()
+		reconcileTick = reconcileTicker.C
+	}
+	failoverTicker := time.NewTicker(failoverCheckPeriod)
+	defer failoverTicker.Stop()
+	// Pins are expired from their own timer, so that records get
+	// updated as soon as their pin expires and not at their next update.
+	pinTimer := time.NewTimer(0)
+	defer pinTimer.Stop()
+	s.resetPinTimer(pinTimer)
 	close(ready)
 	for {
 		select {
-		case <-ticker.C:
-			s.updateNecessary(ctx)
+		case <-timer.C:
+			recordIDs := s.getDueRecordIDs(s.db.SelectAll(), s.timeNow())
+			if len(recordIDs) > 0 {
+				s.updateNecessary(ctx, recordIDs)
+			}
+			timer.Reset(s.getNextUpdateDelay(s.db.SelectAll(), s.timeNow()))
+			s.resetPinTimer(pinTimer)
+		case <-pinTimer.C:
+			expiredIDs, _ := s.expirePins()
+			if len(expiredIDs) > 0 {
+				s.updateNecessary(ctx, expiredIDs)
+				timer.Reset(s.getNextUpdateDelay(s.db.SelectAll(), s.timeNow()))
+			}
+			s.resetPinTimer(pinTimer)
+		case <-reconcileTick:
+			s.reconcile(ctx)
+		case <-failoverTicker.C:
+			s.checkFailovers(ctx)
+		case request := <-s.pins:
+			request.result <- s.pin(ctx, request)
+			s.resetPinTimer(pinTimer)
+		case request := <-s.reports:
+			request.result <- s.report(ctx, request)
+			timer.Reset(s.getNextUpdateDelay(s.db.SelectAll(), s.timeNow()))
 		case <-s.force:
-			s.forceResult <- s.updateNecessary(ctx)
+			s.forceResult <- s.updateNecessary(ctx, nil)
+			timer.Reset(s.getNextUpdateDelay(s.db.SelectAll(), s.timeNow()))
+			s.resetPinTimer(pinTimer)
+		case dynamicRecords := <-s.dynamic:
+			// Records are replaced between update cycles only,
+			// since record identifiers may change.
+			s.db.SetDynamic(dynamicRecords)
+			s.updateNecessary(ctx, nil)
+			timer.Reset(s.getNextUpdateDelay(s.db.SelectAll(), s.timeNow()))
+			s.resetPinTimer(pinTimer)
 		case <-ctx.Done():
-			ticker.Stop()
 			return
 		}
 	}
@@ -439,3 +857,16 @@ func (s *Service) ForceUpdate(ctx context.Context) (errs []error) {
 	}
 	return errs
 }
+
+// SetDynamicRecords replaces the dynamically discovered records
+// and updates them if necessary.
+func (s *Service) SetDynamicRecords(ctx context.Context,
+	dynamicRecords []librecords.Record,
+) (err error) {
+	select {
+	case s.dynamic <- dynamicRecords:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,158 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver struct {
+	ipv4s []netip.Addr
+}
+
+func (r fakeResolver) LookupNetIP(_ context.Context, network, _ string) (
+	ips []netip.Addr, err error,
+) {
+	if network == "ip4" {
+		return r.ipv4s, nil
+	}
+	return nil, nil
+}
+
+func Test_Service_shouldUpdateRecord(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		provider    *fakeReaderProvider
+		history     []string
+		resolvedIPs []netip.Addr
+		update      bool
+	}{
+		"record_up_to_date": {
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+			},
+			resolvedIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+		},
+		"record_outdated": {
+			provider: &fakeReaderProvider{
+				recordIPs: []netip.Addr{netip.MustParseAddr("5.6.7.8")},
+			},
+			resolvedIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+			update:      true,
+		},
+		"record_missing": {
+			provider:    &fakeReaderProvider{},
+			resolvedIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+			update:      true,
+		},
+		"read_error_falls_back_on_lookup": {
+			provider:    &fakeReaderProvider{readErr: errTest},
+			resolvedIPs: []netip.Addr{netip.MustParseAddr("1.2.3.4")},
+		},
+		"read_error_falls_back_on_history_for_proxied": {
+			provider: &fakeReaderProvider{readErr: errTest, proxied: true},
+			history:  []string{"5.6.7.8"},
+			update:   true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			record := makeReaderRecord(t, testCase.provider, ipversion.IP4,
+				records.Settings{}, testCase.history...)
+			db := &fakeDatabase{records: []records.Record{record}}
+			service := &Service{
+				db:       db,
+				updater:  makeTestUpdater(db, now),
+				resolver: fakeResolver{ipv4s: testCase.resolvedIPs},
+				logger:   noopLogger{},
+				timeNow:  func() time.Time { return now },
+			}
+
+			ip := netip.MustParseAddr("1.2.3.4")
+			update := service.shouldUpdateRecord(context.Background(), record,
+				ip, ip, netip.Addr{})
+
+			assert.Equal(t, testCase.update, update)
+		})
+	}
+}
+
+func Test_Service_shouldUpdateAutoRecord(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+
+	testCases := map[string]struct {
+		status      models.Status
+		lastUpdate  time.Time
+		ipChangedAt time.Time
+		update      bool
+	}{
+		"never_updated": {
+			update: true,
+		},
+		"ip_changed_at_last_update": {
+			status:      constants.SUCCESS,
+			lastUpdate:  now.Add(-time.Minute),
+			ipChangedAt: now.Add(-time.Minute + time.Second),
+			update:      true,
+		},
+		"ip_unchanged_at_recent_update": {
+			status:      constants.SUCCESS,
+			lastUpdate:  now.Add(-time.Minute),
+			ipChangedAt: now.Add(-2 * time.Hour),
+		},
+		"ip_unchanged_at_old_update": {
+			status:      constants.SUCCESS,
+			lastUpdate:  now.Add(-autoIPUnchangedInterval),
+			ipChangedAt: now.Add(-2 * time.Hour),
+			update:      true,
+		},
+		"last_update_failed": {
+			status:      constants.FAIL,
+			lastUpdate:  now.Add(-time.Minute),
+			ipChangedAt: now.Add(-2 * time.Hour),
+			update:      true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			record := makeTestRecord(t, "sub", ipversion.IP4, records.Settings{AutoIP: true})
+			if !testCase.ipChangedAt.IsZero() {
+				record.History = models.History{{
+					IP:   netip.MustParseAddr("1.2.3.4"),
+					Time: testCase.ipChangedAt,
+				}}
+			}
+			record.Status = testCase.status
+			record.Time = testCase.lastUpdate
+			service := &Service{
+				logger:  noopLogger{},
+				timeNow: func() time.Time { return now },
+			}
+
+			update := service.shouldUpdateRecord(context.Background(), record,
+				netip.Addr{}, netip.Addr{}, netip.Addr{})
+
+			assert.Equal(t, testCase.update, update)
+		})
+	}
+}
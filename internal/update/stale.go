@@ -0,0 +1,113 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// clearStaleRecords applies the stale policy of records for which the
+// public IP address of an IP family they manage can no longer be obtained.
+// previousRecords are the records before the update cycle, and are used
+// to detect IPv4 or IPv6 records which changed IP family.
+// Only the records with the identifiers given are considered.
+func (s *Service) clearStaleRecords(ctx context.Context, previousRecords []librecords.Record,
+	recordIDs map[uint]struct{}, ip, ipv4, ipv6 netip.Addr,
+) (errors []error) {
+	now := s.timeNow()
+	records := s.db.SelectAll()
+	for i, record := range records {
+		_, selected := recordIDs[uint(i)]
+		switch {
+		case !selected,
+			record.Settings.AutoIP,
+			record.FailedOver,
+			record.Pin != nil,
+			record.Settings.StalePolicy == librecords.StalePolicyKeep,
+			record.Settings.StalePolicy == "",
+			record.LastBan != nil && now.Sub(*record.LastBan) < banPeriod:
+			continue
+		}
+
+		recordTypes := getStaleRecordTypes(previousRecords[i], record, ip, ipv4, ipv6)
+		for _, recordType := range recordTypes {
+			if slices.Contains(record.StaleRecordTypes, recordType) {
+				continue
+			}
+			s.logger.Info(fmt.Sprintf("applying stale policy %s to %s record of %s",
+				record.Settings.StalePolicy, recordType, recordToLogString(record)))
+			err := s.updater.ClearStale(ctx, uint(i), recordType)
+			if err != nil && !s.isRateLimited(err) {
+				err = fmt.Errorf("clearing stale %s record of %s: %w",
+					recordType, recordToLogString(record), err)
+				errors = append(errors, err)
+				s.logger.Error(err.Error())
+			}
+		}
+	}
+	return errors
+}
+
+// getStaleRecordTypes returns the A and/or AAAA record types
+// of the record which became stale.
+func getStaleRecordTypes(previousRecord, record librecords.Record,
+	ip, ipv4, ipv6 netip.Addr,
+) (recordTypes []string) {
+	if record.Settings.DualStack {
+		if !ipv4.IsValid() && record.History.GetCurrentIP4().IsValid() {
+			recordTypes = append(recordTypes, constants.A)
+		}
+		if !ipv6.IsValid() && record.History.GetCurrentIP6().IsValid() {
+			recordTypes = append(recordTypes, constants.AAAA)
+		}
+		return recordTypes
+	}
+
+	switch record.Provider.IPVersion() {
+	case ipversion.IP4:
+		if ipv4.IsValid() || !record.History.GetCurrentIP().IsValid() {
+			return nil
+		}
+		return []string{constants.A}
+	case ipversion.IP6:
+		if ipv6.IsValid() || !record.History.GetCurrentIP().IsValid() {
+			return nil
+		}
+		return []string{constants.AAAA}
+	default:
+		previousIP := previousRecord.History.GetCurrentIP()
+		currentIP := record.History.GetCurrentIP()
+		switch {
+		case !previousIP.IsValid():
+			return nil
+		case !ip.IsValid():
+			// no IP address available at all
+		case previousIP.Is4() == ip.Is4():
+			return nil
+		case !currentIP.IsValid() || currentIP.Is4() != ip.Is4():
+			// the record was not yet updated to the new IP family,
+			// so keep the previous IP family record in place.
+			return nil
+		}
+		return []string{ipToRecordType(previousIP)}
+	}
+}
+
+func ipToRecordType(ip netip.Addr) (recordType string) {
+	if ip.Is6() {
+		return constants.AAAA
+	}
+	return constants.A
+}
+
+func removeRecordTypeOf(recordTypes []string, ip netip.Addr) []string {
+	recordType := ipToRecordType(ip)
+	return slices.DeleteFunc(slices.Clone(recordTypes), func(s string) bool {
+		return s == recordType
+	})
+}
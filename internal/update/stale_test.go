@@ -0,0 +1,106 @@
+package update
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/provider/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getStaleRecordTypes(t *testing.T) {
+	t.Parallel()
+
+	makeRecord := func(ipVersion ipversion.IPVersion, ips ...string) records.Record {
+		return makeTestRecord(t, "sub", ipVersion, records.Settings{}, ips...)
+	}
+	makeDualStackRecord := func(ips ...string) records.Record {
+		record := makeRecord(ipversion.IP4or6, ips...)
+		record.Settings.DualStack = true
+		return record
+	}
+
+	ipv4 := netip.MustParseAddr("1.2.3.4")
+	ipv6 := netip.MustParseAddr("::1")
+
+	testCases := map[string]struct {
+		previousRecord records.Record
+		record         records.Record
+		ip             netip.Addr
+		ipv4           netip.Addr
+		ipv6           netip.Addr
+		recordTypes    []string
+	}{
+		"ipv4_available": {
+			previousRecord: makeRecord(ipversion.IP4, "1.2.3.4"),
+			record:         makeRecord(ipversion.IP4, "1.2.3.4"),
+			ipv4:           ipv4,
+		},
+		"ipv4_missing": {
+			previousRecord: makeRecord(ipversion.IP4, "1.2.3.4"),
+			record:         makeRecord(ipversion.IP4, "1.2.3.4"),
+			recordTypes:    []string{constants.A},
+		},
+		"ipv4_missing_never_set": {
+			previousRecord: makeRecord(ipversion.IP4),
+			record:         makeRecord(ipversion.IP4),
+		},
+		"ipv6_missing": {
+			previousRecord: makeRecord(ipversion.IP6, "::1"),
+			record:         makeRecord(ipversion.IP6, "::1"),
+			ipv4:           ipv4,
+			recordTypes:    []string{constants.AAAA},
+		},
+		"ip_missing": {
+			previousRecord: makeRecord(ipversion.IP4or6, "::1"),
+			record:         makeRecord(ipversion.IP4or6, "::1"),
+			recordTypes:    []string{constants.AAAA},
+		},
+		"ip_same_family": {
+			previousRecord: makeRecord(ipversion.IP4or6, "1.2.3.5"),
+			record:         makeRecord(ipversion.IP4or6, "1.2.3.5", "1.2.3.4"),
+			ip:             ipv4,
+		},
+		"ip_family_changed": {
+			previousRecord: makeRecord(ipversion.IP4or6, "1.2.3.4"),
+			record:         makeRecord(ipversion.IP4or6, "1.2.3.4", "::1"),
+			ip:             ipv6,
+			recordTypes:    []string{constants.A},
+		},
+		"ip_family_changed_update_failed": {
+			previousRecord: makeRecord(ipversion.IP4or6, "1.2.3.4"),
+			record:         makeRecord(ipversion.IP4or6, "1.2.3.4"),
+			ip:             ipv6,
+		},
+		"dual_stack_available": {
+			previousRecord: makeDualStackRecord("1.2.3.4", "::1"),
+			record:         makeDualStackRecord("1.2.3.4", "::1"),
+			ipv4:           ipv4,
+			ipv6:           ipv6,
+		},
+		"dual_stack_ipv6_missing": {
+			previousRecord: makeDualStackRecord("1.2.3.4", "::1"),
+			record:         makeDualStackRecord("1.2.3.4", "::1"),
+			ipv4:           ipv4,
+			recordTypes:    []string{constants.AAAA},
+		},
+		"dual_stack_both_missing": {
+			previousRecord: makeDualStackRecord("1.2.3.4", "::1"),
+			record:         makeDualStackRecord("1.2.3.4", "::1"),
+			recordTypes:    []string{constants.A, constants.AAAA},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recordTypes := getStaleRecordTypes(testCase.previousRecord, testCase.record,
+				testCase.ip, testCase.ipv4, testCase.ipv6)
+
+			assert.Equal(t, testCase.recordTypes, recordTypes)
+		})
+	}
+}
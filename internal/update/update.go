@@ -6,30 +6,60 @@ import (
 	"fmt"
 	"net/http"
 	"net/netip"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/provider"
+	providerconstants "github.com/qdm12/ddns-updater/internal/provider/constants"
 	settingserrors "github.com/qdm12/ddns-updater/internal/provider/errors"
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
 type Updater struct {
 	db             Database
-	client         *http.Client
+	clients        map[clientKey]*http.Client
+	rateLimiter    RateLimiter
 	shoutrrrClient ShoutrrrClient
 	logger         DebugLogger
 	timeNow        func() time.Time
 }
 
-func NewUpdater(db Database, client *http.Client, shoutrrrClient ShoutrrrClient,
-	logger DebugLogger, timeNow func() time.Time, debugEnabled bool,
+func NewUpdater(db Database, client *http.Client, uplinkClients map[string]*http.Client,
+	rateLimiter RateLimiter, shoutrrrClient ShoutrrrClient, logger DebugLogger,
+	timeNow func() time.Time, debugEnabled bool,
 ) *Updater {
+	baseClients := make(map[string]*http.Client, len(uplinkClients)+1)
+	baseClients[""] = client
+	for name, uplinkClient := range uplinkClients {
+		baseClients[name] = uplinkClient
+	}
+
+	// Each client is pinned to tcp4 and tcp6 for records with the
+	// IP version ipv4 and ipv6, and left unpinned for other records.
+	networks := []string{"", "tcp4", "tcp6"}
+	clients := make(map[clientKey]*http.Client, len(baseClients)*len(networks))
+	for uplinkName, baseClient := range baseClients {
+		for _, network := range networks {
+			clients[clientKey{uplinkName: uplinkName, network: network}] = pinNetwork(baseClient, network)
+		}
+	}
 	if debugEnabled {
-		client = makeLogClient(client, logger)
+		// Wrap the clients once pinned, since pinning requires
+		// the client transport to be unmodified.
+		for key, client := range clients {
+			clients[key] = makeLogClient(client, logger)
+		}
 	}
+
 	return &Updater{
 		db:             db,
-		client:         client,
+		clients:        clients,
+		rateLimiter:    rateLimiter,
 		shoutrrrClient: shoutrrrClient,
 		logger:         logger,
 		timeNow:        timeNow,
@@ -37,42 +67,452 @@ func NewUpdater(db Database, client *http.Client, shoutrrrClient ShoutrrrClient,
 }
 
 func (u *Updater) Update(ctx context.Context, id uint, ip netip.Addr) (err error) {
-	record, err := u.db.Select(id)
+	record, newIP, err := u.update(ctx, id, ip)
 	if err != nil {
 		return err
 	}
-	record.Time = u.timeNow()
-	record.Status = constants.UPDATING
-	err = u.db.Update(id, record)
+	record.Status = constants.SUCCESS
+	record.Message = "changed to " + ip.String()
+	record.StaleRecordTypes = removeRecordTypeOf(record.StaleRecordTypes, newIP)
+	record.History = append(record.History, models.HistoryEvent{
+		IP:   newIP,
+		Time: u.timeNow(),
+	})
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record) // persists some data if needed (i.e new IP)
+}
+
+// UpdateDualStack updates both the A and AAAA records of a dual-stack
+// record, in a single API call if its provider supports it. Either
+// of ipv4 and ipv6 can be invalid, in which case its record is not updated.
+func (u *Updater) UpdateDualStack(ctx context.Context, id uint,
+	ipv4, ipv6 netip.Addr,
+) (err error) {
+	var newIPs []netip.Addr
+	record, err := u.apply(ctx, id, func(ctx context.Context, record records.Record) (err error) {
+		newIPs, err = u.updateIPFamilies(ctx, record, ipv4, ipv6)
+		return err
+	})
 	if err != nil {
 		return err
 	}
-	record.Status = constants.FAIL
-	newIP, err := record.Provider.Update(ctx, u.client, ip)
+	record.Status = constants.SUCCESS
+	record.Message = "changed to " + ipsToString(newIPs)
+	now := u.timeNow()
+	for _, newIP := range newIPs {
+		record.StaleRecordTypes = removeRecordTypeOf(record.StaleRecordTypes, newIP)
+		record.History = append(record.History, models.HistoryEvent{
+			IP:   newIP,
+			Time: now,
+		})
+	}
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+// updateIPFamilies updates the A record with ipv4 and the AAAA record with ipv6,
+// in a single API call if the provider supports it. Either of ipv4 and ipv6
+// can be invalid, in which case its record is not updated.
+func (u *Updater) updateIPFamilies(ctx context.Context, record records.Record,
+	ipv4, ipv6 netip.Addr,
+) (newIPs []netip.Addr, err error) {
+	dualStackUpdater, ok := record.Provider.(provider.DualStackUpdater)
+	if ok && ipv4.IsValid() && ipv6.IsValid() {
+		client := u.clientFor(record.Settings, "")
+		newIPv4, newIPv6, err := dualStackUpdater.UpdateDualStack(ctx, client, ipv4, ipv6)
+		if err != nil {
+			return nil, err
+		}
+		return []netip.Addr{newIPv4, newIPv6}, nil
+	}
+
+	for _, ip := range []netip.Addr{ipv4, ipv6} {
+		if !ip.IsValid() {
+			continue
+		}
+		client := u.clientFor(record.Settings, ipToNetwork(ip))
+		newIP, err := record.Provider.Update(ctx, client, ip)
+		if err != nil {
+			return nil, fmt.Errorf("updating %s record: %w", ipToRecordType(ip), err)
+		}
+		newIPs = append(newIPs, newIP)
+	}
+	return newIPs, nil
+}
+
+// UpdateAuto updates the record without specifying its IP addresses,
+// letting its provider detect them from the source address of the
+// update requests. Each request is sent over IPv4 or IPv6 to match
+// the IP version of the record, and both are sent for dual-stack records.
+func (u *Updater) UpdateAuto(ctx context.Context, id uint) (err error) {
+	var newIPs []netip.Addr
+	record, err := u.apply(ctx, id, func(ctx context.Context, record records.Record) (err error) {
+		newIPs, err = u.updateAuto(ctx, record)
+		return err
+	})
 	if err != nil {
-		record.Message = err.Error()
-		if errors.Is(err, settingserrors.ErrBannedAbuse) {
-			lastBan := time.Unix(u.timeNow().Unix(), 0)
-			record.LastBan = &lastBan
-			domainName := record.Provider.BuildDomainName()
-			message := domainName + ": " + record.Message +
-				", no more updates will be attempted for an hour"
-			u.shoutrrrClient.Notify(message)
-			err = fmt.Errorf("%w: for domain %s, no more update will be attempted for 1h", err, domainName)
-		} else {
-			record.LastBan = nil // clear a previous ban
+		return err
+	}
+	record.Status = constants.SUCCESS
+	record.Message = "detected as " + ipsToString(newIPs)
+	if len(newIPs) == 0 {
+		record.Message = "updated with the IP address detected by the provider"
+	}
+	appendHistory(&record, newIPs, u.timeNow())
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+var ErrAutoIPUnsupported = errors.New("provider does not support detecting the IP address")
+
+func (u *Updater) updateAuto(ctx context.Context, record records.Record) (
+	newIPs []netip.Addr, err error,
+) {
+	autoUpdater, ok := record.Provider.(provider.AutoUpdater)
+	if !ok {
+		return nil, fmt.Errorf("%w", ErrAutoIPUnsupported)
+	}
+
+	networks := []string{ipVersionToNetwork(record.Provider.IPVersion())}
+	if record.Settings.DualStack {
+		networks = []string{"tcp4", "tcp6"}
+	}
+
+	for _, network := range networks {
+		client := u.clientFor(record.Settings, network)
+		newIP, err := autoUpdater.UpdateAuto(ctx, client)
+		if err != nil {
+			if network != "" {
+				err = fmt.Errorf("updating over %s: %w", network, err)
+			}
+			return nil, err
 		}
-		if updateErr := u.db.Update(id, record); updateErr != nil {
-			return fmt.Errorf("%w (with database update error: %w)", err, updateErr)
+		if newIP.IsValid() { // not reported by all providers
+			newIPs = append(newIPs, newIP)
 		}
+	}
+	return newIPs, nil
+}
+
+// Failover points the record at the backup IP addresses given, after
+// its primary target was found unhealthy because of healthErr.
+func (u *Updater) Failover(ctx context.Context, id uint, backupIPs []netip.Addr,
+	healthErr error,
+) (err error) {
+	var newIPs []netip.Addr
+	record, err := u.apply(ctx, id, func(ctx context.Context, record records.Record) (err error) {
+		ipv4, ipv6 := splitIPFamilies(backupIPs)
+		newIPs, err = u.updateIPFamilies(ctx, record, ipv4, ipv6)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	record.Status = constants.FAILEDOVER
+	record.Message = "switched to backup " + ipsToString(newIPs) + " since primary target is unhealthy: " +
+		healthErr.Error()
+	record.FailedOver = true
+	appendHistory(&record, newIPs, u.timeNow())
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+// Recover marks the record as no longer failed over after its primary
+// target recovered, so that it gets updated to the current public IP
+// addresses, which may have changed while failed over.
+func (u *Updater) Recover(id uint) (err error) {
+	record, err := u.db.Select(id)
+	if err != nil {
+		return err
+	}
+	record.Status = constants.UNSET
+	record.Message = "switching back from backup since primary target recovered"
+	record.Time = u.timeNow()
+	record.FailedOver = false
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+// appendHistory appends history events to the record for the IP addresses
+// given which differ from its current IP address, or from its current
+// IP address of the same IP family for dual-stack records.
+func appendHistory(record *records.Record, ips []netip.Addr, now time.Time) {
+	for _, ip := range ips {
+		currentIP := record.History.GetCurrentIP()
+		switch {
+		case !record.Settings.DualStack:
+		case ip.Is4():
+			currentIP = record.History.GetCurrentIP4()
+		default:
+			currentIP = record.History.GetCurrentIP6()
+		}
+		if ip.Compare(currentIP) == 0 {
+			continue
+		}
+		record.History = append(record.History, models.HistoryEvent{
+			IP:   ip,
+			Time: now,
+		})
+	}
+}
+
+var (
+	ErrPinIPVersionMismatch = errors.New("pinned IP address does not match the record IP version")
+	ErrPinContentRecord     = errors.New("content records cannot be pinned")
+)
+
+// Pin sets the record to the IP address given and pins it until the
+// time given, during which the record is not updated.
+func (u *Updater) Pin(ctx context.Context, id uint, ip netip.Addr, until time.Time) (err error) {
+	record, err := u.db.Select(id)
+	if err != nil {
+		return err
+	}
+	ipVersion := record.Provider.IPVersion()
+	switch {
+	case record.Settings.RecordType != "":
+		return fmt.Errorf("%w: %s", ErrPinContentRecord, recordToLogString(record))
+	case (ipVersion == ipversion.IP4 && !ip.Is4()) || (ipVersion == ipversion.IP6 && !ip.Is6()):
+		return fmt.Errorf("%w: %s for IP version %s", ErrPinIPVersionMismatch, ip, ipVersion)
+	}
+
+	var newIP netip.Addr
+	record, err = u.apply(ctx, id, func(ctx context.Context, record records.Record) (err error) {
+		client := u.clientFor(record.Settings, ipToNetwork(ip))
+		newIP, err = record.Provider.Update(ctx, client, ip)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	record.Status = constants.PINNED
+	record.Message = "pinned to " + newIP.String() + " until " + until.Format(time.DateTime)
+	record.Pin = &models.Pin{IP: newIP, Until: until}
+	appendHistory(&record, []netip.Addr{newIP}, u.timeNow())
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+// Unpin removes the pin of the record for the reason given, so that the
+// record gets updated again as usual during the next update.
+func (u *Updater) Unpin(id uint, reason string) (err error) {
+	record, err := u.db.Select(id)
+	if err != nil {
+		return err
+	} else if record.Pin == nil {
+		return nil
+	}
+	record.Pin = nil
+	record.Status = constants.UNSET
+	record.Message = reason
+	record.Time = u.timeNow()
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+// Repair sets back the record to the IP address it was last updated with,
+// after its value got modified outside of the program to recordIPs.
+func (u *Updater) Repair(ctx context.Context, id uint, ip netip.Addr,
+	recordIPs []netip.Addr,
+) (err error) {
+	record, _, err := u.update(ctx, id, ip)
+	if err != nil {
+		return err
+	}
+	record.Status = constants.DRIFTED
+	record.StaleRecordTypes = removeRecordTypeOf(record.StaleRecordTypes, ip)
+	record.Message = "was " + ipsToString(recordIPs) + ", restored to " + ip.String()
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+// ClearStale deletes the record of the given type, or sets it to
+// its fallback IP address, depending on the record stale policy.
+// This is used when the public IP address of the family of the
+// record type can no longer be obtained.
+func (u *Updater) ClearStale(ctx context.Context, id uint, recordType string) (err error) {
+	var message string
+	record, err := u.apply(ctx, id, func(ctx context.Context, record records.Record) (err error) {
+		message, err = u.clearStale(ctx, record, recordType)
+		return err
+	})
+	if err != nil {
 		return err
 	}
 	record.Status = constants.SUCCESS
-	record.Message = "changed to " + ip.String()
-	record.History = append(record.History, models.HistoryEvent{
-		IP:   newIP,
-		Time: u.timeNow(),
+	record.Message = message
+	record.StaleRecordTypes = append(record.StaleRecordTypes, recordType)
+	u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	return u.db.Update(id, record)
+}
+
+var ErrStalePolicyUnknown = errors.New("stale policy is unknown")
+
+func (u *Updater) clearStale(ctx context.Context, record records.Record,
+	recordType string,
+) (message string, err error) {
+	// The client is not pinned to the network of the stale record
+	// type, since its IP family is likely no longer available.
+	client := u.clientFor(record.Settings, "")
+	ipFamily := "IPv4"
+	fallbackIP := record.Settings.FallbackIPv4
+	if recordType == providerconstants.AAAA {
+		ipFamily = "IPv6"
+		fallbackIP = record.Settings.FallbackIPv6
+	}
+
+	switch record.Settings.StalePolicy {
+	case records.StalePolicyDelete:
+		deleter, ok := record.Provider.(provider.RecordDeleter)
+		if !ok {
+			return "", fmt.Errorf("%w", provider.ErrRecordDeletionUnsupported)
+		}
+		err = deleter.DeleteRecord(ctx, client, recordType)
+		if err != nil {
+			return "", fmt.Errorf("deleting %s record: %w", recordType, err)
+		}
+		return "deleted " + recordType + " record since no " + ipFamily + " address is available", nil
+	case records.StalePolicyFallback:
+		_, err = record.Provider.Update(ctx, client, fallbackIP)
+		if err != nil {
+			return "", fmt.Errorf("setting %s record to fallback %s: %w", recordType, fallbackIP, err)
+		}
+		return "set " + recordType + " record to fallback " + fallbackIP.String() +
+			" since no " + ipFamily + " address is available", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrStalePolicyUnknown, record.Settings.StalePolicy)
+	}
+}
+
+// update updates the record with the given IP address using its provider,
+// and sets and notifies the failure status in case of error.
+func (u *Updater) update(ctx context.Context, id uint, ip netip.Addr) (
+	record records.Record, newIP netip.Addr, err error,
+) {
+	record, err = u.apply(ctx, id, func(ctx context.Context, record records.Record) (err error) {
+		network := ipVersionToNetwork(record.Provider.IPVersion())
+		newIP, err = record.Provider.Update(ctx, u.clientFor(record.Settings, network), ip)
+		return err
 	})
-	u.shoutrrrClient.Notify(record.Provider.BuildDomainName() + " " + record.Message)
-	return u.db.Update(id, record) // persists some data if needed (i.e new IP)
+	return record, newIP, err
+}
+
+// apply runs the action given on the record, and sets and
+// notifies the failure status in case of error. If the provider
+// account of the record is rate limited, the record is left
+// unchanged and an error wrapping ratelimit.ErrLimited is returned.
+func (u *Updater) apply(ctx context.Context, id uint,
+	action func(ctx context.Context, record records.Record) error,
+) (record records.Record, err error) {
+	batch, err := u.applyBatch(ctx, []uint{id}, func(ctx context.Context, batch []records.Record) error {
+		return action(ctx, batch[0])
+	})
+	return batch[0], err
+}
+
+// applyBatch runs the action given on the records with the identifiers
+// given, which are of the same provider account, and sets and notifies
+// their failure status in case of error. If the provider account is
+// rate limited, the records are left unchanged and an error wrapping
+// ratelimit.ErrLimited is returned.
+func (u *Updater) applyBatch(ctx context.Context, ids []uint,
+	action func(ctx context.Context, batch []records.Record) error,
+) (batch []records.Record, err error) {
+	batch = make([]records.Record, len(ids))
+	for i, id := range ids {
+		batch[i], err = u.db.Select(id)
+		if err != nil {
+			return batch, err
+		}
+	}
+	account, limit := batch[0].Settings.Account, batch[0].Settings.RateLimit
+	err = u.rateLimiter.Check(account, limit)
+	if err != nil {
+		return batch, fmt.Errorf("%s: %w", recordsToLogString(batch), err)
+	}
+	previousBatch := slices.Clone(batch)
+	for i, id := range ids {
+		batch[i].Time = u.timeNow()
+		batch[i].Status = constants.UPDATING
+		err = u.db.Update(id, batch[i])
+		if err != nil {
+			return batch, err
+		}
+		batch[i].Status = constants.FAIL
+	}
+	err = action(ctx, batch)
+	if errors.Is(err, ratelimit.ErrLimited) {
+		// the budget got exhausted during the action, for example
+		// by another record of the same provider account.
+		err = fmt.Errorf("%s: %w", recordsToLogString(batch), err)
+		for i, id := range ids {
+			if updateErr := u.db.Update(id, previousBatch[i]); updateErr != nil {
+				return previousBatch, fmt.Errorf("%w (with database update error: %w)", err, updateErr)
+			}
+		}
+		return previousBatch, err
+	} else if err != nil {
+		actionErr := err
+		if errors.Is(err, settingserrors.ErrBannedAbuse) {
+			domainNames := make([]string, len(batch))
+			for i, record := range batch {
+				domainNames[i] = record.Provider.BuildDomainName()
+			}
+			err = fmt.Errorf("%w: for domain %s, no more update will be attempted for 1h",
+				err, strings.Join(domainNames, ", "))
+		}
+		if len(batch) > 1 {
+			err = fmt.Errorf("%s: %w", recordsToLogString(batch), err)
+		}
+		for i, id := range ids {
+			var updateErr error
+			batch[i], updateErr = u.fail(id, batch[i], actionErr)
+			if updateErr != nil {
+				err = fmt.Errorf("%w (with database update error: %w)", err, updateErr)
+			}
+		}
+		return batch, err
+	}
+	return batch, nil
+}
+
+// fail sets, notifies and stores the failure status of the record for
+// the error given, and returns an error if the record cannot be stored.
+func (u *Updater) fail(id uint, record records.Record, err error) (
+	failedRecord records.Record, updateErr error,
+) {
+	record.Status = constants.FAIL
+	record.Message = err.Error()
+	if errors.Is(err, settingserrors.ErrBannedAbuse) {
+		lastBan := time.Unix(u.timeNow().Unix(), 0)
+		record.LastBan = &lastBan
+		message := record.Message + ", no more updates will be attempted for an hour"
+		u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, message)
+	} else {
+		record.LastBan = nil // clear a previous ban
+		u.shoutrrrClient.NotifyRecord(recordKey(record), recordToLogString(record), record.Status, record.Message)
+	}
+	return record, u.db.Update(id, record)
+}
+
+// ReadRecord returns the IP addresses currently set for the record
+// of the given record reader, using the provider API over the given
+// uplink, which is empty for the default network path.
+func (u *Updater) ReadRecord(ctx context.Context, reader provider.RecordReader,
+	settings records.Settings,
+) (ips []netip.Addr, err error) {
+	return reader.GetRecord(ctx, u.clientFor(settings, ""))
+}
+
+// clientFor returns the HTTP client to use for the uplink of the record
+// settings given, only dialing over the network given if it is tcp4 or
+// tcp6. The default network path is used if the uplink name is empty
+// or unknown. The client is rate limited for the provider account of
+// the record.
+func (u *Updater) clientFor(settings records.Settings, network string) *http.Client {
+	client, ok := u.clients[clientKey{uplinkName: settings.Uplink, network: network}]
+	if !ok {
+		client = u.clients[clientKey{network: network}]
+	}
+	return u.rateLimiter.Client(client, settings.Account, settings.RateLimit)
 }
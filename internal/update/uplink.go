@@ -0,0 +1,65 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+var ErrUplinkNotFound = errors.New("uplink not found")
+
+func (s *Service) getIPGetter(uplinkName string) (ipGetter PublicIPFetcher, err error) {
+	if uplinkName == "" {
+		return s.ipGetter, nil
+	}
+	ipGetter, ok := s.uplinkIPGetters[uplinkName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUplinkNotFound, uplinkName)
+	}
+	return ipGetter, nil
+}
+
+// getUplinkNames returns the unique uplink names used by the records,
+// where the empty default uplink name is first if used.
+// Multi-value and follow records are ignored since they are updated separately.
+func getUplinkNames(records []librecords.Record) (uplinkNames []string) {
+	for _, record := range records {
+		if record.Settings.MultiIP != nil || record.Settings.Follow != "" {
+			continue
+		}
+		if !slices.Contains(uplinkNames, record.Settings.Uplink) {
+			uplinkNames = append(uplinkNames, record.Settings.Uplink)
+		}
+	}
+	slices.Sort(uplinkNames)
+	return uplinkNames
+}
+
+// filterUplinkRecordIDs returns the identifiers of the records
+// amongst the record identifiers given using the uplink given,
+// excluding multi-value and follow records.
+func filterUplinkRecordIDs(records []librecords.Record, recordIDs map[uint]struct{},
+	uplinkName string,
+) (uplinkRecordIDs map[uint]struct{}) {
+	uplinkRecordIDs = make(map[uint]struct{}, len(recordIDs))
+	for id := range recordIDs {
+		settings := records[id].Settings
+		if settings.MultiIP == nil && settings.Follow == "" && settings.Uplink == uplinkName {
+			uplinkRecordIDs[id] = struct{}{}
+		}
+	}
+	return uplinkRecordIDs
+}
+
+func selectRecords(records []librecords.Record, recordIDs map[uint]struct{}) (
+	selected []librecords.Record,
+) {
+	for i, record := range records {
+		if _, ok := recordIDs[uint(i)]; ok {
+			selected = append(selected, record)
+		}
+	}
+	return selected
+}
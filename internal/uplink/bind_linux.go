@@ -0,0 +1,25 @@
+package uplink
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func validateInterface(string) (err error) { return nil }
+
+// bindToInterface returns a dialer control function binding
+// the socket to the network interface given.
+func bindToInterface(name string) func(network, address string, rawConn syscall.RawConn) error {
+	return func(_, _ string, rawConn syscall.RawConn) (err error) {
+		var sockErr error
+		err = rawConn.Control(func(fd uintptr) {
+			sockErr = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, name)
+		})
+		if err != nil {
+			return fmt.Errorf("controlling raw connection: %w", err)
+		} else if sockErr != nil {
+			return fmt.Errorf("binding to interface %s: %w", name, sockErr)
+		}
+		return nil
+	}
+}
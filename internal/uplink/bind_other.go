@@ -0,0 +1,24 @@
+//go:build !linux
+
+package uplink
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+var ErrInterfaceNotSupported = errors.New("binding to an interface is only supported on Linux")
+
+func validateInterface(name string) (err error) {
+	if name == "" {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInterfaceNotSupported, name)
+}
+
+func bindToInterface(name string) func(network, address string, rawConn syscall.RawConn) error {
+	return func(string, string, syscall.RawConn) error {
+		return validateInterface(name)
+	}
+}
@@ -0,0 +1,16 @@
+package uplink
+
+import (
+	"net/http"
+	"time"
+)
+
+// HTTPClient returns an HTTP client dialing connections over the uplink.
+func (u Uplink) HTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.DialContext = u.Dialer().DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
@@ -0,0 +1,61 @@
+// Package uplink defines uplinks, which are network paths to the Internet
+// bound to a local source address and/or network interface, to support
+// hosts with multiple WAN connections.
+package uplink
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// Uplink is a named network path identified by its local source
+// address and/or its network interface.
+type Uplink struct {
+	Name string `json:"name"`
+	// Interface is the network interface name to bind to,
+	// and is only supported on Linux.
+	Interface string `json:"interface,omitempty"`
+	// Address is the local source IP address to bind to.
+	Address netip.Addr `json:"address,omitempty"`
+}
+
+var (
+	ErrNameNotSet               = errors.New("name is not set")
+	ErrInterfaceOrAddressNotSet = errors.New("interface or address must be set")
+)
+
+func (u Uplink) Validate() (err error) {
+	switch {
+	case u.Name == "":
+		return fmt.Errorf("%w", ErrNameNotSet)
+	case u.Interface == "" && !u.Address.IsValid():
+		return fmt.Errorf("%w: for uplink %s", ErrInterfaceOrAddressNotSet, u.Name)
+	}
+	return validateInterface(u.Interface)
+}
+
+func (u Uplink) String() string {
+	switch {
+	case u.Interface == "":
+		return fmt.Sprintf("%s (address %s)", u.Name, u.Address)
+	case !u.Address.IsValid():
+		return fmt.Sprintf("%s (interface %s)", u.Name, u.Interface)
+	default:
+		return fmt.Sprintf("%s (interface %s, address %s)", u.Name, u.Interface, u.Address)
+	}
+}
+
+// Dialer returns a network dialer bound to the uplink
+// source address and/or network interface.
+func (u Uplink) Dialer() *net.Dialer {
+	dialer := &net.Dialer{}
+	if u.Address.IsValid() {
+		dialer.LocalAddr = &net.TCPAddr{IP: u.Address.AsSlice()}
+	}
+	if u.Interface != "" {
+		dialer.Control = bindToInterface(u.Interface)
+	}
+	return dialer
+}
@@ -1,10 +1,14 @@
 package dns
 
-import "time"
+import (
+	"net"
+	"time"
+)
 
 type Fetcher struct {
 	ring    ring
 	timeout time.Duration
+	dialer  *net.Dialer
 }
 
 type ring struct {
@@ -28,5 +32,6 @@ func New(options ...Option) (f *Fetcher, err error) {
 			providers: settings.providers,
 		},
 		timeout: settings.timeout,
+		dialer:  settings.dialer,
 	}, nil
 }
@@ -64,6 +64,11 @@ func (f *Fetcher) ip(ctx context.Context, network string) (
 			ServerName: providerData.TLSName,
 		},
 	}
+	if f.dialer != nil {
+		dialer := *f.dialer
+		dialer.Timeout = f.timeout
+		client.Dialer = &dialer
+	}
 
 	return fetch(ctx, client, network, providerData)
 }
@@ -1,10 +1,14 @@
 package dns
 
-import "time"
+import (
+	"net"
+	"time"
+)
 
 type settings struct {
 	providers []Provider
 	timeout   time.Duration
+	dialer    *net.Dialer
 }
 
 func newDefaultSettings() settings {
@@ -37,3 +41,13 @@ func SetTimeout(timeout time.Duration) Option {
 		return nil
 	}
 }
+
+// SetDialer sets the dialer to use to connect to the DNS servers,
+// for example to bind to a local address. Its timeout is overridden
+// by the timeout set with SetTimeout.
+func SetDialer(dialer *net.Dialer) Option {
+	return func(s *settings) (err error) {
+		s.dialer = dialer
+		return nil
+	}
+}
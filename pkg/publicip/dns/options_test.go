@@ -2,6 +2,7 @@ package dns
 
 import (
 	"errors"
+	"net"
 	"testing"
 	"time"
 
@@ -92,3 +93,19 @@ func Test_SetTimeout(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, expectedSettings, initialSettings)
 }
+
+func Test_SetDialer(t *testing.T) {
+	t.Parallel()
+
+	dialer := &net.Dialer{}
+	initialSettings := settings{}
+	expectedSettings := settings{
+		dialer: dialer,
+	}
+
+	option := SetDialer(dialer)
+	err := option(&initialSettings)
+
+	require.NoError(t, err)
+	assert.Equal(t, expectedSettings, initialSettings)
+}